@@ -9,6 +9,8 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	// quiet hours are kept in the time zone of the user
+	_ "time/tzdata"
 )
 
 func main() {
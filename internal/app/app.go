@@ -3,24 +3,52 @@ package app
 import (
 	brokerapp "dussh/internal/app/broker"
 	cacheapp "dussh/internal/app/cache"
+	digestapp "dussh/internal/app/digest"
 	httpapp "dussh/internal/app/http"
+	inboxapp "dussh/internal/app/inbox"
+	outboxapp "dussh/internal/app/outbox"
 	rbacapp "dussh/internal/app/rbac"
+	reminderapp "dussh/internal/app/reminder"
 	repoapp "dussh/internal/app/repo"
-	"dussh/internal/broker/rabbit/publisher"
+	telegramapp "dussh/internal/app/telegram"
+	webhookapp "dussh/internal/app/webhook"
 	"dussh/internal/config"
-	"dussh/internal/domain/models"
+	auditapi "dussh/internal/services/audit/api/v1"
+	auditservice "dussh/internal/services/audit/service"
 	authapi "dussh/internal/services/auth/api/v1"
 	authservice "dussh/internal/services/auth/service"
 	courseapi "dussh/internal/services/course/api/v1"
 	courseservice "dussh/internal/services/course/service"
+	deadletterapi "dussh/internal/services/deadletter/api/v1"
+	deadletterservice "dussh/internal/services/deadletter/service"
+	inboxapi "dussh/internal/services/inbox/api/v1"
+	inboxservice "dussh/internal/services/inbox/service"
+	"dussh/internal/services/mailsink"
+	mailsinkapi "dussh/internal/services/mailsink/api/v1"
 	"dussh/internal/services/notification"
+	notificationapi "dussh/internal/services/notification/api/v1"
+	preferenceapi "dussh/internal/services/preference/api/v1"
+	preferenceservice "dussh/internal/services/preference/service"
+	telegramapi "dussh/internal/services/telegram/api/v1"
+	telegramservice "dussh/internal/services/telegram/service"
+	templateapi "dussh/internal/services/template/api/v1"
+	templateservice "dussh/internal/services/template/service"
 	userapi "dussh/internal/services/user/api/v1"
 	userservice "dussh/internal/services/user/service"
+	webhookapi "dussh/internal/services/webhook/api/v1"
+	webhookservice "dussh/internal/services/webhook/service"
 	"dussh/pkg/jwt"
 	"dussh/pkg/notify"
 	"dussh/pkg/notify/provider/email"
+	"dussh/pkg/notify/provider/inapp"
+	sink "dussh/pkg/notify/provider/mailsink"
+	"dussh/pkg/notify/provider/sms"
+	"dussh/pkg/notify/provider/telegram"
+	"errors"
+	"fmt"
 	"go.uber.org/zap"
 	"golang.org/x/net/context"
+	"net/http"
 )
 
 type App struct {
@@ -29,12 +57,20 @@ type App struct {
 	cache      *cacheapp.App
 	repo       *repoapp.App
 	rbac       *rbacapp.App
+	outbox     *outboxapp.App
+	telegram   *telegramapp.App
+	webhook    *webhookapp.App
+	reminder   *reminderapp.App
+	digest     *digestapp.App
+	inbox      *inboxapp.App
+	mailer     *email.NotificationProvider
 }
 
 func New(ctx context.Context, log *zap.Logger, cfg config.Config) *App {
 	repoApp := repoapp.New(ctx, &cfg.DB, log)
 	cacheApp := cacheapp.New(ctx, &cfg.Redis, log)
 	rbacApp := rbacapp.New(log)
+	brokerApp := brokerapp.New(ctx, &cfg, repoApp.PGSQL(), cacheApp.Redis(), log)
 
 	jwtManager, err := jwt.NewManager(
 		cfg.Auth.SecretKey,
@@ -45,33 +81,118 @@ func New(ctx context.Context, log *zap.Logger, cfg config.Config) *App {
 		panic(err)
 	}
 
+	auditSvc := auditservice.NewAuditService(repoApp.PGSQL(), log)
+	auditAPI := auditapi.NewAuditAPI(auditSvc, log)
+
 	authService := authservice.NewAuthService(
 		repoApp.PGSQL(),
 		jwtManager,
 		cacheApp.Redis(),
+		auditSvc,
 		log,
 	)
 	authAPI := authapi.NewAuthAPI(authService, log)
 
-	userSvc := userservice.NewUserService(repoApp.PGSQL(), log)
+	userSvc := userservice.NewUserService(repoApp.PGSQL(), auditSvc, log)
 	userAPI := userapi.NewUserAPI(userSvc, log)
 
-	ePublisher := publisher.NewEventPublisher[models.EnrollmentEvent](cfg.RabbitMQ)
-	courseSvc := courseservice.NewCourseService(repoApp.PGSQL(), ePublisher, log)
+	courseSvc := courseservice.NewCourseService(repoApp.PGSQL(), auditSvc, log)
 	courseAPI := courseapi.NewCourseAPI(courseSvc, log)
 
-	emailCfg := notify.Config{Email: &email.NotificationProvider{
-		From:      cfg.Notify.EmailProvider.From,
-		Username:  cfg.Notify.EmailProvider.Username,
-		Password:  cfg.Notify.EmailProvider.Password,
-		Host:      cfg.Notify.EmailProvider.Host,
-		Port:      cfg.Notify.EmailProvider.Port,
-		TLSEnable: cfg.Notify.EmailProvider.TLSEnable,
-	}}
-	notificationSvc := notification.NewService(emailCfg, courseSvc, userSvc)
+	deadLetterSvc := deadletterservice.NewDeadLetterService(brokerApp.DeadLetters(), log)
+	deadLetterAPI := deadletterapi.NewDeadLetterAPI(deadLetterSvc, log)
+
+	webhookSvc := webhookservice.NewWebhookService(repoApp.PGSQL(), log)
+	webhookAPI := webhookapi.NewWebhookAPI(webhookSvc, log)
+
+	telegramSvc := telegramservice.NewTelegramService(
+		repoApp.PGSQL(),
+		cacheApp.Redis(),
+		cfg.Notify.TelegramProvider,
+		log,
+	)
+	telegramAPI := telegramapi.NewTelegramAPI(telegramSvc, log)
+
+	preferenceSvc := preferenceservice.NewPreferenceService(repoApp.PGSQL(), log)
+	preferenceAPI := preferenceapi.NewPreferenceAPI(preferenceSvc, log)
+
+	templateSvc := templateservice.NewTemplateService(repoApp.PGSQL(), log)
+	templateAPI := templateapi.NewTemplateAPI(templateSvc, log)
+
+	inboxSvc := inboxservice.NewInboxService(repoApp.PGSQL(), cacheApp.Redis(), log)
+	inboxAPI := inboxapi.NewInboxAPI(inboxSvc, log)
+
+	mailSink := mustNewMailSink(&cfg)
+	var mailSinkAPI mailsink.Api
+	if mailSink != nil && cfg.Notify.EmailProvider.SinkUI {
+		mailSinkAPI = mailsinkapi.NewMailSinkAPI(mailSink, log)
+	}
+
+	notifyCfg := notify.Config{
+		MailSink: mailSink,
+		Email: &email.NotificationProvider{
+			From:               cfg.Notify.EmailProvider.From,
+			Username:           cfg.Notify.EmailProvider.Username,
+			Password:           cfg.Notify.EmailProvider.Password,
+			Host:               cfg.Notify.EmailProvider.Host,
+			Port:               cfg.Notify.EmailProvider.Port,
+			Security:           email.Security(cfg.Notify.EmailProvider.Security),
+			InsecureSkipVerify: cfg.Notify.EmailProvider.InsecureSkipVerify,
+			PoolSize:           cfg.Notify.EmailProvider.PoolSize,
+			IdleTimeout:        cfg.Notify.EmailProvider.IdleTimeout,
+		},
+		Telegram: &telegram.NotificationProvider{
+			Token:   cfg.Notify.TelegramProvider.Token,
+			BaseURL: cfg.Notify.TelegramProvider.BaseURL,
+		},
+		SMS: &sms.NotificationProvider{
+			From:     cfg.Notify.SMSProvider.From,
+			Gateway:  mustNewSMSGateway(&cfg, log),
+			MaxParts: cfg.Notify.SMSProvider.MaxParts,
+		},
+		InApp: &inapp.NotificationProvider{Inbox: inboxSvc},
+	}
+	notificationSvc := notification.NewService(
+		notifyCfg,
+		cfg.Notify.SMSProvider,
+		courseSvc,
+		userSvc,
+		preferenceSvc,
+		templateSvc,
+		repoApp.PGSQL(),
+		cacheApp.Redis(),
+		log,
+	)
+
+	notificationAPI := notificationapi.NewNotificationAPI(notificationSvc, log)
 
-	brokerApp := brokerapp.New(ctx, cfg.RabbitMQ, notificationSvc, log)
-	httpApp := httpapp.New(ctx, &cfg, authAPI, userAPI, courseAPI, rbacApp, log)
+	brokerApp.HandleNotificationEvents(notificationSvc)
+
+	outboxApp := outboxapp.New(ctx, &cfg, brokerApp.Publisher(), repoApp.PGSQL(), log)
+	telegramApp := telegramapp.New(cfg.Notify.TelegramProvider, telegramSvc, log)
+	webhookApp := webhookapp.New(&cfg, repoApp.PGSQL(), log)
+	reminderApp := reminderapp.New(&cfg, repoApp.PGSQL(), cacheApp.Redis(), log)
+	digestApp := digestapp.New(&cfg, repoApp.PGSQL(), cacheApp.Redis(), log)
+	inboxApp := inboxapp.New(inboxSvc, log)
+	httpApp := httpapp.New(
+		ctx,
+		&cfg,
+		authAPI,
+		userAPI,
+		courseAPI,
+		auditAPI,
+		deadLetterAPI,
+		telegramAPI,
+		webhookAPI,
+		preferenceAPI,
+		templateAPI,
+		notificationAPI,
+		inboxAPI,
+		mailSinkAPI,
+		rbacApp,
+		cacheApp.Redis(),
+		log,
+	)
 
 	return &App{
 		httpServer: httpApp,
@@ -79,22 +200,119 @@ func New(ctx context.Context, log *zap.Logger, cfg config.Config) *App {
 		cache:      cacheApp,
 		repo:       repoApp,
 		rbac:       rbacApp,
+		outbox:     outboxApp,
+		telegram:   telegramApp,
+		webhook:    webhookApp,
+		reminder:   reminderApp,
+		digest:     digestApp,
+		inbox:      inboxApp,
+		mailer:     notifyCfg.Email,
 	}
 }
 
+// mustNewSMSGateway returns the configured gateway, dev falls back to the
+// fake gateway so a forgotten setting doesn't silently drop sms elsewhere.
+func mustNewSMSGateway(cfg *config.Config, log *zap.Logger) sms.Gateway {
+	smsCfg := cfg.Notify.SMSProvider
+	gateway := smsCfg.Gateway
+	if gateway == "" && cfg.Env == "dev" {
+		gateway = sms.GatewayFake
+	}
+
+	switch gateway {
+	case sms.GatewayHTTP:
+		if smsCfg.URL == "" {
+			panic(fmt.Errorf("sms gateway %q requires a url", gateway))
+		}
+		return &sms.HTTPGateway{
+			URL:    smsCfg.URL,
+			APIKey: smsCfg.APIKey,
+			Client: &http.Client{Timeout: smsCfg.Timeout},
+		}
+	case sms.GatewayFake:
+		log := log.Named("sms.fake")
+		return &sms.FakeGateway{OnSend: func(msg sms.Message) {
+			log.Info("sms sent to fake gateway",
+				zap.String("to", msg.To),
+				zap.String("text", msg.Text),
+				zap.Int("parts", msg.Parts),
+			)
+		}}
+	case "":
+		panic(errors.New("sms gateway is required outside dev"))
+	default:
+		panic(fmt.Errorf("unknown sms gateway %q", gateway))
+	}
+}
+
+// mustNewMailSink returns the provider capturing emails for the file and
+// memory drivers, nil for smtp.
+func mustNewMailSink(cfg *config.Config) *sink.NotificationProvider {
+	emailCfg := cfg.Notify.EmailProvider
+	if emailCfg.SinkUI && cfg.Env != "dev" {
+		panic(errors.New("the mail sink pages are not authenticated and are allowed in dev only"))
+	}
+
+	switch emailCfg.Driver {
+	case email.DriverSMTP:
+		if emailCfg.Host == "" {
+			panic(fmt.Errorf("email driver %q requires a host", emailCfg.Driver))
+		}
+		return nil
+	case sink.DriverFile, sink.DriverMemory:
+		if cfg.Env == "prod" {
+			panic(fmt.Errorf("email driver %q is not allowed in prod", emailCfg.Driver))
+		}
+	default:
+		panic(fmt.Errorf("unknown email driver %q", emailCfg.Driver))
+	}
+
+	mailSink := &sink.NotificationProvider{From: emailCfg.From, Limit: emailCfg.SinkLimit}
+	if emailCfg.Driver == sink.DriverFile {
+		mailSink.Dir = emailCfg.SinkDir
+	}
+
+	return mailSink
+}
+
 func (a *App) Run() {
-	// TODO uncommented
-	//ctx := context.Background()
-	//go a.broker.MustRun(ctx)
+	ctx := context.Background()
+	go a.broker.MustRun(ctx)
+	go a.outbox.MustRun(ctx)
+	go a.telegram.MustRun(ctx)
+	go a.webhook.MustRun(ctx)
+	go a.reminder.MustRun(ctx)
+	go a.digest.MustRun(ctx)
+	go a.inbox.MustRun(ctx)
 	a.httpServer.MustRun()
 }
 
 func (a *App) Shutdown(ctx context.Context) error {
+	if err := a.inbox.Shutdown(ctx); err != nil {
+		return err
+	}
+
 	if err := a.httpServer.Shutdown(ctx); err != nil {
 		return err
 	}
 
-	if err := a.cache.Shutdown(ctx); err != nil {
+	if err := a.reminder.Shutdown(ctx); err != nil {
+		return err
+	}
+
+	if err := a.digest.Shutdown(ctx); err != nil {
+		return err
+	}
+
+	if err := a.webhook.Shutdown(ctx); err != nil {
+		return err
+	}
+
+	if err := a.telegram.Shutdown(ctx); err != nil {
+		return err
+	}
+
+	if err := a.outbox.Shutdown(ctx); err != nil {
 		return err
 	}
 
@@ -102,5 +320,13 @@ func (a *App) Shutdown(ctx context.Context) error {
 		return err
 	}
 
+	if err := a.mailer.Close(); err != nil {
+		return err
+	}
+
+	if err := a.cache.Shutdown(ctx); err != nil {
+		return err
+	}
+
 	return nil
 }
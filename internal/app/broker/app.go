@@ -2,44 +2,140 @@ package broker
 
 import (
 	"context"
-	"dussh/internal/broker/rabbit/consumer"
+	"dussh/internal/broker"
+	"dussh/internal/broker/consumer"
+	"dussh/internal/broker/envelope"
+	"dussh/internal/broker/memory"
+	"dussh/internal/broker/pgqueue"
+	"dussh/internal/broker/rabbit"
+	"dussh/internal/broker/rabbit/deadletter"
+	"dussh/internal/cache/redis"
 	"dussh/internal/config"
 	"dussh/internal/services/notification"
 	"errors"
+	"fmt"
 	"go.uber.org/zap"
 )
 
 type App struct {
-	eventEnrollmentConsumer consumer.Consumer
-	cfg                     config.RabbitMQ
+	publisher   broker.Publisher
+	subscriber  broker.Subscriber
+	deadLetters deadletter.Store
+	close       func(context.Context) error
+	router      *consumer.Router
+	hasHandlers bool
+	cfg         config.RabbitMQ
+	log         *zap.Logger
 }
 
+// New creates the broker backend selected by the broker config option.
 func New(
 	ctx context.Context,
-	cfgRabbitMQ config.RabbitMQ,
-	svc notification.Service,
+	cfg *config.Config,
+	repo pgqueue.Repository,
+	cache redis.Cache,
 	log *zap.Logger,
 ) *App {
-	log.Info("broker app creating")
+	log.Info("broker app creating", zap.String("backend", cfg.Broker))
 
-	eConsumer := consumer.NewEventEnrollmentConsumer(
-		cfgRabbitMQ,
-		svc,
+	a := &App{
+		cfg: cfg.RabbitMQ,
+		log: log,
+	}
+
+	switch cfg.Broker {
+	case broker.BackendRabbitMQ:
+		conn, err := rabbit.NewConnection(cfg.RabbitMQ, log)
+		if err != nil {
+			panic(err)
+		}
+
+		// topology must be declared before anything is published or consumed
+		startupCtx, cancel := context.WithTimeout(ctx, cfg.RabbitMQ.StartupTimeout)
+		defer cancel()
+		if err := conn.WaitConnected(startupCtx); err != nil {
+			panic(fmt.Errorf("rabbitmq is not ready: %w", err))
+		}
+
+		a.publisher = rabbit.NewPublisher(conn, cfg.RabbitMQ)
+		a.subscriber = rabbit.NewSubscriber(conn, cfg.RabbitMQ)
+		a.deadLetters = deadletter.NewStore(conn, cfg.RabbitMQ)
+		a.close = conn.Close
+
+		log.Info("broker app created",
+			zap.String("host", cfg.RabbitMQ.Host),
+			zap.Int("port", cfg.RabbitMQ.Port),
+		)
+	case broker.BackendMemory:
+		b, err := memory.New(cfg.RabbitMQ, log)
+		if err != nil {
+			panic(err)
+		}
+
+		a.publisher = b
+		a.subscriber = b
+		a.deadLetters = b
+		a.close = b.Close
+
+		log.Info("broker app created, messages are kept in memory")
+	case broker.BackendPostgres:
+		b, err := pgqueue.New(repo, cfg.RabbitMQ, cfg.JobQueue, log)
+		if err != nil {
+			panic(err)
+		}
+
+		a.publisher = b
+		a.subscriber = b
+		a.deadLetters = b
+		a.close = b.Close
+
+		log.Info("broker app created, messages are queued in postgres",
+			zap.Duration("visibility_timeout", cfg.JobQueue.VisibilityTimeout),
+			zap.Int("max_attempts", cfg.JobQueue.MaxAttempts),
+		)
+	default:
+		panic(fmt.Errorf("unknown broker backend %q", cfg.Broker))
+	}
+
+	a.router = consumer.NewRouter(
+		a.subscriber,
+		cfg.RabbitMQ.NotificationConsumer,
+		envelope.DefaultRegistry(),
+		cache,
 		log,
 	)
+	a.router.Use(consumer.Logging(log), consumer.Recovery(log), consumer.Tracing())
 
-	log.Info("broker app created",
-		zap.String("host", cfgRabbitMQ.Host),
-		zap.Int("port", cfgRabbitMQ.Port),
-	)
-	return &App{
-		eventEnrollmentConsumer: eConsumer,
-		cfg:                     cfgRabbitMQ,
-	}
+	return a
+}
+
+func (a *App) Publisher() broker.Publisher {
+	return a.publisher
+}
+
+func (a *App) DeadLetters() deadletter.Store {
+	return a.deadLetters
+}
+
+// HandleNotificationEvents registers the handlers of enrollment and schedule
+// events, session reminders, medical certificate notices, digests and
+// deferred notifications, they are started by MustRun.
+func (a *App) HandleNotificationEvents(svc notification.Service) {
+	consumer.HandleEnrollmentEvents(a.router, a.cfg.NotificationConsumer.Queue, svc, a.log)
+	consumer.HandleScheduleChanges(a.router, a.cfg.NotificationConsumer.Queue, svc, a.log)
+	consumer.HandleSessionReminders(a.router, a.cfg.NotificationConsumer.Queue, svc, a.log)
+	consumer.HandleMedicalCertificates(a.router, a.cfg.NotificationConsumer.Queue, svc, a.log)
+	consumer.HandleNotificationDigests(a.router, a.cfg.NotificationConsumer.Queue, svc, a.log)
+	consumer.HandleDeferredNotifications(a.router, a.cfg.NotificationConsumer.Queue, svc, a.log)
+	a.hasHandlers = true
 }
 
 func (a *App) MustRun(ctx context.Context) {
-	if err := a.eventEnrollmentConsumer.Consume(ctx); err != nil {
+	if !a.hasHandlers {
+		return
+	}
+
+	if err := a.router.Consume(ctx); err != nil {
 		if errors.Is(err, consumer.ErrConsumerClosed) {
 			return
 		}
@@ -48,6 +144,11 @@ func (a *App) MustRun(ctx context.Context) {
 	}
 }
 
+// Shutdown stops the consumers and then closes the backend.
 func (a *App) Shutdown(ctx context.Context) error {
-	return a.eventEnrollmentConsumer.Shutdown(ctx)
+	if err := a.router.Shutdown(ctx); err != nil {
+		return err
+	}
+
+	return a.close(ctx)
 }
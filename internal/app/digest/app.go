@@ -0,0 +1,70 @@
+package digest
+
+import (
+	"context"
+	"dussh/internal/cache/redis"
+	"dussh/internal/config"
+	"dussh/internal/services/digest/scheduler"
+	"errors"
+	"go.uber.org/zap"
+)
+
+type App struct {
+	scheduler *scheduler.Scheduler
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+func New(cfg *config.Config, repo scheduler.Repository, cache redis.Cache, log *zap.Logger) *App {
+	log.Info("digest app creating")
+
+	s, err := scheduler.New(repo, cache, cfg.Digest, log)
+	if err != nil {
+		panic(err)
+	}
+
+	log.Info("digest app created",
+		zap.Duration("interval", cfg.Digest.Interval),
+		zap.Int("hour", cfg.Digest.Hour),
+		zap.String("weekday", cfg.Digest.Weekday),
+	)
+	return &App{
+		scheduler: s,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+}
+
+func (a *App) MustRun(ctx context.Context) {
+	defer close(a.done)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-a.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	if err := a.scheduler.Run(ctx); err != nil {
+		if errors.Is(err, scheduler.ErrSchedulerClosed) {
+			return
+		}
+
+		panic(err)
+	}
+}
+
+// Shutdown stops the scheduler and waits for the current scan to finish.
+func (a *App) Shutdown(ctx context.Context) error {
+	close(a.stop)
+
+	select {
+	case <-a.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -2,12 +2,22 @@ package httpapp
 
 import (
 	"dussh/internal/app/rbac"
+	"dussh/internal/cache/redis"
 	"dussh/internal/config"
 	"dussh/internal/domain/models"
 	httpserver "dussh/internal/http"
+	"dussh/internal/services/audit"
 	"dussh/internal/services/auth"
 	"dussh/internal/services/course"
+	"dussh/internal/services/deadletter"
+	"dussh/internal/services/inbox"
+	"dussh/internal/services/mailsink"
+	"dussh/internal/services/notification"
+	"dussh/internal/services/preference"
+	"dussh/internal/services/telegram"
+	"dussh/internal/services/template"
 	"dussh/internal/services/user"
+	"dussh/internal/services/webhook"
 	"fmt"
 	"go.uber.org/zap"
 	"golang.org/x/net/context"
@@ -26,7 +36,17 @@ func New(
 	authAPI auth.Api,
 	userAPI user.Api,
 	courseAPI course.Api,
+	auditAPI audit.Api,
+	deadLetterAPI deadletter.Api,
+	telegramAPI telegram.Api,
+	webhookAPI webhook.Api,
+	preferenceAPI preference.Api,
+	templateAPI template.Api,
+	notificationAPI notification.Api,
+	inboxAPI inbox.Api,
+	mailSinkAPI mailsink.Api,
 	rbac *rbac.App,
+	cache redis.Cache,
 	log *zap.Logger,
 ) *App {
 	log.Info("http app creating")
@@ -39,7 +59,18 @@ func New(
 		authAPI,
 		userAPI,
 		courseAPI,
+		auditAPI,
+		deadLetterAPI,
+		telegramAPI,
+		webhookAPI,
+		preferenceAPI,
+		templateAPI,
+		notificationAPI,
+		inboxAPI,
+		mailSinkAPI,
 		rbac.RoleManager(),
+		cache,
+		log,
 	)
 
 	addr := cfg.HTTPServer.Address
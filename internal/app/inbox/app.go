@@ -0,0 +1,54 @@
+package inbox
+
+import (
+	"context"
+	inboxservice "dussh/internal/services/inbox/service"
+	"go.uber.org/zap"
+)
+
+type App struct {
+	svc  inboxservice.Service
+	stop chan struct{}
+	done chan struct{}
+}
+
+func New(svc inboxservice.Service, log *zap.Logger) *App {
+	log.Info("inbox app created")
+
+	return &App{
+		svc:  svc,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+}
+
+// MustRun pushes the inbox notifications published by every instance to the
+// streams open on this one.
+func (a *App) MustRun(ctx context.Context) {
+	defer close(a.done)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-a.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	a.svc.Listen(ctx)
+}
+
+// Shutdown ends the open streams, the http server would wait for them.
+func (a *App) Shutdown(ctx context.Context) error {
+	close(a.stop)
+	a.svc.Close()
+
+	select {
+	case <-a.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
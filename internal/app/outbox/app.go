@@ -0,0 +1,78 @@
+package outbox
+
+import (
+	"context"
+	"dussh/internal/broker"
+	"dussh/internal/broker/outbox"
+	"dussh/internal/broker/publisher"
+	"dussh/internal/config"
+	"errors"
+	"go.uber.org/zap"
+)
+
+type App struct {
+	relay *outbox.Relay
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+func New(
+	ctx context.Context,
+	cfg *config.Config,
+	b broker.Publisher,
+	repo outbox.Repository,
+	log *zap.Logger,
+) *App {
+	log.Info("outbox app creating")
+
+	relay := outbox.NewRelay(
+		repo,
+		publisher.NewEnvelopePublisher(b),
+		cfg.Outbox,
+		log,
+	)
+
+	log.Info("outbox app created",
+		zap.Duration("poll_interval", cfg.Outbox.PollInterval),
+		zap.Int64("batch_size", cfg.Outbox.BatchSize),
+	)
+	return &App{
+		relay: relay,
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+}
+
+func (a *App) MustRun(ctx context.Context) {
+	defer close(a.done)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-a.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	if err := a.relay.Run(ctx); err != nil {
+		if errors.Is(err, outbox.ErrRelayClosed) {
+			return
+		}
+
+		panic(err)
+	}
+}
+
+// Shutdown stops the relay and waits for the current batch to finish.
+func (a *App) Shutdown(ctx context.Context) error {
+	close(a.stop)
+
+	select {
+	case <-a.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
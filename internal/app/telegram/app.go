@@ -0,0 +1,137 @@
+package telegram
+
+import (
+	"context"
+	"dussh/internal/config"
+	"dussh/pkg/notify/provider/telegram"
+	"errors"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	retryDelay    = time.Second
+	maxRetryDelay = time.Minute
+)
+
+// Bot answers the messages sent to the bot.
+type Bot interface {
+	HandleMessage(ctx context.Context, chatID int64, text string) string
+}
+
+// App long polls the Bot API for messages and replies to them.
+type App struct {
+	client  *telegram.Client
+	bot     Bot
+	timeout time.Duration
+	enabled bool
+
+	log  *zap.Logger
+	stop chan struct{}
+	done chan struct{}
+}
+
+func New(cfg config.TelegramProvider, bot Bot, log *zap.Logger) *App {
+	log = log.Named("telegram.bot")
+
+	// the http timeout must outlast the long poll
+	httpClient := &http.Client{Timeout: cfg.PollTimeout + 10*time.Second}
+
+	a := &App{
+		client:  telegram.NewClient(cfg.BaseURL, cfg.Token, httpClient),
+		bot:     bot,
+		timeout: cfg.PollTimeout,
+		enabled: cfg.Token != "",
+		log:     log,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	if !a.enabled {
+		log.Info("telegram bot disabled, token is not set")
+	}
+	return a
+}
+
+func (a *App) MustRun(ctx context.Context) {
+	defer close(a.done)
+
+	if !a.enabled {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-a.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	a.log.Info("telegram bot polling")
+
+	var (
+		offset int64
+		delay  = retryDelay
+	)
+	for {
+		updates, err := a.client.GetUpdates(ctx, offset, a.timeout)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			a.log.Error("failed to get telegram updates", zap.Error(err), zap.Duration("retry_in", delay))
+
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return
+			}
+			delay = min(delay*2, maxRetryDelay)
+			continue
+		}
+		delay = retryDelay
+
+		for _, update := range updates {
+			offset = update.UpdateID + 1
+			if update.Message == nil || update.Message.Text == "" {
+				continue
+			}
+
+			a.reply(ctx, update.Message)
+		}
+	}
+}
+
+func (a *App) reply(ctx context.Context, msg *telegram.Message) {
+	// a reply is not interrupted by the shutdown
+	ctx = context.WithoutCancel(ctx)
+
+	text := a.bot.HandleMessage(ctx, msg.Chat.ID, msg.Text)
+	chatID := strconv.FormatInt(msg.Chat.ID, 10)
+
+	if err := a.client.SendMessage(ctx, chatID, text, ""); err != nil {
+		var apiErr *telegram.APIError
+		if errors.As(err, &apiErr) {
+			a.log.Warn("telegram rejected reply", zap.Int64("chat_id", msg.Chat.ID), zap.Error(err))
+			return
+		}
+		a.log.Error("failed to send telegram reply", zap.Int64("chat_id", msg.Chat.ID), zap.Error(err))
+	}
+}
+
+// Shutdown stops polling and waits for the current replies to be sent.
+func (a *App) Shutdown(ctx context.Context) error {
+	close(a.stop)
+
+	select {
+	case <-a.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,66 @@
+package webhook
+
+import (
+	"context"
+	"dussh/internal/config"
+	"dussh/internal/services/webhook/dispatcher"
+	"errors"
+	"go.uber.org/zap"
+)
+
+type App struct {
+	dispatcher *dispatcher.Dispatcher
+	stop       chan struct{}
+	done       chan struct{}
+}
+
+func New(cfg *config.Config, repo dispatcher.Repository, log *zap.Logger) *App {
+	log.Info("webhook app creating")
+
+	d := dispatcher.New(repo, cfg.Webhook, log)
+
+	log.Info("webhook app created",
+		zap.Duration("poll_interval", cfg.Webhook.PollInterval),
+		zap.Int("workers", cfg.Webhook.Workers),
+		zap.Int("max_attempts", cfg.Webhook.MaxAttempts),
+	)
+	return &App{
+		dispatcher: d,
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+}
+
+func (a *App) MustRun(ctx context.Context) {
+	defer close(a.done)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-a.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	if err := a.dispatcher.Run(ctx); err != nil {
+		if errors.Is(err, dispatcher.ErrDispatcherClosed) {
+			return
+		}
+
+		panic(err)
+	}
+}
+
+// Shutdown stops the dispatcher and waits for the current batch to finish.
+func (a *App) Shutdown(ctx context.Context) error {
+	close(a.stop)
+
+	select {
+	case <-a.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
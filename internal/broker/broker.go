@@ -0,0 +1,72 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Backends selectable with the broker config option.
+const (
+	BackendRabbitMQ = "rabbitmq"
+	BackendMemory   = "memory"
+	BackendPostgres = "postgres"
+)
+
+var ErrClosed = errors.New("broker closed")
+
+// Message is a message published to or received from a broker.
+type Message struct {
+	ID            string
+	RoutingKey    string
+	Type          string
+	CorrelationID string
+	Producer      string
+	Timestamp     time.Time
+	ContentType   string
+	Headers       map[string]any
+	Body          []byte
+}
+
+// Publisher publishes messages, Publish returns once the broker accepted the message.
+type Publisher interface {
+	Publish(ctx context.Context, msg Message) error
+}
+
+// Delivery is a received message that must be settled exactly once with
+// Ack, Requeue, Retry or DeadLetter.
+type Delivery interface {
+	Message() Message
+	// Redelivered reports whether the message was delivered before.
+	Redelivered() bool
+	Ack() error
+	// Requeue returns the message to the queue for immediate redelivery.
+	Requeue() error
+	// Delay returns the message to the queue for redelivery after the delay,
+	// unlike Retry it is not counted as a failed attempt.
+	Delay(ctx context.Context, delay time.Duration) error
+	// Retry redelivers the message after a backoff, or dead-letters it once
+	// the retries are exhausted.
+	Retry(ctx context.Context, reason error) error
+	// DeadLetter moves the message to the dead-letter queue.
+	DeadLetter(ctx context.Context, reason error) error
+}
+
+type SubscribeOptions struct {
+	Queue    string
+	Consumer string
+	// Prefetch limits the number of unsettled deliveries.
+	Prefetch int
+	// Workers is the number of deliveries handled concurrently.
+	Workers int
+	// Key returns the ordering key of a message, messages with the same key
+	// are handled one by one in delivery order. Nil means no ordering.
+	Key func(Message) string
+}
+
+// Subscriber delivers messages of a queue to a handler.
+type Subscriber interface {
+	// Subscribe blocks until ctx is canceled or the broker is closed, and
+	// waits for in-flight deliveries to be handled before it returns.
+	Subscribe(ctx context.Context, opts SubscribeOptions, handler func(context.Context, Delivery)) error
+}
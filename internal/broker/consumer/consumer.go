@@ -0,0 +1,374 @@
+package consumer
+
+import (
+	"context"
+	"dussh/internal/broker"
+	"dussh/internal/broker/envelope"
+	"dussh/internal/cache/redis"
+	"dussh/internal/config"
+	"dussh/internal/domain/models"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"sync"
+)
+
+var (
+	ErrConsumerClosed = errors.New("consumer closed")
+	ErrNoRoute        = errors.New("no handler for message")
+)
+
+type Consumer interface {
+	Consume(context.Context) error
+	Shutdown(context.Context) error
+}
+
+// Message is a delivery decoded by the router.
+type Message struct {
+	broker.Message
+	Queue       string
+	Redelivered bool
+	Envelope    *envelope.Envelope
+	// Event is the decoded and upcasted payload, its type is the type the
+	// route was registered with.
+	Event models.DomainEvent
+}
+
+// id returns the envelope ID, or the broker message ID for legacy messages.
+func (m *Message) id() string {
+	if m.Envelope.ID != "" {
+		return m.Envelope.ID
+	}
+	return m.ID
+}
+
+// Handler handles a message, an error schedules a retry of the message.
+type Handler func(ctx context.Context, m *Message) error
+
+// Middleware wraps every handler of the router, e.g. for logging or recovery.
+type Middleware func(Handler) Handler
+
+// route binds an event type to a typed handler.
+type route struct {
+	eventType  string
+	routingKey string
+	decode     func(data []byte) (*envelope.Envelope, models.DomainEvent, error)
+	handler    Handler
+}
+
+// queueRoutes are the routes of a single queue.
+type queueRoutes struct {
+	byType map[string]*route
+	// byKey resolves messages without a type, nil marks an ambiguous key.
+	byKey map[string]*route
+	dedup *deduplicator
+}
+
+// Router consumes one or more queues and dispatches every message to the
+// handler registered for its event type, falling back to the routing key for
+// messages published without a type. Messages without a handler or that
+// cannot be decoded are dead-lettered, failed messages are retried.
+// Redeliveries of processed messages are skipped, see deduplicator.
+type Router struct {
+	sub        broker.Subscriber
+	cfg        config.NotificationConsumer
+	upcasters  *envelope.Registry
+	cache      redis.Cache
+	middleware []Middleware
+	queues     map[string]*queueRoutes
+	log        *zap.Logger
+
+	mu      sync.Mutex
+	cancel  func()
+	running sync.WaitGroup
+}
+
+// NewRouter returns a router subscribing with the consumer name, prefetch
+// and workers of cfg.
+func NewRouter(
+	sub broker.Subscriber,
+	cfg config.NotificationConsumer,
+	upcasters *envelope.Registry,
+	cache redis.Cache,
+	log *zap.Logger,
+) *Router {
+	return &Router{
+		sub:       sub,
+		cfg:       cfg,
+		upcasters: upcasters,
+		cache:     cache,
+		queues:    make(map[string]*queueRoutes),
+		cancel:    func() {},
+		log:       log.Named("consumer.router"),
+	}
+}
+
+// Use appends middleware, the first one is the outermost. Middleware and
+// handlers must be registered before Consume is called.
+func (r *Router) Use(middleware ...Middleware) {
+	r.middleware = append(r.middleware, middleware...)
+}
+
+// Handle registers handler for events of type T consumed from queue.
+func Handle[T models.DomainEvent](r *Router, queue string, handler func(context.Context, T) error) {
+	var event T
+
+	rt := &route{
+		eventType:  event.EventType(),
+		routingKey: event.RoutingKey(),
+		decode: func(data []byte) (*envelope.Envelope, models.DomainEvent, error) {
+			return envelope.Decode[T](data, r.upcasters)
+		},
+		handler: func(ctx context.Context, m *Message) error {
+			return handler(ctx, m.Event.(T))
+		},
+	}
+
+	qr := r.queueRoutes(queue)
+	if _, ok := qr.byType[rt.eventType]; ok {
+		panic(fmt.Sprintf("consumer: handler for %q on queue %q is already registered", rt.eventType, queue))
+	}
+	qr.byType[rt.eventType] = rt
+
+	if _, ok := qr.byKey[rt.routingKey]; ok {
+		qr.byKey[rt.routingKey] = nil
+	} else {
+		qr.byKey[rt.routingKey] = rt
+	}
+}
+
+func (r *Router) queueRoutes(queue string) *queueRoutes {
+	qr, ok := r.queues[queue]
+	if ok {
+		return qr
+	}
+
+	// the consumer queue keeps the dedup keys it had before the router
+	name := r.cfg.Name
+	if queue != r.cfg.Queue {
+		name += "." + queue
+	}
+
+	qr = &queueRoutes{
+		byType: make(map[string]*route),
+		byKey:  make(map[string]*route),
+		dedup: &deduplicator{
+			cache:   r.cache,
+			name:    name,
+			ttl:     r.cfg.DedupTTL,
+			lockTTL: r.cfg.DedupLockTTL,
+		},
+	}
+	r.queues[queue] = qr
+
+	return qr
+}
+
+// Consume subscribes to every queue with a registered handler and processes
+// messages until shutdown. If a subscription fails, the others are stopped.
+func (r *Router) Consume(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	r.mu.Lock()
+	r.cancel = cancel
+	r.running.Add(1)
+	r.mu.Unlock()
+	defer r.running.Done()
+
+	var (
+		wg   sync.WaitGroup
+		once sync.Once
+		err  error
+	)
+	for queue, qr := range r.queues {
+		wg.Add(1)
+		go func(queue string, qr *queueRoutes) {
+			defer wg.Done()
+
+			if subErr := r.subscribe(ctx, queue, qr); subErr != nil {
+				once.Do(func() {
+					err = subErr
+					cancel()
+				})
+			}
+		}(queue, qr)
+	}
+	wg.Wait()
+
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, broker.ErrClosed) {
+		return ErrConsumerClosed
+	}
+
+	return err
+}
+
+func (r *Router) wrap(h Handler) Handler {
+	for i := len(r.middleware) - 1; i >= 0; i-- {
+		h = r.middleware[i](h)
+	}
+	return h
+}
+
+func (r *Router) subscribe(ctx context.Context, queue string, qr *queueRoutes) error {
+	return r.sub.Subscribe(
+		ctx,
+		broker.SubscribeOptions{
+			Queue:    queue,
+			Consumer: r.cfg.Name,
+			Prefetch: r.cfg.Prefetch,
+			Workers:  r.cfg.Workers,
+			Key: func(msg broker.Message) string {
+				return r.orderingKey(qr, msg)
+			},
+		},
+		func(ctx context.Context, d broker.Delivery) {
+			r.handle(ctx, queue, qr, d)
+		},
+	)
+}
+
+// match returns the route of the message by its type, messages published
+// without a type are matched by the type in the envelope or the routing key.
+func (qr *queueRoutes) match(msg broker.Message) (*route, error) {
+	eventType := msg.Type
+	if eventType == "" {
+		if env, err := envelope.Unmarshal(msg.Body, ""); err == nil {
+			eventType = env.Type
+		}
+	}
+
+	if rt, ok := qr.byType[eventType]; ok {
+		return rt, nil
+	}
+	if eventType == "" {
+		if rt := qr.byKey[msg.RoutingKey]; rt != nil {
+			return rt, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: type %q, routing key %q", ErrNoRoute, eventType, msg.RoutingKey)
+}
+
+// orderingKey keeps events of the same entity in order, see models.OrderedEvent.
+func (r *Router) orderingKey(qr *queueRoutes, msg broker.Message) string {
+	rt, err := qr.match(msg)
+	if err != nil {
+		return msg.ID
+	}
+
+	_, event, err := rt.decode(msg.Body)
+	if err != nil {
+		return msg.ID
+	}
+
+	if e, ok := event.(models.OrderedEvent); ok {
+		return e.OrderingKey()
+	}
+	return msg.ID
+}
+
+func (r *Router) handle(ctx context.Context, queue string, qr *queueRoutes, d broker.Delivery) {
+	msg := d.Message()
+	log := r.log.With(zap.String("queue", queue), zap.String("message_id", msg.ID))
+
+	rt, err := qr.match(msg)
+	if err != nil {
+		// a message without a handler or that cannot be decoded will never succeed
+		log.Error("failed to route message", zap.Error(err))
+		r.settle(log, d.DeadLetter(ctx, err))
+		return
+	}
+
+	env, event, err := rt.decode(msg.Body)
+	if err != nil {
+		log.Error("failed to decode message", zap.String("type", rt.eventType), zap.Error(err))
+		r.settle(log, d.DeadLetter(ctx, err))
+		return
+	}
+
+	id := env.ID
+	if id == "" {
+		id = msg.ID
+	}
+
+	if id != "" {
+		state, err := qr.dedup.acquire(ctx, id)
+		if err != nil {
+			// prefer a possible duplicate over losing the message while Redis is down
+			log.Warn("failed to check message for duplicate", zap.Error(err))
+		}
+
+		switch state {
+		case dedupDuplicate:
+			qr.dedup.count("duplicates_skipped")
+			log.Info("skipping already processed message")
+			d.Ack()
+			return
+		case dedupInProgress:
+			qr.dedup.count("duplicates_in_progress")
+			// the other delivery may be slow or its consumer gone, check again
+			// later instead of spinning until the lock expires
+			r.settle(log, d.Delay(ctx, r.cfg.InProgressDelay))
+			return
+		}
+	}
+
+	m := &Message{
+		Message:     msg,
+		Queue:       queue,
+		Redelivered: d.Redelivered(),
+		Envelope:    env,
+		Event:       event,
+	}
+	if err := r.wrap(rt.handler)(envelope.WithEnvelope(ctx, env), m); err != nil {
+		qr.dedup.count("failed")
+		if id != "" {
+			if err := qr.dedup.release(ctx, id); err != nil {
+				log.Warn("failed to release message claim", zap.Error(err))
+			}
+		}
+
+		r.settle(log, d.Retry(ctx, err))
+		return
+	}
+
+	qr.dedup.count("processed")
+	if id != "" {
+		if err := qr.dedup.done(ctx, id); err != nil {
+			log.Warn("failed to mark message as processed", zap.Error(err))
+		}
+	}
+
+	if err := d.Ack(); err != nil {
+		log.Error("failed to ack message", zap.Error(err))
+	}
+}
+
+// settle logs a failed retry or dead-letter move, the broker requeues
+// the delivery in that case.
+func (r *Router) settle(log *zap.Logger, err error) {
+	if err != nil {
+		log.Error("failed to move message, requeued", zap.Error(err))
+	}
+}
+
+// Shutdown stops consuming and waits until in-flight messages are processed.
+func (r *Router) Shutdown(ctx context.Context) error {
+	r.mu.Lock()
+	r.cancel()
+	r.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		r.running.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,80 @@
+package consumer
+
+import (
+	"context"
+	"dussh/internal/cache/redis"
+	"errors"
+	"expvar"
+	"time"
+)
+
+const (
+	dedupKeyPrefix = "consumer:dedup:"
+
+	dedupProcessing = "processing"
+	dedupDone       = "done"
+)
+
+// metrics are published on the admin debug/vars route, keyed by
+// "<consumer>.<counter>".
+var metrics = expvar.NewMap("broker_consumer")
+
+type dedupState int
+
+const (
+	// dedupAcquired means the message was not seen before and must be processed.
+	dedupAcquired dedupState = iota
+	// dedupDuplicate means the message was already processed.
+	dedupDuplicate
+	// dedupInProgress means another delivery of the message is being processed.
+	dedupInProgress
+)
+
+// deduplicator remembers processed message IDs in Redis, so redeliveries
+// of an already processed message are skipped.
+type deduplicator struct {
+	cache redis.Cache
+	name  string
+	ttl   time.Duration
+	// lockTTL bounds how long a message stays claimed if the consumer dies.
+	lockTTL time.Duration
+}
+
+func (d *deduplicator) key(id string) string {
+	return dedupKeyPrefix + d.name + ":" + id
+}
+
+func (d *deduplicator) acquire(ctx context.Context, id string) (dedupState, error) {
+	acquired, err := d.cache.SetNX(ctx, d.key(id), dedupProcessing, d.lockTTL)
+	if err != nil {
+		return dedupAcquired, err
+	}
+	if acquired {
+		return dedupAcquired, nil
+	}
+
+	state, err := d.cache.Get(ctx, d.key(id))
+	switch {
+	case errors.Is(err, redis.ErrNotFound):
+		// the claim expired or was released in between
+		return d.acquire(ctx, id)
+	case err != nil:
+		return dedupAcquired, err
+	case state == dedupDone:
+		return dedupDuplicate, nil
+	default:
+		return dedupInProgress, nil
+	}
+}
+
+func (d *deduplicator) done(ctx context.Context, id string) error {
+	return d.cache.Set(ctx, d.key(id), dedupDone, d.ttl)
+}
+
+func (d *deduplicator) release(ctx context.Context, id string) error {
+	return d.cache.Delete(ctx, d.key(id))
+}
+
+func (d *deduplicator) count(counter string) {
+	metrics.Add(d.name+"."+counter, 1)
+}
@@ -0,0 +1,95 @@
+package consumer
+
+import (
+	"context"
+	"dussh/internal/cache/redis"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// fakeCache keeps the keys in a map, expiring a key is simulated by expire.
+type fakeCache struct {
+	redis.Cache
+
+	values map[string]string
+	// expire drops the key on the next Get, as if its TTL ran out after SetNX
+	expire bool
+}
+
+func newFakeCache() *fakeCache {
+	return &fakeCache{values: make(map[string]string)}
+}
+
+func (c *fakeCache) Get(_ context.Context, key string) (string, error) {
+	if c.expire {
+		c.expire = false
+		delete(c.values, key)
+	}
+
+	value, ok := c.values[key]
+	if !ok {
+		return "", redis.ErrNotFound
+	}
+	return value, nil
+}
+
+func (c *fakeCache) Set(_ context.Context, key string, value any, _ time.Duration) error {
+	c.values[key] = fmt.Sprint(value)
+	return nil
+}
+
+func (c *fakeCache) SetNX(_ context.Context, key string, value any, _ time.Duration) (bool, error) {
+	if _, ok := c.values[key]; ok {
+		return false, nil
+	}
+	c.values[key] = fmt.Sprint(value)
+	return true, nil
+}
+
+func (c *fakeCache) Delete(_ context.Context, key string) error {
+	delete(c.values, key)
+	return nil
+}
+
+func TestDeduplicator(t *testing.T) {
+	ctx := context.Background()
+	cache := newFakeCache()
+	d := &deduplicator{cache: cache, name: "test", ttl: time.Hour, lockTTL: time.Minute}
+
+	acquire := func(want dedupState) {
+		t.Helper()
+		state, err := d.acquire(ctx, "msg")
+		if err != nil {
+			t.Fatalf("acquire() error = %v", err)
+		}
+		if state != want {
+			t.Fatalf("acquire() = %v, want %v", state, want)
+		}
+	}
+
+	acquire(dedupAcquired)
+	// a redelivery while the first delivery is processed
+	acquire(dedupInProgress)
+
+	// a failed delivery releases the claim, so the retry is processed
+	if err := d.release(ctx, "msg"); err != nil {
+		t.Fatal(err)
+	}
+	acquire(dedupAcquired)
+
+	if err := d.done(ctx, "msg"); err != nil {
+		t.Fatal(err)
+	}
+	acquire(dedupDuplicate)
+
+	// the claim of another message expires between SetNX and Get
+	if _, err := cache.SetNX(ctx, d.key("other"), dedupProcessing, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	cache.expire = true
+	state, err := d.acquire(ctx, "other")
+	if err != nil || state != dedupAcquired {
+		t.Fatalf("acquire() = %v, %v, want %v", state, err, dedupAcquired)
+	}
+}
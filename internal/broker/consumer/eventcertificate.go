@@ -0,0 +1,22 @@
+package consumer
+
+import (
+	"context"
+	"dussh/internal/domain/models"
+	"dussh/internal/services/notification"
+	"go.uber.org/zap"
+)
+
+// HandleMedicalCertificates sends the notices of expiring medical
+// certificates enqueued by the reminder scheduler.
+func HandleMedicalCertificates(r *Router, queue string, svc notification.Service, log *zap.Logger) {
+	Handle(r, queue, func(ctx context.Context, e models.MedicalCertificateExpiringEvent) error {
+		if err := svc.NotifyMedicalCertificateExpiring(ctx, e); err != nil {
+			log.Error("failed to send medical certificate notice",
+				zap.Int64("certificate_id", e.CertificateID), zap.Error(err))
+			return err
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,21 @@
+package consumer
+
+import (
+	"context"
+	"dussh/internal/domain/models"
+	"dussh/internal/services/notification"
+	"go.uber.org/zap"
+)
+
+// HandleDeferredNotifications sends notifications held back by quiet hours
+// once they are published from the outbox.
+func HandleDeferredNotifications(r *Router, queue string, svc notification.Service, log *zap.Logger) {
+	Handle(r, queue, func(ctx context.Context, e models.DeferredNotificationEvent) error {
+		if err := svc.NotifyDeferred(ctx, e); err != nil {
+			log.Error("failed to send deferred notification", zap.String("type", e.Type), zap.Error(err))
+			return err
+		}
+
+		return nil
+	})
+}
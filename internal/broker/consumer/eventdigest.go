@@ -0,0 +1,21 @@
+package consumer
+
+import (
+	"context"
+	"dussh/internal/domain/models"
+	"dussh/internal/services/notification"
+	"go.uber.org/zap"
+)
+
+// HandleNotificationDigests sends the digests enqueued by the digest
+// scheduler.
+func HandleNotificationDigests(r *Router, queue string, svc notification.Service, log *zap.Logger) {
+	Handle(r, queue, func(ctx context.Context, e models.NotificationDigestEvent) error {
+		if err := svc.NotifyDigest(ctx, e); err != nil {
+			log.Error("failed to send notification digest", zap.Int64("user_id", e.UserID), zap.Error(err))
+			return err
+		}
+
+		return nil
+	})
+}
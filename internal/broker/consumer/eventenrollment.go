@@ -0,0 +1,38 @@
+package consumer
+
+import (
+	"context"
+	"dussh/internal/domain/models"
+	"dussh/internal/services/notification"
+	"go.uber.org/zap"
+)
+
+type eventEnrollmentHandler struct {
+	svc notification.Service
+	log *zap.Logger
+}
+
+// HandleEnrollmentEvents notifies users about enrollment events consumed from queue.
+func HandleEnrollmentEvents(r *Router, queue string, svc notification.Service, log *zap.Logger) {
+	h := &eventEnrollmentHandler{svc: svc, log: log}
+	Handle(r, queue, h.handle)
+	Handle(r, queue, h.handleCancelled)
+}
+
+func (h *eventEnrollmentHandler) handle(ctx context.Context, e models.EnrollmentEvent) error {
+	if err := h.svc.NotifyEnrollment(ctx, e); err != nil {
+		h.log.Error("failed to notify about enrollment", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (h *eventEnrollmentHandler) handleCancelled(ctx context.Context, e models.EnrollmentCancelledEvent) error {
+	if err := h.svc.NotifyCancellation(ctx, e); err != nil {
+		h.log.Error("failed to notify about cancelled enrollment", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
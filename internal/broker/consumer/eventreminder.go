@@ -0,0 +1,21 @@
+package consumer
+
+import (
+	"context"
+	"dussh/internal/domain/models"
+	"dussh/internal/services/notification"
+	"go.uber.org/zap"
+)
+
+// HandleSessionReminders sends the session reminders enqueued by the reminder
+// scheduler.
+func HandleSessionReminders(r *Router, queue string, svc notification.Service, log *zap.Logger) {
+	Handle(r, queue, func(ctx context.Context, e models.SessionReminderEvent) error {
+		if err := svc.NotifyReminder(ctx, e); err != nil {
+			log.Error("failed to send session reminder", zap.Int64("event_id", e.EventID), zap.Error(err))
+			return err
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,21 @@
+package consumer
+
+import (
+	"context"
+	"dussh/internal/domain/models"
+	"dussh/internal/services/notification"
+	"go.uber.org/zap"
+)
+
+// HandleScheduleChanges notifies the enrolled users and the trainers of a
+// course about changes of its schedule.
+func HandleScheduleChanges(r *Router, queue string, svc notification.Service, log *zap.Logger) {
+	Handle(r, queue, func(ctx context.Context, e models.ScheduleChangedEvent) error {
+		if err := svc.NotifyScheduleChange(ctx, e); err != nil {
+			log.Error("failed to notify about schedule change", zap.Int64("course_id", e.CourseID), zap.Error(err))
+			return err
+		}
+
+		return nil
+	})
+}
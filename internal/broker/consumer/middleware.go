@@ -0,0 +1,78 @@
+package consumer
+
+import (
+	"context"
+	"dussh/pkg/requestid"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"time"
+)
+
+var ErrHandlerPanic = errors.New("message handler panicked")
+
+// Logging logs every handled message with its outcome and duration.
+func Logging(log *zap.Logger) Middleware {
+	log = log.Named("consumer")
+
+	return func(next Handler) Handler {
+		return func(ctx context.Context, m *Message) error {
+			start := time.Now()
+			err := next(ctx, m)
+
+			fields := []zap.Field{
+				zap.String("queue", m.Queue),
+				zap.String("type", m.Envelope.Type),
+				zap.String("message_id", m.id()),
+				zap.String("correlation_id", m.Envelope.CorrelationID),
+				zap.Bool("redelivered", m.Redelivered),
+				zap.Duration("duration", time.Since(start)),
+			}
+			if err != nil {
+				log.Error("failed to handle message", append(fields, zap.Error(err))...)
+				return err
+			}
+
+			log.Debug("message handled", fields...)
+			return nil
+		}
+	}
+}
+
+// Recovery turns a panic in the handler into an error, so the message is
+// retried instead of crashing the worker.
+func Recovery(log *zap.Logger) Middleware {
+	log = log.Named("consumer")
+
+	return func(next Handler) Handler {
+		return func(ctx context.Context, m *Message) (err error) {
+			defer func() {
+				if p := recover(); p != nil {
+					log.Error("message handler panicked",
+						zap.String("message_id", m.id()),
+						zap.Any("panic", p),
+						zap.Stack("stack"),
+					)
+					err = fmt.Errorf("%w: %v", ErrHandlerPanic, p)
+				}
+			}()
+
+			return next(ctx, m)
+		}
+	}
+}
+
+// Tracing carries the correlation ID of the message over to the events the
+// handler publishes, a message without one starts a new trace with its ID.
+func Tracing() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, m *Message) error {
+			id := m.Envelope.CorrelationID
+			if id == "" {
+				id = m.id()
+			}
+
+			return next(requestid.NewContext(ctx, id), m)
+		}
+	}
+}
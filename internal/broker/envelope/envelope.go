@@ -0,0 +1,119 @@
+package envelope
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"dussh/internal/domain/models"
+	"dussh/pkg/requestid"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Producer identifies this service in published envelopes.
+const Producer = "dussh"
+
+const ctxEnvelope = "envelopeKey"
+
+var ErrUnsupportedVersion = errors.New("unsupported event schema version")
+
+// Envelope wraps every message published to the broker.
+type Envelope struct {
+	ID            string          `json:"id"`
+	Type          string          `json:"type"`
+	Version       int             `json:"version"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	Producer      string          `json:"producer"`
+	CorrelationID string          `json:"correlation_id,omitempty"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// New wraps the event into an envelope, the correlation ID is taken from the request ID.
+func New(ctx context.Context, e models.DomainEvent) (*Envelope, error) {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Envelope{
+		ID:            newID(),
+		Type:          e.EventType(),
+		Version:       e.SchemaVersion(),
+		OccurredAt:    time.Now().UTC(),
+		Producer:      Producer,
+		CorrelationID: requestid.FromContext(ctx),
+		Payload:       payload,
+	}, nil
+}
+
+// Unmarshal decodes an envelope. Messages published before envelopes were
+// introduced carry a bare payload; they are wrapped as version 1 of eventType.
+func Unmarshal(data []byte, eventType string) (*Envelope, error) {
+	var env Envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, err
+	}
+
+	if env.Type == "" && len(env.Payload) == 0 {
+		return &Envelope{
+			Type:    eventType,
+			Version: 1,
+			Payload: bytes.Clone(data),
+		}, nil
+	}
+
+	return &env, nil
+}
+
+// Decode decodes the message into an event of type T, upcasting older schema versions.
+func Decode[T models.DomainEvent](data []byte, registry *Registry) (*Envelope, T, error) {
+	var event T
+
+	env, err := Unmarshal(data, event.EventType())
+	if err != nil {
+		return nil, event, err
+	}
+
+	if env.Type != event.EventType() {
+		return env, event, fmt.Errorf("unexpected event type %q, want %q", env.Type, event.EventType())
+	}
+
+	if err := registry.Upcast(env); err != nil {
+		return env, event, err
+	}
+
+	if env.Version != event.SchemaVersion() {
+		return env, event, fmt.Errorf("%w: %s v%d", ErrUnsupportedVersion, env.Type, env.Version)
+	}
+
+	if err := json.Unmarshal(env.Payload, &event); err != nil {
+		return env, event, err
+	}
+
+	return env, event, nil
+}
+
+// WithEnvelope returns a copy of ctx carrying the envelope of the message being processed.
+func WithEnvelope(ctx context.Context, env *Envelope) context.Context {
+	return context.WithValue(ctx, ctxEnvelope, env)
+}
+
+// FromContext returns the envelope of the message being processed.
+func FromContext(ctx context.Context) (*Envelope, bool) {
+	env, ok := ctx.Value(ctxEnvelope).(*Envelope)
+	return env, ok
+}
+
+// newID returns a random UUID v4.
+func newID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
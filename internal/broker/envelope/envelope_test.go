@@ -0,0 +1,94 @@
+package envelope
+
+import (
+	"context"
+	"dussh/internal/domain/models"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestDecodeUpcastsEnrollmentCreated(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{
+			name: "bare payload published before envelopes",
+			data: `{"CourseID":7,"UserID":42}`,
+		},
+		{
+			name: "version 1 envelope",
+			data: `{"id":"1","type":"enrollment.created","version":1,"producer":"dussh","payload":{"CourseID":7,"UserID":42}}`,
+		},
+		{
+			name: "version 2 envelope",
+			data: `{"id":"1","type":"enrollment.created","version":2,"producer":"dussh","payload":{"course_id":7,"user_id":42}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, event, err := Decode[models.EnrollmentEvent]([]byte(tt.data), DefaultRegistry())
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if env.Version != 2 {
+				t.Fatalf("version = %d, want 2", env.Version)
+			}
+			if event.CourseID != 7 || event.UserID != 42 {
+				t.Fatalf("event = %+v, want course 7 and user 42", event)
+			}
+		})
+	}
+}
+
+func TestDecodeRoundTrip(t *testing.T) {
+	want := models.EnrollmentEvent{CourseID: 3, UserID: 5}
+
+	env, err := New(context.Background(), want)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	data, err := json.Marshal(env)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, got, err := Decode[models.EnrollmentEvent](data, DefaultRegistry())
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if got != want || decoded.ID != env.ID || decoded.Producer != Producer {
+		t.Fatalf("Decode() = %+v, %+v, want %+v, %+v", decoded, got, env, want)
+	}
+}
+
+func TestDecodeRejects(t *testing.T) {
+	t.Run("unknown version", func(t *testing.T) {
+		data := `{"type":"enrollment.created","version":3,"payload":{"course_id":7,"user_id":42}}`
+		_, _, err := Decode[models.EnrollmentEvent]([]byte(data), DefaultRegistry())
+		if !errors.Is(err, ErrUnsupportedVersion) {
+			t.Fatalf("Decode() error = %v, want %v", err, ErrUnsupportedVersion)
+		}
+	})
+
+	t.Run("other event type", func(t *testing.T) {
+		data := `{"type":"session.reminder","version":1,"payload":{}}`
+		if _, _, err := Decode[models.EnrollmentEvent]([]byte(data), DefaultRegistry()); err == nil {
+			t.Fatal("Decode() error = nil, want an unexpected event type error")
+		}
+	})
+
+	t.Run("failed upcast", func(t *testing.T) {
+		registry := NewRegistry()
+		registry.Register(models.EventTypeEnrollmentCreated, 1, func(json.RawMessage) (json.RawMessage, error) {
+			return nil, errors.New("broken")
+		})
+
+		data := `{"type":"enrollment.created","version":1,"payload":{}}`
+		if _, _, err := Decode[models.EnrollmentEvent]([]byte(data), registry); err == nil {
+			t.Fatal("Decode() error = nil, want the upcaster error")
+		}
+	})
+}
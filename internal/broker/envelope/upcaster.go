@@ -0,0 +1,72 @@
+package envelope
+
+import (
+	"dussh/internal/domain/models"
+	"encoding/json"
+	"fmt"
+)
+
+// Upcaster converts a payload of one schema version to the next version.
+type Upcaster func(payload json.RawMessage) (json.RawMessage, error)
+
+type upcasterKey struct {
+	eventType string
+	version   int
+}
+
+// Registry holds upcasters by event type and the version they upcast from.
+type Registry struct {
+	upcasters map[upcasterKey]Upcaster
+}
+
+func NewRegistry() *Registry {
+	return &Registry{upcasters: make(map[upcasterKey]Upcaster)}
+}
+
+// Register adds an upcaster from version to version+1 of eventType.
+func (r *Registry) Register(eventType string, version int, up Upcaster) {
+	r.upcasters[upcasterKey{eventType, version}] = up
+}
+
+// Upcast applies upcasters until no upcaster is registered for the envelope version.
+func (r *Registry) Upcast(env *Envelope) error {
+	for {
+		up, ok := r.upcasters[upcasterKey{env.Type, env.Version}]
+		if !ok {
+			return nil
+		}
+
+		payload, err := up(env.Payload)
+		if err != nil {
+			return fmt.Errorf("upcast %s v%d: %w", env.Type, env.Version, err)
+		}
+
+		env.Payload = payload
+		env.Version++
+	}
+}
+
+// DefaultRegistry returns the registry with upcasters for all known events.
+func DefaultRegistry() *Registry {
+	r := NewRegistry()
+	r.Register(models.EventTypeEnrollmentCreated, 1, enrollmentCreatedV1)
+
+	return r
+}
+
+// enrollmentCreatedV1 converts the untagged payload {"CourseID", "UserID"}
+// to the snake case fields of version 2.
+func enrollmentCreatedV1(payload json.RawMessage) (json.RawMessage, error) {
+	var v1 struct {
+		CourseID int64
+		UserID   int64
+	}
+	if err := json.Unmarshal(payload, &v1); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(models.EnrollmentEvent{
+		CourseID: v1.CourseID,
+		UserID:   v1.UserID,
+	})
+}
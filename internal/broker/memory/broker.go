@@ -0,0 +1,523 @@
+package memory
+
+import (
+	"context"
+	"dussh/internal/broker"
+	"dussh/internal/broker/rabbit"
+	"dussh/internal/config"
+	domainerrors "dussh/internal/domain/errors"
+	"dussh/internal/domain/models"
+	"errors"
+	"fmt"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+var ErrUnroutable = errors.New("message is not routed to any queue")
+
+// Broker is an in-process broker for dev and test runs. It routes messages
+// published to the notification exchange by the configured bindings and
+// mimics RabbitMQ delivery semantics: deliveries must be acked, requeued
+// deliveries are redelivered, unsettled deliveries are requeued when a
+// subscription ends, and retries are delayed and eventually dead-lettered.
+// Messages are lost when the process exits.
+type Broker struct {
+	exchange string
+	fanout   bool
+	bindings map[string][]string
+	cfg      config.NotificationConsumer
+
+	mu          sync.Mutex
+	queues      map[string]*queue
+	deadLetters []broker.Message
+	timers      map[*time.Timer]struct{}
+	closed      chan struct{}
+	closeOnce   sync.Once
+
+	log *zap.Logger
+}
+
+type queue struct {
+	ready  []*entry
+	notify chan struct{}
+}
+
+type entry struct {
+	msg         broker.Message
+	redelivered bool
+}
+
+// New returns a broker with the queues and bindings of the RabbitMQ topology.
+func New(rc config.RabbitMQ, log *zap.Logger) (*Broker, error) {
+	topology := rabbit.TopologyFromConfig(rc)
+	if err := rabbit.ValidateTopology(rc, topology); err != nil {
+		return nil, err
+	}
+
+	b := &Broker{
+		exchange: rc.NotificationPublisher.Exchange,
+		bindings: make(map[string][]string),
+		cfg:      rc.NotificationConsumer,
+		queues:   make(map[string]*queue, len(topology.Queues)),
+		timers:   make(map[*time.Timer]struct{}),
+		closed:   make(chan struct{}),
+		log:      log.Named("memory.broker"),
+	}
+
+	for _, e := range topology.Exchanges {
+		if e.Name == b.exchange {
+			b.fanout = e.Kind == amqp.ExchangeFanout
+		}
+	}
+	for _, q := range topology.Queues {
+		b.queues[q.Name] = &queue{notify: make(chan struct{}, 1)}
+	}
+	for _, bd := range topology.Bindings {
+		if bd.Exchange == b.exchange {
+			b.bindings[bd.RoutingKey] = append(b.bindings[bd.RoutingKey], bd.Queue)
+		}
+	}
+
+	return b, nil
+}
+
+// Publish copies the message to every queue bound with its routing key, or
+// to every bound queue if the exchange is a fanout.
+func (b *Broker) Publish(ctx context.Context, msg broker.Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var queues []string
+	if b.fanout {
+		for _, qs := range b.bindings {
+			queues = append(queues, qs...)
+		}
+	} else {
+		queues = b.bindings[msg.RoutingKey]
+	}
+	if len(queues) == 0 {
+		return fmt.Errorf("%w: %q", ErrUnroutable, msg.RoutingKey)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.isClosed() {
+		return broker.ErrClosed
+	}
+	for _, name := range queues {
+		b.push(name, &entry{msg: copyMessage(msg)})
+	}
+
+	return nil
+}
+
+// Subscribe delivers messages of the queue to handler until ctx is canceled
+// or the broker is closed. Like Connection.Consume it stops receiving on
+// cancellation, waits for in-flight deliveries and requeues the unsettled ones.
+func (b *Broker) Subscribe(
+	ctx context.Context,
+	opts broker.SubscribeOptions,
+	handler func(context.Context, broker.Delivery),
+) error {
+	b.mu.Lock()
+	q, ok := b.queues[opts.Queue]
+	b.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("queue %q is not declared", opts.Queue)
+	}
+
+	b.log.Info("subscribed to queue",
+		zap.String("queue", opts.Queue),
+		zap.String("consumer", opts.Consumer),
+		zap.Int("prefetch", opts.Prefetch),
+		zap.Int("workers", opts.Workers),
+	)
+
+	s := &subscription{
+		broker:  b,
+		queue:   opts.Queue,
+		unacked: make(map[*delivery]struct{}),
+	}
+	if opts.Prefetch > 0 {
+		s.slots = make(chan struct{}, opts.Prefetch)
+	}
+
+	handlerCtx := context.WithoutCancel(ctx)
+	pool := broker.NewWorkerPool(opts.Workers, func(d *delivery) { handler(handlerCtx, d) })
+
+	var err error
+	for {
+		if err = s.acquire(ctx); err != nil {
+			break
+		}
+
+		var e *entry
+		if e, err = b.next(ctx, q); err != nil {
+			s.release()
+			break
+		}
+
+		d := s.track(e)
+		key := e.msg.ID
+		if opts.Key != nil {
+			key = opts.Key(e.msg)
+		}
+		pool.Dispatch(key, d)
+	}
+
+	pool.Drain()
+	s.requeueUnacked()
+
+	return err
+}
+
+// next waits for the next ready message of the queue.
+func (b *Broker) next(ctx context.Context, q *queue) (*entry, error) {
+	for {
+		b.mu.Lock()
+		if len(q.ready) != 0 {
+			e := q.ready[0]
+			q.ready = q.ready[1:]
+			if len(q.ready) != 0 {
+				// let other subscribers of the queue pick up the rest
+				q.wake()
+			}
+			b.mu.Unlock()
+			return e, nil
+		}
+		b.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-b.closed:
+			return nil, broker.ErrClosed
+		case <-q.notify:
+		}
+	}
+}
+
+// push appends the entry to the queue, b.mu must be held.
+func (b *Broker) push(name string, e *entry) {
+	q, ok := b.queues[name]
+	if !ok {
+		b.log.Warn("dropping message for undeclared queue", zap.String("queue", name))
+		return
+	}
+
+	q.ready = append(q.ready, e)
+	q.wake()
+}
+
+// pushFront returns a requeued entry to the head of the queue like RabbitMQ does.
+func (b *Broker) pushFront(name string, e *entry) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	q := b.queues[name]
+	q.ready = append([]*entry{e}, q.ready...)
+	q.wake()
+}
+
+func (q *queue) wake() {
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
+
+// retry schedules the message for redelivery after a backoff or dead-letters
+// it once retries are exhausted, see rabbit.Retry.
+func (b *Broker) retry(name string, msg broker.Message, reason error) {
+	attempts := rabbit.RetryCount(msg.Headers)
+	if attempts >= b.cfg.MaxRetries {
+		b.deadLetter(name, msg, reason)
+		return
+	}
+
+	msg = copyMessage(msg)
+	msg.Headers[rabbit.HeaderRetryCount] = int32(attempts + 1)
+	msg.Headers[rabbit.HeaderLastError] = reason.Error()
+
+	b.schedule(name, msg, rabbit.RetryDelay(b.cfg, attempts))
+}
+
+// schedule pushes the message to the queue after the delay, unless the
+// broker is closed by then.
+func (b *Broker) schedule(name string, msg broker.Message, delay time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.isClosed() {
+		return
+	}
+
+	var t *time.Timer
+	t = time.AfterFunc(delay, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		delete(b.timers, t)
+		if !b.isClosed() {
+			b.push(name, &entry{msg: msg})
+		}
+	})
+	b.timers[t] = struct{}{}
+}
+
+func (b *Broker) deadLetter(name string, msg broker.Message, reason error) {
+	msg = copyMessage(msg)
+	msg.Headers[rabbit.HeaderLastError] = reason.Error()
+	msg.Headers[rabbit.HeaderOriginalQueue] = name
+	msg.Headers[rabbit.HeaderDeadLetteredAt] = time.Now().UTC().Format(time.RFC3339)
+	if msg.ID == "" {
+		msg.ID = fmt.Sprintf("dead-letter-%d", time.Now().UnixNano())
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.deadLetters = append(b.deadLetters, msg)
+}
+
+// List returns up to limit dead-lettered messages.
+func (b *Broker) List(_ context.Context, limit int) ([]*models.DeadLetter, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	letters := make([]*models.DeadLetter, 0, min(limit, len(b.deadLetters)))
+	for _, msg := range b.deadLetters[:min(limit, len(b.deadLetters))] {
+		letters = append(letters, toDeadLetter(msg))
+	}
+
+	return letters, nil
+}
+
+// Replay moves the message back to its original queue with a reset retry counter.
+func (b *Broker) Replay(_ context.Context, id string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	msg, err := b.takeDeadLetter(id)
+	if err != nil {
+		return err
+	}
+
+	delete(msg.Headers, rabbit.HeaderRetryCount)
+	queue, _ := msg.Headers[rabbit.HeaderOriginalQueue].(string)
+	if _, ok := b.queues[queue]; !ok {
+		queue = b.cfg.Queue
+	}
+	b.push(queue, &entry{msg: msg})
+
+	return nil
+}
+
+// Discard removes the message from the dead-letter list.
+func (b *Broker) Discard(_ context.Context, id string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	_, err := b.takeDeadLetter(id)
+	return err
+}
+
+// takeDeadLetter removes the message from the dead-letter list, b.mu must be held.
+func (b *Broker) takeDeadLetter(id string) (broker.Message, error) {
+	for i, msg := range b.deadLetters {
+		if msg.ID == id {
+			b.deadLetters = append(b.deadLetters[:i], b.deadLetters[i+1:]...)
+			return msg, nil
+		}
+	}
+
+	return broker.Message{}, domainerrors.ErrDeadLetterNotFound
+}
+
+// Close stops subscriptions and pending retries.
+func (b *Broker) Close(context.Context) error {
+	b.closeOnce.Do(func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		close(b.closed)
+		for t := range b.timers {
+			t.Stop()
+		}
+		b.timers = nil
+	})
+
+	return nil
+}
+
+// isClosed reports whether Close was called.
+func (b *Broker) isClosed() bool {
+	select {
+	case <-b.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// subscription tracks deliveries that are not settled yet.
+type subscription struct {
+	broker *Broker
+	queue  string
+	slots  chan struct{}
+
+	mu      sync.Mutex
+	unacked map[*delivery]struct{}
+}
+
+// acquire blocks while prefetch deliveries are unsettled.
+func (s *subscription) acquire(ctx context.Context) error {
+	if s.slots == nil {
+		return nil
+	}
+
+	select {
+	case s.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-s.broker.closed:
+		return broker.ErrClosed
+	}
+}
+
+func (s *subscription) release() {
+	if s.slots != nil {
+		<-s.slots
+	}
+}
+
+func (s *subscription) track(e *entry) *delivery {
+	d := &delivery{sub: s, entry: e}
+
+	s.mu.Lock()
+	s.unacked[d] = struct{}{}
+	s.mu.Unlock()
+
+	return d
+}
+
+// settle removes the delivery from the unacked set, it reports false if the
+// delivery was already settled.
+func (s *subscription) settle(d *delivery) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.unacked[d]; !ok {
+		return false
+	}
+	delete(s.unacked, d)
+	s.release()
+
+	return true
+}
+
+func (s *subscription) requeueUnacked() {
+	s.mu.Lock()
+	unacked := s.unacked
+	s.unacked = make(map[*delivery]struct{})
+	s.mu.Unlock()
+
+	for d := range unacked {
+		s.broker.pushFront(s.queue, &entry{msg: d.entry.msg, redelivered: true})
+	}
+}
+
+var errAlreadySettled = errors.New("delivery is already settled")
+
+type delivery struct {
+	sub   *subscription
+	entry *entry
+}
+
+func (d *delivery) Message() broker.Message {
+	return d.entry.msg
+}
+
+func (d *delivery) Redelivered() bool {
+	return d.entry.redelivered
+}
+
+func (d *delivery) Ack() error {
+	if !d.sub.settle(d) {
+		return errAlreadySettled
+	}
+
+	return nil
+}
+
+func (d *delivery) Requeue() error {
+	if !d.sub.settle(d) {
+		return errAlreadySettled
+	}
+
+	d.sub.broker.pushFront(d.sub.queue, &entry{msg: d.entry.msg, redelivered: true})
+	return nil
+}
+
+func (d *delivery) Delay(_ context.Context, delay time.Duration) error {
+	if !d.sub.settle(d) {
+		return errAlreadySettled
+	}
+
+	d.sub.broker.schedule(d.sub.queue, d.entry.msg, delay)
+	return nil
+}
+
+func (d *delivery) Retry(_ context.Context, reason error) error {
+	if !d.sub.settle(d) {
+		return errAlreadySettled
+	}
+
+	d.sub.broker.retry(d.sub.queue, d.entry.msg, reason)
+	return nil
+}
+
+func (d *delivery) DeadLetter(_ context.Context, reason error) error {
+	if !d.sub.settle(d) {
+		return errAlreadySettled
+	}
+
+	d.sub.broker.deadLetter(d.sub.queue, d.entry.msg, reason)
+	return nil
+}
+
+// copyMessage copies the body and headers so queues never share them.
+func copyMessage(msg broker.Message) broker.Message {
+	headers := make(map[string]any, len(msg.Headers)+3)
+	for k, v := range msg.Headers {
+		headers[k] = v
+	}
+	msg.Headers = headers
+	msg.Body = append([]byte(nil), msg.Body...)
+
+	return msg
+}
+
+func toDeadLetter(msg broker.Message) *models.DeadLetter {
+	l := &models.DeadLetter{
+		ID:          msg.ID,
+		RetryCount:  rabbit.RetryCount(msg.Headers),
+		ContentType: msg.ContentType,
+		Body:        string(msg.Body),
+	}
+
+	l.OriginalQueue, _ = msg.Headers[rabbit.HeaderOriginalQueue].(string)
+	l.LastError, _ = msg.Headers[rabbit.HeaderLastError].(string)
+	if v, ok := msg.Headers[rabbit.HeaderDeadLetteredAt].(string); ok {
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			l.DeadLetteredAt = &t
+		}
+	}
+
+	return l
+}
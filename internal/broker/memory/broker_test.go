@@ -0,0 +1,245 @@
+package memory
+
+import (
+	"context"
+	"dussh/internal/broker"
+	"dussh/internal/broker/rabbit"
+	"dussh/internal/config"
+	"errors"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func newTestBroker(t *testing.T, maxRetries int) *Broker {
+	t.Helper()
+
+	rc := config.RabbitMQ{
+		NotificationPublisher: config.NotificationPublisher{Exchange: "events"},
+		NotificationConsumer: config.NotificationConsumer{
+			Queue:         "notification",
+			RetryQueue:    "notification.retry",
+			MaxRetries:    maxRetries,
+			RetryDelay:    time.Millisecond,
+			MaxRetryDelay: time.Millisecond,
+		},
+		DeadLetter: config.DeadLetter{Exchange: "notification.dlx", Queue: "notification.dead"},
+		Topology: config.Topology{
+			Exchanges: []config.Exchange{
+				{Name: "events", Kind: "direct"},
+				{Name: "notification.dlx", Kind: "fanout"},
+			},
+			Queues: []config.Queue{
+				{Name: "notification"},
+				{Name: "audit"},
+				{Name: "billing"},
+				{Name: "notification.retry.1ms", MessageTTL: time.Millisecond, DeadLetterRoutingKey: "notification"},
+				{Name: "notification.dead"},
+			},
+			Bindings: []config.Binding{
+				{Queue: "notification", Exchange: "events", RoutingKey: "user"},
+				{Queue: "audit", Exchange: "events", RoutingKey: "user"},
+				{Queue: "billing", Exchange: "events", RoutingKey: "invoice"},
+				{Queue: "notification.dead", Exchange: "notification.dlx"},
+			},
+		},
+	}
+
+	b, err := New(rc, zap.NewNop())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	t.Cleanup(func() { b.Close(context.Background()) })
+
+	return b
+}
+
+// subscribe passes the deliveries of the queue to the returned channel until
+// the returned cancel is called, which waits for Subscribe to return.
+func subscribe(t *testing.T, b *Broker, queue string) (<-chan broker.Delivery, func()) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	deliveries := make(chan broker.Delivery, 16)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = b.Subscribe(ctx, broker.SubscribeOptions{Queue: queue, Prefetch: 4, Workers: 2},
+			func(_ context.Context, d broker.Delivery) { deliveries <- d })
+	}()
+
+	return deliveries, func() {
+		cancel()
+		<-done
+	}
+}
+
+func receive(t *testing.T, deliveries <-chan broker.Delivery) broker.Delivery {
+	t.Helper()
+
+	select {
+	case d := <-deliveries:
+		return d
+	case <-time.After(time.Second):
+		t.Fatal("no delivery received")
+		return nil
+	}
+}
+
+func expectNone(t *testing.T, deliveries <-chan broker.Delivery) {
+	t.Helper()
+
+	select {
+	case d := <-deliveries:
+		t.Fatalf("unexpected delivery of %q", d.Message().ID)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestPublishRoutesByKey(t *testing.T) {
+	b := newTestBroker(t, 0)
+	ctx := context.Background()
+
+	if err := b.Publish(ctx, broker.Message{ID: "1", RoutingKey: "user", Body: []byte("{}")}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if err := b.Publish(ctx, broker.Message{ID: "2", RoutingKey: "course"}); !errors.Is(err, ErrUnroutable) {
+		t.Fatalf("Publish() error = %v, want %v", err, ErrUnroutable)
+	}
+
+	for _, queue := range []string{"notification", "audit"} {
+		deliveries, cancel := subscribe(t, b, queue)
+		if d := receive(t, deliveries); d.Message().ID != "1" {
+			t.Fatalf("%s received %q, want 1", queue, d.Message().ID)
+		} else if err := d.Ack(); err != nil {
+			t.Fatalf("Ack() error = %v", err)
+		}
+		expectNone(t, deliveries)
+		cancel()
+	}
+
+	deliveries, cancel := subscribe(t, b, "billing")
+	expectNone(t, deliveries)
+	cancel()
+}
+
+func TestRequeueRedelivers(t *testing.T) {
+	b := newTestBroker(t, 0)
+	deliveries, cancel := subscribe(t, b, "notification")
+	defer cancel()
+
+	if err := b.Publish(context.Background(), broker.Message{ID: "1", RoutingKey: "user"}); err != nil {
+		t.Fatal(err)
+	}
+
+	d := receive(t, deliveries)
+	if d.Redelivered() {
+		t.Fatal("first delivery is marked redelivered")
+	}
+	if err := d.Requeue(); err != nil {
+		t.Fatalf("Requeue() error = %v", err)
+	}
+	if err := d.Ack(); err == nil {
+		t.Fatal("Ack() of a settled delivery succeeded")
+	}
+
+	d = receive(t, deliveries)
+	if !d.Redelivered() || d.Message().ID != "1" {
+		t.Fatalf("redelivery = %q, redelivered %v", d.Message().ID, d.Redelivered())
+	}
+	if err := d.Ack(); err != nil {
+		t.Fatalf("Ack() error = %v", err)
+	}
+	expectNone(t, deliveries)
+}
+
+func TestRetryDeadLetters(t *testing.T) {
+	b := newTestBroker(t, 2)
+	deliveries, cancel := subscribe(t, b, "notification")
+	defer cancel()
+
+	ctx := context.Background()
+	if err := b.Publish(ctx, broker.Message{ID: "1", RoutingKey: "user"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the first delivery and two retries
+	for i := 0; i < 3; i++ {
+		d := receive(t, deliveries)
+		if got := rabbit.RetryCount(d.Message().Headers); got != i {
+			t.Fatalf("retry count = %d, want %d", got, i)
+		}
+		if err := d.Retry(ctx, errors.New("gateway down")); err != nil {
+			t.Fatalf("Retry() error = %v", err)
+		}
+	}
+	expectNone(t, deliveries)
+
+	letters, err := b.List(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(letters) != 1 {
+		t.Fatalf("dead letters = %d, want 1", len(letters))
+	}
+	if l := letters[0]; l.ID != "1" || l.OriginalQueue != "notification" ||
+		l.RetryCount != 2 || l.LastError != "gateway down" {
+		t.Fatalf("dead letter = %+v", l)
+	}
+
+	// a replayed message is delivered again with a reset retry count
+	if err := b.Replay(ctx, "1"); err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	if d := receive(t, deliveries); rabbit.RetryCount(d.Message().Headers) != 0 {
+		t.Fatalf("replayed retry count = %d, want 0", rabbit.RetryCount(d.Message().Headers))
+	} else {
+		d.Ack()
+	}
+}
+
+func TestUnsubscribeRequeuesUnacked(t *testing.T) {
+	b := newTestBroker(t, 0)
+	deliveries, cancel := subscribe(t, b, "notification")
+
+	if err := b.Publish(context.Background(), broker.Message{ID: "1", RoutingKey: "user"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the delivery is never settled before the subscription ends
+	receive(t, deliveries)
+	cancel()
+
+	deliveries, cancel = subscribe(t, b, "notification")
+	defer cancel()
+
+	d := receive(t, deliveries)
+	if d.Message().ID != "1" || !d.Redelivered() {
+		t.Fatalf("redelivery = %q, redelivered %v", d.Message().ID, d.Redelivered())
+	}
+	d.Ack()
+}
+
+func TestDelayKeepsRetryCount(t *testing.T) {
+	b := newTestBroker(t, 1)
+	deliveries, cancel := subscribe(t, b, "notification")
+	defer cancel()
+
+	ctx := context.Background()
+	if err := b.Publish(ctx, broker.Message{ID: "1", RoutingKey: "user"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// delays don't use up the retries
+	for i := 0; i < 3; i++ {
+		if err := receive(t, deliveries).Delay(ctx, time.Millisecond); err != nil {
+			t.Fatalf("Delay() error = %v", err)
+		}
+	}
+
+	d := receive(t, deliveries)
+	if got := rabbit.RetryCount(d.Message().Headers); got != 0 {
+		t.Fatalf("retry count = %d, want 0", got)
+	}
+	d.Ack()
+}
@@ -0,0 +1,132 @@
+package outbox
+
+import (
+	"context"
+	"dussh/internal/broker/envelope"
+	"dussh/internal/broker/publisher"
+	"dussh/internal/config"
+	"dussh/internal/domain/models"
+	"errors"
+	"go.uber.org/zap"
+	"sort"
+	"strconv"
+	"time"
+)
+
+var ErrRelayClosed = errors.New("outbox relay closed")
+
+type Repository interface {
+	ClaimOutboxMessages(ctx context.Context, limit int64, lease time.Duration) ([]*models.OutboxMessage, error)
+	MarkOutboxMessageSent(ctx context.Context, id int64) error
+	MarkOutboxMessageFailed(ctx context.Context, id int64, reason string, retryIn time.Duration) error
+}
+
+// Relay publishes messages stored in the outbox to the broker. Each message is
+// published at least once, failed publishes are retried with exponential backoff.
+type Relay struct {
+	repo      Repository
+	publisher publisher.Publisher[*envelope.Envelope]
+	cfg       config.Outbox
+
+	log *zap.Logger
+}
+
+func NewRelay(
+	repo Repository,
+	publisher publisher.Publisher[*envelope.Envelope],
+	cfg config.Outbox,
+	log *zap.Logger,
+) *Relay {
+	return &Relay{
+		repo:      repo,
+		publisher: publisher,
+		cfg:       cfg,
+		log:       log.Named("outbox.relay"),
+	}
+}
+
+// Run polls the outbox until the context is canceled.
+func (r *Relay) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// drain the outbox without waiting while full batches are claimed
+		for {
+			n, err := r.relay(ctx)
+			if err != nil {
+				r.log.Error("failed to relay outbox messages", zap.Error(err))
+			}
+			if err != nil || int64(n) < r.cfg.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ErrRelayClosed
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Relay) relay(ctx context.Context) (int, error) {
+	messages, err := r.repo.ClaimOutboxMessages(ctx, r.cfg.BatchSize, r.cfg.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	sort.Slice(messages, func(i, j int) bool {
+		return messages[i].ID < messages[j].ID
+	})
+
+	for _, m := range messages {
+		if err := r.publish(ctx, m); err != nil {
+			retryIn := r.backoff(m.Attempts)
+			r.log.Warn("failed to publish outbox message",
+				zap.Int64("id", m.ID),
+				zap.String("event_type", m.EventType),
+				zap.Int("attempts", m.Attempts),
+				zap.Duration("retry_in", retryIn),
+				zap.Error(err),
+			)
+
+			if err := r.repo.MarkOutboxMessageFailed(ctx, m.ID, err.Error(), retryIn); err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		if err := r.repo.MarkOutboxMessageSent(ctx, m.ID); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(messages), nil
+}
+
+func (r *Relay) publish(ctx context.Context, m *models.OutboxMessage) error {
+	env, err := envelope.Unmarshal(m.Payload, m.EventType)
+	if err != nil {
+		return err
+	}
+
+	// rows written before envelopes were introduced hold a bare payload
+	if env.ID == "" {
+		env.ID = "outbox-" + strconv.FormatInt(m.ID, 10)
+		env.OccurredAt = m.CreatedAt
+		env.Producer = envelope.Producer
+	}
+
+	return r.publisher.Publish(ctx, m.RoutingKey, env)
+}
+
+// backoff returns the delay before the next publish attempt.
+func (r *Relay) backoff(attempts int) time.Duration {
+	delay := r.cfg.RetryInterval
+	for i := 1; i < attempts && delay < r.cfg.MaxRetryInterval; i++ {
+		delay *= 2
+	}
+
+	return min(delay, r.cfg.MaxRetryInterval)
+}
@@ -0,0 +1,471 @@
+package pgqueue
+
+import (
+	"context"
+	"crypto/rand"
+	"dussh/internal/broker"
+	"dussh/internal/broker/rabbit"
+	"dussh/internal/config"
+	"dussh/internal/domain/models"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+var ErrUnroutable = errors.New("message is not routed to any queue")
+
+type Repository interface {
+	EnqueueJobs(ctx context.Context, queues []string, job *models.Job) error
+	ClaimJob(ctx context.Context, queue string, visibility time.Duration) (*models.Job, error)
+	DeleteJob(ctx context.Context, job *models.Job) error
+	RescheduleJob(ctx context.Context, job *models.Job, runIn time.Duration, reason *string) error
+	DeadLetterJob(ctx context.Context, job *models.Job, reason string) error
+	ListDeadLetteredJobs(ctx context.Context, limit int64) ([]*models.Job, error)
+	ReplayDeadLetteredJob(ctx context.Context, messageID string) error
+	DiscardDeadLetteredJob(ctx context.Context, messageID string) error
+	ListenJobs(ctx context.Context, notify func(queue string)) error
+}
+
+// Broker is a broker backed by a Postgres jobs table for deployments without
+// RabbitMQ. Consumers claim jobs with SKIP LOCKED and are woken up by
+// LISTEN/NOTIFY, polling covers missed notifications, delayed retries and
+// expired visibility timeouts. A claimed job that is not settled within the
+// visibility timeout is delivered again, up to the max attempts.
+type Broker struct {
+	repo     Repository
+	exchange string
+	fanout   bool
+	bindings map[string][]string
+	queues   map[string]bool
+	cfg      config.NotificationConsumer
+	jq       config.JobQueue
+
+	mu      sync.Mutex
+	waiters map[string][]chan struct{}
+
+	closed    chan struct{}
+	closeOnce sync.Once
+	stop      func()
+	done      chan struct{}
+
+	log *zap.Logger
+}
+
+// New returns a broker with the queues and bindings of the RabbitMQ topology
+// and starts listening for notifications.
+func New(repo Repository, rc config.RabbitMQ, jq config.JobQueue, log *zap.Logger) (*Broker, error) {
+	topology := rabbit.TopologyFromConfig(rc)
+	if err := rabbit.ValidateTopology(rc, topology); err != nil {
+		return nil, err
+	}
+	if jq.MaxAttempts <= rc.NotificationConsumer.MaxRetries {
+		return nil, fmt.Errorf("job max attempts %d must exceed the %d retries",
+			jq.MaxAttempts, rc.NotificationConsumer.MaxRetries)
+	}
+
+	b := &Broker{
+		repo:     repo,
+		exchange: rc.NotificationPublisher.Exchange,
+		bindings: make(map[string][]string),
+		queues:   make(map[string]bool, len(topology.Queues)),
+		cfg:      rc.NotificationConsumer,
+		jq:       jq,
+		waiters:  make(map[string][]chan struct{}),
+		closed:   make(chan struct{}),
+		done:     make(chan struct{}),
+		log:      log.Named("pgqueue.broker"),
+	}
+
+	for _, e := range topology.Exchanges {
+		if e.Name == b.exchange {
+			b.fanout = e.Kind == amqp.ExchangeFanout
+		}
+	}
+	for _, q := range topology.Queues {
+		b.queues[q.Name] = true
+	}
+	for _, bd := range topology.Bindings {
+		if bd.Exchange == b.exchange {
+			b.bindings[bd.RoutingKey] = append(b.bindings[bd.RoutingKey], bd.Queue)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	b.stop = cancel
+	go b.listen(ctx)
+
+	return b, nil
+}
+
+// listen wakes up subscribers of the notified queue, reconnecting with
+// backoff when the listening connection fails.
+func (b *Broker) listen(ctx context.Context) {
+	defer close(b.done)
+
+	delay := b.jq.ListenRetryDelay
+	for {
+		err := b.repo.ListenJobs(ctx, func(queue string) {
+			delay = b.jq.ListenRetryDelay
+			b.wake(queue)
+		})
+		if ctx.Err() != nil {
+			return
+		}
+
+		b.log.Warn("failed to listen for jobs, polling until reconnected",
+			zap.Duration("retry_in", delay),
+			zap.Error(err),
+		)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, b.jq.PollInterval)
+	}
+}
+
+// Publish stores the message for every queue bound with its routing key, or
+// for every bound queue if the exchange is a fanout.
+func (b *Broker) Publish(ctx context.Context, msg broker.Message) error {
+	var queues []string
+	if b.fanout {
+		for _, qs := range b.bindings {
+			queues = append(queues, qs...)
+		}
+	} else {
+		queues = b.bindings[msg.RoutingKey]
+	}
+	if len(queues) == 0 {
+		return fmt.Errorf("%w: %q", ErrUnroutable, msg.RoutingKey)
+	}
+
+	headers := msg.Headers
+	if headers == nil {
+		headers = map[string]any{}
+	}
+	rawHeaders, err := json.Marshal(headers)
+	if err != nil {
+		return err
+	}
+
+	id := msg.ID
+	if id == "" {
+		id = newMessageID()
+	}
+	publishedAt := msg.Timestamp
+	if publishedAt.IsZero() {
+		publishedAt = time.Now()
+	}
+
+	return b.repo.EnqueueJobs(ctx, queues, &models.Job{
+		MessageID:     id,
+		RoutingKey:    msg.RoutingKey,
+		Type:          msg.Type,
+		CorrelationID: msg.CorrelationID,
+		Producer:      msg.Producer,
+		ContentType:   msg.ContentType,
+		Headers:       rawHeaders,
+		Body:          msg.Body,
+		PublishedAt:   publishedAt.UTC(),
+	})
+}
+
+// Subscribe delivers jobs of the queue to handler until ctx is canceled or
+// the broker is closed. On cancellation it stops claiming and waits for
+// in-flight deliveries, unsettled jobs are redelivered after their
+// visibility timeout.
+func (b *Broker) Subscribe(
+	ctx context.Context,
+	opts broker.SubscribeOptions,
+	handler func(context.Context, broker.Delivery),
+) error {
+	if !b.queues[opts.Queue] {
+		return fmt.Errorf("queue %q is not declared", opts.Queue)
+	}
+
+	wake := b.register(opts.Queue)
+	defer b.unregister(opts.Queue, wake)
+
+	b.log.Info("subscribed to queue",
+		zap.String("queue", opts.Queue),
+		zap.String("consumer", opts.Consumer),
+		zap.Int("prefetch", opts.Prefetch),
+		zap.Int("workers", opts.Workers),
+	)
+
+	var slots chan struct{}
+	if opts.Prefetch > 0 {
+		slots = make(chan struct{}, opts.Prefetch)
+	}
+	release := func() {
+		if slots != nil {
+			<-slots
+		}
+	}
+
+	handlerCtx := context.WithoutCancel(ctx)
+	pool := broker.NewWorkerPool(opts.Workers, func(d *delivery) { handler(handlerCtx, d) })
+
+	var err error
+	for {
+		if slots != nil {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				err = ctx.Err()
+			case <-b.closed:
+				err = broker.ErrClosed
+			}
+			if err != nil {
+				break
+			}
+		}
+
+		job, claimErr := b.repo.ClaimJob(ctx, opts.Queue, b.jq.VisibilityTimeout)
+		if claimErr != nil || job == nil {
+			release()
+			if claimErr != nil && ctx.Err() == nil {
+				b.log.Warn("failed to claim job", zap.String("queue", opts.Queue), zap.Error(claimErr))
+			}
+			if err = b.wait(ctx, wake); err != nil {
+				break
+			}
+			continue
+		}
+
+		if job.Attempts > b.jq.MaxAttempts {
+			release()
+			b.deadLetterExhausted(ctx, job)
+			continue
+		}
+
+		d := &delivery{broker: b, job: job, msg: toMessage(job), release: release}
+		key := d.msg.ID
+		if opts.Key != nil {
+			key = opts.Key(d.msg)
+		}
+		pool.Dispatch(key, d)
+	}
+
+	pool.Drain()
+
+	return err
+}
+
+// deadLetterExhausted dead-letters a job whose earlier deliveries all expired
+// without being settled, it is not handed to the handler again until replayed.
+func (b *Broker) deadLetterExhausted(ctx context.Context, job *models.Job) {
+	reason := fmt.Sprintf("delivered %d times without being settled", job.Attempts-1)
+	if err := b.repo.DeadLetterJob(ctx, job, reason); err != nil {
+		b.log.Warn("failed to dead-letter job that exceeded the delivery attempts",
+			zap.String("queue", job.Queue),
+			zap.String("message_id", job.MessageID),
+			zap.Error(err),
+		)
+		return
+	}
+
+	b.log.Warn("dead-lettered job that exceeded the delivery attempts",
+		zap.String("queue", job.Queue),
+		zap.String("message_id", job.MessageID),
+	)
+}
+
+// wait blocks until the queue is notified or the poll interval passes.
+func (b *Broker) wait(ctx context.Context, wake chan struct{}) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-b.closed:
+		return broker.ErrClosed
+	case <-wake:
+		return nil
+	case <-time.After(b.jq.PollInterval):
+		return nil
+	}
+}
+
+func (b *Broker) register(queue string) chan struct{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	wake := make(chan struct{}, 1)
+	b.waiters[queue] = append(b.waiters[queue], wake)
+
+	return wake
+}
+
+func (b *Broker) unregister(queue string, wake chan struct{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	waiters := b.waiters[queue]
+	for i, w := range waiters {
+		if w == wake {
+			b.waiters[queue] = append(waiters[:i], waiters[i+1:]...)
+			return
+		}
+	}
+}
+
+func (b *Broker) wake(queue string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, w := range b.waiters[queue] {
+		select {
+		case w <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// List returns up to limit dead-lettered jobs.
+func (b *Broker) List(ctx context.Context, limit int) ([]*models.DeadLetter, error) {
+	jobs, err := b.repo.ListDeadLetteredJobs(ctx, int64(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	letters := make([]*models.DeadLetter, 0, len(jobs))
+	for _, job := range jobs {
+		letters = append(letters, toDeadLetter(job))
+	}
+
+	return letters, nil
+}
+
+// Replay returns the job to its queue with reset attempts and retry counter.
+func (b *Broker) Replay(ctx context.Context, id string) error {
+	return b.repo.ReplayDeadLetteredJob(ctx, id)
+}
+
+// Discard deletes the dead-lettered job.
+func (b *Broker) Discard(ctx context.Context, id string) error {
+	return b.repo.DiscardDeadLetteredJob(ctx, id)
+}
+
+// Close stops subscriptions and the notification listener.
+func (b *Broker) Close(ctx context.Context) error {
+	b.closeOnce.Do(func() {
+		close(b.closed)
+		b.stop()
+	})
+
+	select {
+	case <-b.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type delivery struct {
+	broker  *Broker
+	job     *models.Job
+	msg     broker.Message
+	release func()
+	settled sync.Once
+}
+
+func (d *delivery) Message() broker.Message {
+	return d.msg
+}
+
+// Redelivered reports whether the job was delivered before, including
+// deliveries before it was dead-lettered and replayed.
+func (d *delivery) Redelivered() bool {
+	return d.job.Attempts > 1 || d.job.Replays > 0
+}
+
+// Ack deletes the job. It returns ErrJobLeaseLost if the visibility timeout
+// expired and the job was delivered again or replayed in the meantime.
+func (d *delivery) Ack() error {
+	defer d.settle()
+	return d.broker.repo.DeleteJob(context.Background(), d.job)
+}
+
+func (d *delivery) Requeue() error {
+	defer d.settle()
+	return d.broker.repo.RescheduleJob(context.Background(), d.job, 0, nil)
+}
+
+// Delay makes the job ready again after the delay, the retry count is kept.
+func (d *delivery) Delay(ctx context.Context, delay time.Duration) error {
+	defer d.settle()
+	return d.broker.repo.RescheduleJob(ctx, d.job, delay, nil)
+}
+
+// Retry schedules the job for another attempt after an exponential backoff,
+// or dead-letters it once retries are exhausted.
+func (d *delivery) Retry(ctx context.Context, reason error) error {
+	if d.job.RetryCount >= d.broker.cfg.MaxRetries {
+		return d.DeadLetter(ctx, reason)
+	}
+
+	defer d.settle()
+	msg := reason.Error()
+	delay := rabbit.RetryDelay(d.broker.cfg, d.job.RetryCount)
+
+	return d.broker.repo.RescheduleJob(ctx, d.job, delay, &msg)
+}
+
+func (d *delivery) DeadLetter(ctx context.Context, reason error) error {
+	defer d.settle()
+	return d.broker.repo.DeadLetterJob(ctx, d.job, reason.Error())
+}
+
+func (d *delivery) settle() {
+	d.settled.Do(d.release)
+}
+
+func toMessage(job *models.Job) broker.Message {
+	var headers map[string]any
+	if err := json.Unmarshal(job.Headers, &headers); err != nil {
+		headers = map[string]any{}
+	}
+
+	return broker.Message{
+		ID:            job.MessageID,
+		RoutingKey:    job.RoutingKey,
+		Type:          job.Type,
+		CorrelationID: job.CorrelationID,
+		Producer:      job.Producer,
+		Timestamp:     job.PublishedAt,
+		ContentType:   job.ContentType,
+		Headers:       headers,
+		Body:          job.Body,
+	}
+}
+
+func toDeadLetter(job *models.Job) *models.DeadLetter {
+	l := &models.DeadLetter{
+		ID:             job.MessageID,
+		OriginalQueue:  job.Queue,
+		RetryCount:     job.RetryCount,
+		DeadLetteredAt: job.DeadLetteredAt,
+		ContentType:    job.ContentType,
+		Body:           string(job.Body),
+	}
+	if job.LastError != nil {
+		l.LastError = *job.LastError
+	}
+
+	return l
+}
+
+func newMessageID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("job-%d", time.Now().UnixNano())
+	}
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,264 @@
+package pgqueue
+
+import (
+	"context"
+	"dussh/internal/broker"
+	"dussh/internal/broker/rabbit"
+	"dussh/internal/config"
+	domainerrors "dussh/internal/domain/errors"
+	"dussh/internal/domain/models"
+	"errors"
+	"go.uber.org/zap"
+	"sync"
+	"testing"
+	"time"
+)
+
+type rescheduled struct {
+	id     int64
+	runIn  time.Duration
+	reason *string
+}
+
+// fakeRepo hands out the queued jobs once, counting the delivery like
+// ClaimJob does, and records how they are settled.
+type fakeRepo struct {
+	Repository
+
+	mu          sync.Mutex
+	jobs        []*models.Job
+	leaseLost   bool
+	deleted     []int64
+	rescheduled []rescheduled
+	deadLetters map[int64]string
+	dead        []*models.Job
+}
+
+func (r *fakeRepo) ClaimJob(context.Context, string, time.Duration) (*models.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.jobs) == 0 {
+		return nil, nil
+	}
+	job := *r.jobs[0]
+	job.Attempts++
+	r.jobs = r.jobs[1:]
+	return &job, nil
+}
+
+func (r *fakeRepo) DeleteJob(_ context.Context, job *models.Job) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.leaseLost {
+		return domainerrors.ErrJobLeaseLost
+	}
+	r.deleted = append(r.deleted, job.ID)
+	return nil
+}
+
+func (r *fakeRepo) RescheduleJob(_ context.Context, job *models.Job, runIn time.Duration, reason *string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rescheduled = append(r.rescheduled, rescheduled{id: job.ID, runIn: runIn, reason: reason})
+	return nil
+}
+
+func (r *fakeRepo) DeadLetterJob(_ context.Context, job *models.Job, reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.deadLetters == nil {
+		r.deadLetters = make(map[int64]string)
+	}
+	r.deadLetters[job.ID] = reason
+	r.dead = append(r.dead, job)
+	return nil
+}
+
+func (r *fakeRepo) ReplayDeadLetteredJob(_ context.Context, messageID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, job := range r.dead {
+		if job.MessageID == messageID {
+			replayed := *job
+			replayed.Attempts, replayed.RetryCount = 0, 0
+			replayed.Replays++
+			r.dead = append(r.dead[:i], r.dead[i+1:]...)
+			r.jobs = append(r.jobs, &replayed)
+			return nil
+		}
+	}
+	return domainerrors.ErrDeadLetterNotFound
+}
+
+func (r *fakeRepo) deadLetter(id int64) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reason, ok := r.deadLetters[id]
+	return reason, ok
+}
+
+func (r *fakeRepo) ListenJobs(ctx context.Context, _ func(string)) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func testConfig() (config.RabbitMQ, config.JobQueue) {
+	rc := config.RabbitMQ{
+		NotificationPublisher: config.NotificationPublisher{Exchange: "notification"},
+		NotificationConsumer: config.NotificationConsumer{
+			Queue:         "notification",
+			RetryQueue:    "notification.retry",
+			MaxRetries:    2,
+			RetryDelay:    time.Second,
+			MaxRetryDelay: time.Minute,
+		},
+		DeadLetter: config.DeadLetter{Exchange: "notification.dlx", Queue: "notification.dead"},
+	}
+	jq := config.JobQueue{
+		PollInterval:      10 * time.Millisecond,
+		VisibilityTimeout: time.Minute,
+		ListenRetryDelay:  time.Millisecond,
+		MaxAttempts:       5,
+	}
+
+	return rc, jq
+}
+
+func newTestBroker(t *testing.T, repo *fakeRepo) *Broker {
+	t.Helper()
+
+	rc, jq := testConfig()
+	b, err := New(repo, rc, jq, zap.NewNop())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	t.Cleanup(func() { b.Close(context.Background()) })
+
+	return b
+}
+
+func TestNewRequiresAttemptsBeyondRetries(t *testing.T) {
+	rc, jq := testConfig()
+	jq.MaxAttempts = rc.NotificationConsumer.MaxRetries
+
+	if _, err := New(&fakeRepo{}, rc, jq, zap.NewNop()); err == nil {
+		t.Fatal("New() error = nil, want max attempts error")
+	}
+}
+
+func TestAckAfterLeaseLost(t *testing.T) {
+	repo := &fakeRepo{
+		jobs:      []*models.Job{{ID: 1}, {ID: 2, Attempts: 1}},
+		leaseLost: true,
+	}
+	b := newTestBroker(t, repo)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	deliveries := make(chan broker.Delivery, 2)
+	go b.Subscribe(ctx, broker.SubscribeOptions{Queue: "notification", Prefetch: 1, Workers: 1},
+		func(_ context.Context, d broker.Delivery) { deliveries <- d })
+
+	d := <-deliveries
+	if d.Redelivered() {
+		t.Fatal("first delivery is marked redelivered")
+	}
+	if err := d.Ack(); !errors.Is(err, domainerrors.ErrJobLeaseLost) {
+		t.Fatalf("Ack() error = %v, want %v", err, domainerrors.ErrJobLeaseLost)
+	}
+
+	// the prefetch slot is released even though the lease was lost
+	select {
+	case d = <-deliveries:
+	case <-time.After(time.Second):
+		t.Fatal("next job was not claimed after the lost lease")
+	}
+	if !d.Redelivered() {
+		t.Fatal("job claimed twice is not marked redelivered")
+	}
+}
+
+func TestReplayExhaustedJob(t *testing.T) {
+	repo := &fakeRepo{}
+	b := newTestBroker(t, repo)
+	repo.jobs = []*models.Job{{ID: 1, MessageID: "m1", Attempts: b.jq.MaxAttempts}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	deliveries := make(chan broker.Delivery, 1)
+	go b.Subscribe(ctx, broker.SubscribeOptions{Queue: "notification", Prefetch: 1, Workers: 1},
+		func(_ context.Context, d broker.Delivery) { deliveries <- d })
+
+	deadline := time.After(time.Second)
+	for {
+		if _, ok := repo.deadLetter(1); ok {
+			break
+		}
+		select {
+		case d := <-deliveries:
+			t.Fatalf("job that exceeded the delivery attempts was delivered: %+v", d.Message())
+		case <-deadline:
+			t.Fatal("job that exceeded the delivery attempts was not dead-lettered")
+		case <-time.After(b.jq.PollInterval):
+		}
+	}
+
+	if err := b.Replay(ctx, "m1"); err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+
+	var d broker.Delivery
+	select {
+	case d = <-deliveries:
+	case <-time.After(time.Second):
+		t.Fatal("replayed job was not delivered again")
+	}
+	if !d.Redelivered() {
+		t.Error("replayed job is not marked redelivered")
+	}
+	if err := d.Ack(); err != nil {
+		t.Fatalf("Ack() error = %v", err)
+	}
+	if len(repo.deleted) != 1 || repo.deleted[0] != 1 {
+		t.Fatalf("deleted = %v, want the replayed job", repo.deleted)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	repo := &fakeRepo{}
+	b := newTestBroker(t, repo)
+	reason := errors.New("gateway down")
+
+	for retries := 0; retries <= b.cfg.MaxRetries; retries++ {
+		job := &models.Job{ID: int64(retries + 1), Attempts: retries + 1, RetryCount: retries}
+		d := &delivery{broker: b, job: job, msg: toMessage(job), release: func() {}}
+		if err := d.Retry(context.Background(), reason); err != nil {
+			t.Fatalf("Retry() error = %v", err)
+		}
+	}
+
+	if len(repo.rescheduled) != b.cfg.MaxRetries {
+		t.Fatalf("rescheduled %d jobs, want %d", len(repo.rescheduled), b.cfg.MaxRetries)
+	}
+	for i, r := range repo.rescheduled {
+		if want := rabbit.RetryDelay(b.cfg, i); r.runIn != want {
+			t.Fatalf("retry %d delay = %v, want %v", i, r.runIn, want)
+		}
+		if r.reason == nil || *r.reason != reason.Error() {
+			t.Fatalf("retry %d reason = %v, want %q", i, r.reason, reason)
+		}
+	}
+
+	exhausted := int64(b.cfg.MaxRetries + 1)
+	if got := repo.deadLetters[exhausted]; got != reason.Error() {
+		t.Fatalf("dead letter reason = %q, want %q", got, reason)
+	}
+}
@@ -0,0 +1,57 @@
+package broker
+
+import (
+	"hash/fnv"
+	"sync"
+)
+
+// WorkerPool handles deliveries concurrently. Deliveries with the same key
+// always go to the same worker, so they are handled in order.
+type WorkerPool[D any] struct {
+	queues []chan D
+	next   int
+	wg     sync.WaitGroup
+}
+
+func NewWorkerPool[D any](workers int, handle func(D)) *WorkerPool[D] {
+	workers = max(workers, 1)
+
+	p := &WorkerPool[D]{queues: make([]chan D, workers)}
+	for i := range p.queues {
+		q := make(chan D, 1)
+		p.queues[i] = q
+
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			for d := range q {
+				handle(d)
+			}
+		}()
+	}
+
+	return p
+}
+
+// Dispatch blocks while the worker for the key is busy. Deliveries without
+// a key are spread over the workers. Dispatch is not safe for concurrent use.
+func (p *WorkerPool[D]) Dispatch(key string, d D) {
+	if key == "" {
+		p.next = (p.next + 1) % len(p.queues)
+		p.queues[p.next] <- d
+		return
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(key))
+
+	p.queues[h.Sum32()%uint32(len(p.queues))] <- d
+}
+
+// Drain waits until every dispatched delivery is handled.
+func (p *WorkerPool[D]) Drain() {
+	for _, q := range p.queues {
+		close(q)
+	}
+	p.wg.Wait()
+}
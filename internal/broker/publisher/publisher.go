@@ -0,0 +1,59 @@
+package publisher
+
+import (
+	"context"
+	"dussh/internal/broker"
+	"dussh/internal/broker/envelope"
+	"dussh/internal/domain/models"
+	"encoding/json"
+)
+
+type Publisher[T any] interface {
+	Publish(context.Context, string, T) error
+}
+
+// NewEventPublisher returns a publisher that wraps events into an envelope.
+func NewEventPublisher[T models.DomainEvent](b broker.Publisher) Publisher[T] {
+	return &eventPublisher[T]{envelopes: NewEnvelopePublisher(b)}
+}
+
+type eventPublisher[T models.DomainEvent] struct {
+	envelopes Publisher[*envelope.Envelope]
+}
+
+func (p *eventPublisher[T]) Publish(ctx context.Context, key string, event T) error {
+	env, err := envelope.New(ctx, event)
+	if err != nil {
+		return err
+	}
+
+	return p.envelopes.Publish(ctx, key, env)
+}
+
+// NewEnvelopePublisher returns a publisher of already built envelopes.
+func NewEnvelopePublisher(b broker.Publisher) Publisher[*envelope.Envelope] {
+	return &envelopePublisher{broker: b}
+}
+
+type envelopePublisher struct {
+	broker broker.Publisher
+}
+
+// Publish returns once the broker has accepted the envelope.
+func (p *envelopePublisher) Publish(ctx context.Context, key string, env *envelope.Envelope) error {
+	bytes, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	return p.broker.Publish(ctx, broker.Message{
+		ID:            env.ID,
+		RoutingKey:    key,
+		Type:          env.Type,
+		CorrelationID: env.CorrelationID,
+		Producer:      env.Producer,
+		Timestamp:     env.OccurredAt,
+		ContentType:   "application/json",
+		Body:          bytes,
+	})
+}
@@ -0,0 +1,141 @@
+package rabbit
+
+import (
+	"context"
+	"dussh/internal/broker"
+	"dussh/internal/config"
+	"errors"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"time"
+)
+
+// NewPublisher returns a broker.Publisher that publishes to the notification exchange.
+func NewPublisher(conn *Connection, rc config.RabbitMQ) broker.Publisher {
+	return &publisher{conn: conn, rc: rc}
+}
+
+type publisher struct {
+	conn *Connection
+	rc   config.RabbitMQ
+}
+
+func (p *publisher) Publish(ctx context.Context, msg broker.Message) error {
+	return p.conn.Publish(
+		ctx,
+		p.rc.NotificationPublisher.Exchange,
+		msg.RoutingKey,
+		true,
+		amqp.Publishing{
+			Headers:       msg.Headers,
+			ContentType:   msg.ContentType,
+			DeliveryMode:  amqp.Persistent,
+			MessageId:     msg.ID,
+			CorrelationId: msg.CorrelationID,
+			Timestamp:     msg.Timestamp,
+			Type:          msg.Type,
+			AppId:         msg.Producer,
+			Body:          msg.Body,
+		},
+	)
+}
+
+// NewSubscriber returns a broker.Subscriber with retries and dead-lettering
+// configured by the notification consumer settings.
+func NewSubscriber(conn *Connection, rc config.RabbitMQ) broker.Subscriber {
+	return &subscriber{conn: conn, rc: rc}
+}
+
+type subscriber struct {
+	conn *Connection
+	rc   config.RabbitMQ
+}
+
+func (s *subscriber) Subscribe(
+	ctx context.Context,
+	opts broker.SubscribeOptions,
+	handler func(context.Context, broker.Delivery),
+) error {
+	key := func(d amqp.Delivery) string {
+		if opts.Key == nil {
+			return d.MessageId
+		}
+		return opts.Key(toMessage(d))
+	}
+
+	err := s.conn.Consume(
+		ctx,
+		ConsumeOptions{
+			Queue:    opts.Queue,
+			Consumer: opts.Consumer,
+			Prefetch: opts.Prefetch,
+			Workers:  opts.Workers,
+			Key:      key,
+		},
+		func(ctx context.Context, d amqp.Delivery) {
+			handler(ctx, &delivery{d: d, conn: s.conn, rc: s.rc})
+		},
+	)
+	if errors.Is(err, ErrConnectionClosed) {
+		return broker.ErrClosed
+	}
+
+	return err
+}
+
+type delivery struct {
+	d    amqp.Delivery
+	conn *Connection
+	rc   config.RabbitMQ
+}
+
+func (d *delivery) Message() broker.Message {
+	return toMessage(d.d)
+}
+
+func (d *delivery) Redelivered() bool {
+	return d.d.Redelivered
+}
+
+func (d *delivery) Ack() error {
+	return d.d.Ack(false)
+}
+
+func (d *delivery) Requeue() error {
+	return d.d.Nack(false, true)
+}
+
+func (d *delivery) Delay(ctx context.Context, delay time.Duration) error {
+	return d.settle(Delay(ctx, d.conn, d.rc, d.d, delay))
+}
+
+func (d *delivery) Retry(ctx context.Context, reason error) error {
+	return d.settle(Retry(ctx, d.conn, d.rc, d.d, reason))
+}
+
+func (d *delivery) DeadLetter(ctx context.Context, reason error) error {
+	return d.settle(DeadLetter(ctx, d.conn, d.rc, d.d, reason))
+}
+
+// settle acks the delivery once it has been moved to the retry or
+// dead-letter queue, or requeues it if moving failed.
+func (d *delivery) settle(moveErr error) error {
+	if moveErr != nil {
+		return errors.Join(moveErr, d.d.Nack(false, true))
+	}
+
+	return d.d.Ack(false)
+}
+
+func toMessage(d amqp.Delivery) broker.Message {
+	return broker.Message{
+		ID:            d.MessageId,
+		RoutingKey:    d.RoutingKey,
+		Type:          d.Type,
+		CorrelationID: d.CorrelationId,
+		Producer:      d.AppId,
+		Timestamp:     d.Timestamp,
+		ContentType:   d.ContentType,
+		Headers:       d.Headers,
+		Body:          d.Body,
+	}
+}
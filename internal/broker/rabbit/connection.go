@@ -0,0 +1,457 @@
+package rabbit
+
+import (
+	"context"
+	"dussh/internal/broker"
+	"dussh/internal/config"
+	"errors"
+	"fmt"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+var (
+	ErrConnectionClosed = errors.New("rabbitmq connection closed")
+	ErrNotConnected     = errors.New("rabbitmq is not connected")
+	ErrPublishNacked    = errors.New("message was not acknowledged by rabbitmq")
+	ErrUnroutable       = errors.New("message was returned as unroutable")
+)
+
+// Connection keeps a single AMQP connection open and re-dials it with backoff
+// when the broker closes it. The topology is declared on every connect,
+// publishing goes through a pool of channels in confirm mode and consumers
+// are resubscribed after every reconnect.
+type Connection struct {
+	url               string
+	topology          config.Topology
+	poolSize          int
+	reconnectDelay    time.Duration
+	maxReconnectDelay time.Duration
+
+	mu        sync.Mutex
+	lastErr   error
+	conn      *amqp.Connection
+	pool      chan *publishChannel
+	connected chan struct{}
+	closed    chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+
+	log *zap.Logger
+}
+
+// publishChannel is a confirm mode channel with its return listener.
+type publishChannel struct {
+	ch      *amqp.Channel
+	returns chan amqp.Return
+}
+
+// NewConnection validates the topology and returns a connection that dials
+// the broker in the background. Use WaitConnected to block until the first
+// connection is established and the topology is declared.
+func NewConnection(rc config.RabbitMQ, log *zap.Logger) (*Connection, error) {
+	topology := TopologyFromConfig(rc)
+	if err := ValidateTopology(rc, topology); err != nil {
+		return nil, err
+	}
+
+	c := &Connection{
+		url:               BuildRabbitMQURL(rc),
+		topology:          topology,
+		poolSize:          rc.ChannelPoolSize,
+		reconnectDelay:    rc.ReconnectDelay,
+		maxReconnectDelay: rc.MaxReconnectDelay,
+		connected:         make(chan struct{}),
+		closed:            make(chan struct{}),
+		done:              make(chan struct{}),
+		log:               log.Named("rabbit.connection"),
+	}
+
+	go c.run()
+
+	return c, nil
+}
+
+func (c *Connection) run() {
+	defer close(c.done)
+
+	delay := c.reconnectDelay
+	for {
+		conn, err := c.dial()
+		if err != nil {
+			c.mu.Lock()
+			c.lastErr = err
+			c.mu.Unlock()
+
+			c.log.Warn("failed to connect to rabbitmq",
+				zap.Duration("retry_in", delay),
+				zap.Error(err),
+			)
+
+			select {
+			case <-c.closed:
+				return
+			case <-time.After(delay):
+			}
+
+			delay = min(delay*2, c.maxReconnectDelay)
+			continue
+		}
+
+		delay = c.reconnectDelay
+		notifyClose := conn.NotifyClose(make(chan *amqp.Error, 1))
+
+		c.mu.Lock()
+		c.lastErr = nil
+		c.conn = conn
+		c.pool = make(chan *publishChannel, c.poolSize)
+		close(c.connected)
+		c.mu.Unlock()
+
+		c.log.Info("connected to rabbitmq")
+
+		select {
+		case <-c.closed:
+			c.mu.Lock()
+			c.drainPool()
+			c.mu.Unlock()
+
+			if err := conn.Close(); err != nil && !errors.Is(err, amqp.ErrClosed) {
+				c.log.Error("failed to close rabbitmq connection", zap.Error(err))
+			}
+			return
+		case err := <-notifyClose:
+			c.log.Warn("rabbitmq connection lost, reconnecting", zap.Error(err))
+		}
+
+		c.mu.Lock()
+		c.conn = nil
+		c.drainPool()
+		c.connected = make(chan struct{})
+		c.mu.Unlock()
+	}
+}
+
+// dial connects to the broker and declares the topology.
+func (c *Connection) dial() (*amqp.Connection, error) {
+	conn, err := amqp.Dial(c.url)
+	if err != nil {
+		return nil, err
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if err := DeclareTopology(ch, c.topology); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if err := ch.Close(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+// WaitConnected blocks until the connection is established. If ctx is done
+// first, the error of the last connection attempt is returned along with it.
+func (c *Connection) WaitConnected(ctx context.Context) error {
+	if _, err := c.wait(ctx); err != nil {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		return errors.Join(err, c.lastErr)
+	}
+
+	return nil
+}
+
+func (c *Connection) wait(ctx context.Context) (*amqp.Connection, error) {
+	for {
+		c.mu.Lock()
+		conn, connected := c.conn, c.connected
+		c.mu.Unlock()
+
+		if conn != nil && !conn.IsClosed() {
+			return conn, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-c.closed:
+			return nil, ErrConnectionClosed
+		case <-connected:
+		}
+	}
+}
+
+// Channel opens a new channel on the current connection. The caller owns the
+// channel and must close it.
+func (c *Connection) Channel() (*amqp.Channel, error) {
+	c.mu.Lock()
+	conn := c.conn
+	c.mu.Unlock()
+
+	if conn == nil || conn.IsClosed() {
+		return nil, ErrNotConnected
+	}
+
+	return conn.Channel()
+}
+
+// Publish publishes the message and returns once the broker has confirmed it.
+// Mandatory messages that can not be routed to any queue return ErrUnroutable.
+func (c *Connection) Publish(
+	ctx context.Context,
+	exchange, key string,
+	mandatory bool,
+	msg amqp.Publishing,
+) error {
+	pc, err := c.acquire()
+	if err != nil {
+		return err
+	}
+
+	confirmation, err := pc.ch.PublishWithDeferredConfirmWithContext(ctx, exchange, key, mandatory, false, msg)
+	if err != nil {
+		c.discard(pc)
+		return err
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		// the confirmation of this channel may still arrive, do not reuse it
+		c.discard(pc)
+		return err
+	}
+
+	// a basic.return is always delivered before the confirmation of the same message
+	select {
+	case ret, ok := <-pc.returns:
+		if ok {
+			c.release(pc)
+			return fmt.Errorf("%w: %s", ErrUnroutable, ret.ReplyText)
+		}
+	default:
+	}
+
+	c.release(pc)
+	if !acked {
+		return ErrPublishNacked
+	}
+
+	return nil
+}
+
+func (c *Connection) acquire() (*publishChannel, error) {
+	c.mu.Lock()
+	conn, pool := c.conn, c.pool
+	c.mu.Unlock()
+
+	if conn == nil || conn.IsClosed() {
+		return nil, ErrNotConnected
+	}
+
+	if pc := pooled(pool); pc != nil {
+		return pc, nil
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ch.Confirm(false); err != nil {
+		ch.Close()
+		return nil, err
+	}
+
+	return &publishChannel{
+		ch:      ch,
+		returns: ch.NotifyReturn(make(chan amqp.Return, 1)),
+	}, nil
+}
+
+// pooled returns an open channel from the pool or nil if the pool is empty.
+func pooled(pool chan *publishChannel) *publishChannel {
+	for {
+		select {
+		case pc := <-pool:
+			if !pc.ch.IsClosed() {
+				return pc
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+func (c *Connection) release(pc *publishChannel) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if pc.ch.IsClosed() || c.pool == nil {
+		return
+	}
+
+	select {
+	case c.pool <- pc:
+	default:
+		pc.ch.Close()
+	}
+}
+
+func (c *Connection) discard(pc *publishChannel) {
+	if !pc.ch.IsClosed() {
+		pc.ch.Close()
+	}
+}
+
+// drainPool closes pooled channels, c.mu must be held.
+func (c *Connection) drainPool() {
+	if c.pool == nil {
+		return
+	}
+
+	for {
+		select {
+		case pc := <-c.pool:
+			c.discard(pc)
+		default:
+			c.pool = nil
+			return
+		}
+	}
+}
+
+// ConsumeOptions configures a subscription.
+type ConsumeOptions struct {
+	Queue    string
+	Consumer string
+	// Prefetch limits the number of unacknowledged deliveries on the channel.
+	Prefetch int
+	// Workers is the number of deliveries handled concurrently.
+	Workers int
+	// Key returns the ordering key of a delivery, deliveries with the same
+	// key are handled one by one in delivery order. Nil means no ordering.
+	Key func(amqp.Delivery) string
+}
+
+// Consume subscribes to the queue and passes every delivery to handler.
+// Consume resubscribes after the connection is restored and returns when
+// ctx is canceled or the connection is closed. On cancellation it stops
+// receiving, waits for in-flight deliveries to be handled and only then
+// closes the channel, handlers get a context that is not canceled.
+func (c *Connection) Consume(
+	ctx context.Context,
+	opts ConsumeOptions,
+	handler func(context.Context, amqp.Delivery),
+) error {
+	handlerCtx := context.WithoutCancel(ctx)
+
+	for {
+		conn, err := c.wait(ctx)
+		if err != nil {
+			return err
+		}
+
+		ch, err := conn.Channel()
+		if err != nil {
+			c.log.Warn("failed to open consumer channel", zap.String("queue", opts.Queue), zap.Error(err))
+			if err := c.sleep(ctx, c.reconnectDelay); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := ch.Qos(opts.Prefetch, 0, false); err != nil {
+			ch.Close()
+			return fmt.Errorf("set prefetch for queue %q: %w", opts.Queue, err)
+		}
+
+		msgs, err := ch.Consume(opts.Queue, opts.Consumer, false, false, false, false, nil)
+		if err != nil {
+			ch.Close()
+			return fmt.Errorf("consume queue %q: %w", opts.Queue, err)
+		}
+
+		c.log.Info("subscribed to queue",
+			zap.String("queue", opts.Queue),
+			zap.String("consumer", opts.Consumer),
+			zap.Int("prefetch", opts.Prefetch),
+			zap.Int("workers", opts.Workers),
+		)
+
+		pool := broker.NewWorkerPool(opts.Workers, func(msg amqp.Delivery) { handler(handlerCtx, msg) })
+		err = deliver(ctx, msgs, func(msg amqp.Delivery) {
+			key := msg.MessageId
+			if opts.Key != nil {
+				key = opts.Key(msg)
+			}
+			pool.Dispatch(key, msg)
+		})
+		if err != nil {
+			// stop new deliveries, unacked prefetched ones are requeued on close
+			if err := ch.Cancel(opts.Consumer, false); err != nil {
+				c.log.Warn("failed to cancel consumer", zap.String("queue", opts.Queue), zap.Error(err))
+			}
+		}
+
+		pool.Drain()
+		ch.Close()
+
+		if err != nil {
+			return err
+		}
+
+		c.log.Warn("subscription lost, resubscribing", zap.String("queue", opts.Queue))
+	}
+}
+
+// deliver passes messages to fn until msgs is closed or ctx is canceled.
+func deliver(ctx context.Context, msgs <-chan amqp.Delivery, fn func(amqp.Delivery)) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg, ok := <-msgs:
+			if !ok {
+				return nil
+			}
+			fn(msg)
+		}
+	}
+}
+
+func (c *Connection) sleep(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c.closed:
+		return ErrConnectionClosed
+	case <-time.After(d):
+		return nil
+	}
+}
+
+// Close closes the connection and stops reconnecting.
+func (c *Connection) Close(ctx context.Context) error {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+	})
+
+	select {
+	case <-c.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,164 @@
+package deadletter
+
+import (
+	"context"
+	"dussh/internal/broker/rabbit"
+	"dussh/internal/config"
+	domainerrors "dussh/internal/domain/errors"
+	"dussh/internal/domain/models"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"time"
+)
+
+// Store gives access to messages in the dead-letter queue.
+type Store interface {
+	List(ctx context.Context, limit int) ([]*models.DeadLetter, error)
+	Replay(ctx context.Context, id string) error
+	Discard(ctx context.Context, id string) error
+}
+
+func NewStore(conn *rabbit.Connection, rc config.RabbitMQ) Store {
+	return &store{conn: conn, rc: rc}
+}
+
+type store struct {
+	conn *rabbit.Connection
+	rc   config.RabbitMQ
+}
+
+// List returns up to limit dead-lettered messages without removing them from the queue.
+func (s *store) List(ctx context.Context, limit int) ([]*models.DeadLetter, error) {
+	ch, err := s.conn.Channel()
+	if err != nil {
+		return nil, err
+	}
+	defer ch.Close()
+
+	var (
+		letters []*models.DeadLetter
+		lastTag uint64
+	)
+	for len(letters) < limit {
+		msg, ok, err := ch.Get(s.rc.DeadLetter.Queue, false)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+
+		lastTag = msg.DeliveryTag
+		letters = append(letters, toDeadLetter(msg))
+	}
+
+	if lastTag != 0 {
+		// return the inspected messages to the queue in their original order
+		if err := ch.Nack(lastTag, true, true); err != nil {
+			return nil, err
+		}
+	}
+
+	return letters, nil
+}
+
+// Replay moves the message back to its original queue with a reset retry counter.
+func (s *store) Replay(ctx context.Context, id string) error {
+	return s.take(id, func(msg amqp.Delivery) error {
+		headers := make(amqp.Table, len(msg.Headers))
+		for k, v := range msg.Headers {
+			headers[k] = v
+		}
+		delete(headers, rabbit.HeaderRetryCount)
+
+		queue, _ := msg.Headers[rabbit.HeaderOriginalQueue].(string)
+		if queue == "" {
+			queue = s.rc.NotificationConsumer.Queue
+		}
+
+		return s.conn.Publish(
+			ctx,
+			"",
+			queue,
+			true,
+			amqp.Publishing{
+				Headers:      headers,
+				ContentType:  msg.ContentType,
+				DeliveryMode: amqp.Persistent,
+				MessageId:    msg.MessageId,
+				Body:         msg.Body,
+			},
+		)
+	})
+}
+
+// Discard removes the message from the dead-letter queue.
+func (s *store) Discard(ctx context.Context, id string) error {
+	return s.take(id, func(amqp.Delivery) error {
+		return nil
+	})
+}
+
+// take scans the dead-letter queue for the message with the given id, calls fn
+// and acks the message if fn succeeds. Other scanned messages are requeued.
+func (s *store) take(id string, fn func(amqp.Delivery) error) error {
+	ch, err := s.conn.Channel()
+	if err != nil {
+		return err
+	}
+	defer ch.Close()
+
+	var (
+		found   *amqp.Delivery
+		lastTag uint64
+	)
+	for found == nil {
+		msg, ok, err := ch.Get(s.rc.DeadLetter.Queue, false)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+
+		if msg.MessageId == id {
+			found = &msg
+			continue
+		}
+		lastTag = msg.DeliveryTag
+	}
+
+	if lastTag != 0 {
+		if err := ch.Nack(lastTag, true, true); err != nil {
+			return err
+		}
+	}
+
+	if found == nil {
+		return domainerrors.ErrDeadLetterNotFound
+	}
+
+	if err := fn(*found); err != nil {
+		return err
+	}
+
+	return found.Ack(false)
+}
+
+func toDeadLetter(msg amqp.Delivery) *models.DeadLetter {
+	l := &models.DeadLetter{
+		ID:          msg.MessageId,
+		RetryCount:  rabbit.RetryCount(msg.Headers),
+		ContentType: msg.ContentType,
+		Body:        string(msg.Body),
+	}
+
+	l.OriginalQueue, _ = msg.Headers[rabbit.HeaderOriginalQueue].(string)
+	l.LastError, _ = msg.Headers[rabbit.HeaderLastError].(string)
+	if v, ok := msg.Headers[rabbit.HeaderDeadLetteredAt].(string); ok {
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			l.DeadLetteredAt = &t
+		}
+	}
+
+	return l
+}
@@ -0,0 +1,171 @@
+package rabbit
+
+import (
+	"context"
+	"crypto/rand"
+	"dussh/internal/config"
+	"encoding/hex"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Headers set on retried and dead-lettered messages.
+const (
+	HeaderRetryCount     = "x-retry-count"
+	HeaderLastError      = "x-last-error"
+	HeaderOriginalQueue  = "x-original-queue"
+	HeaderDeadLetteredAt = "x-dead-lettered-at"
+)
+
+// Retry schedules the failed delivery for redelivery after an exponential
+// backoff, or moves it to the dead-letter exchange once retries are exhausted.
+// The original delivery must be acked by the caller when Retry succeeds.
+func Retry(ctx context.Context, conn *Connection, rc config.RabbitMQ, msg amqp.Delivery, reason error) error {
+	attempts := RetryCount(msg.Headers)
+	if attempts >= rc.NotificationConsumer.MaxRetries {
+		return DeadLetter(ctx, conn, rc, msg, reason)
+	}
+
+	headers := copyHeaders(msg.Headers)
+	headers[HeaderRetryCount] = int32(attempts + 1)
+	headers[HeaderLastError] = reason.Error()
+
+	delay := RetryDelay(rc.NotificationConsumer, attempts)
+	return conn.Publish(
+		ctx,
+		"",
+		DelayQueue(rc.NotificationConsumer, delay),
+		false,
+		amqp.Publishing{
+			Headers:      headers,
+			ContentType:  msg.ContentType,
+			DeliveryMode: amqp.Persistent,
+			MessageId:    msg.MessageId,
+			Body:         msg.Body,
+		},
+	)
+}
+
+// Delay schedules the delivery for redelivery through the delay queue of the
+// shortest tier not below the delay, the retry count is kept. The original
+// delivery must be acked by the caller when Delay succeeds.
+func Delay(ctx context.Context, conn *Connection, rc config.RabbitMQ, msg amqp.Delivery, delay time.Duration) error {
+	return conn.Publish(
+		ctx,
+		"",
+		DelayQueue(rc.NotificationConsumer, delay),
+		false,
+		amqp.Publishing{
+			Headers:      copyHeaders(msg.Headers),
+			ContentType:  msg.ContentType,
+			DeliveryMode: amqp.Persistent,
+			MessageId:    msg.MessageId,
+			Body:         msg.Body,
+		},
+	)
+}
+
+// DelayTiers returns the delays of the delay queues in ascending order: every
+// retry backoff and the in-progress delay. RabbitMQ expires messages only at
+// the head of a queue, so every queue holds messages of a single delay set by
+// its message TTL, a short delay never waits behind a longer one.
+func DelayTiers(cfg config.NotificationConsumer) []time.Duration {
+	tiers := []time.Duration{cfg.InProgressDelay}
+	for attempts := 0; attempts < cfg.MaxRetries; attempts++ {
+		tiers = append(tiers, RetryDelay(cfg, attempts))
+	}
+
+	tiers = slices.DeleteFunc(tiers, func(d time.Duration) bool { return d <= 0 })
+	slices.Sort(tiers)
+
+	return slices.Compact(tiers)
+}
+
+// DelayQueue returns the name of the delay queue of the shortest tier not
+// below the delay, or of the longest tier. The queues are named after the
+// retry queue with the tier appended, e.g. notification.retry.30s.
+func DelayQueue(cfg config.NotificationConsumer, delay time.Duration) string {
+	tiers := DelayTiers(cfg)
+	if len(tiers) == 0 {
+		return cfg.RetryQueue
+	}
+
+	tier := tiers[len(tiers)-1]
+	if i, _ := slices.BinarySearch(tiers, delay); i < len(tiers) {
+		tier = tiers[i]
+	}
+
+	return cfg.RetryQueue + "." + tier.String()
+}
+
+// DeadLetter publishes the delivery to the dead-letter exchange.
+// The original delivery must be acked by the caller when DeadLetter succeeds.
+func DeadLetter(ctx context.Context, conn *Connection, rc config.RabbitMQ, msg amqp.Delivery, reason error) error {
+	headers := copyHeaders(msg.Headers)
+	headers[HeaderLastError] = reason.Error()
+	headers[HeaderOriginalQueue] = rc.NotificationConsumer.Queue
+	headers[HeaderDeadLetteredAt] = time.Now().UTC().Format(time.RFC3339)
+
+	id := msg.MessageId
+	if id == "" {
+		id = newMessageID()
+	}
+
+	return conn.Publish(
+		ctx,
+		rc.DeadLetter.Exchange,
+		"",
+		false,
+		amqp.Publishing{
+			Headers:      headers,
+			ContentType:  msg.ContentType,
+			DeliveryMode: amqp.Persistent,
+			MessageId:    id,
+			Body:         msg.Body,
+		},
+	)
+}
+
+// RetryCount returns the number of retries already made for the message.
+func RetryCount(headers amqp.Table) int {
+	switch v := headers[HeaderRetryCount].(type) {
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case int:
+		return v
+	default:
+		return 0
+	}
+}
+
+// RetryDelay returns the backoff before the next retry, doubling the
+// configured delay with every attempt up to the configured maximum.
+func RetryDelay(cfg config.NotificationConsumer, attempts int) time.Duration {
+	delay := cfg.RetryDelay
+	for i := 0; i < attempts && delay < cfg.MaxRetryDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, cfg.MaxRetryDelay)
+}
+
+func copyHeaders(headers amqp.Table) amqp.Table {
+	c := make(amqp.Table, len(headers)+3)
+	for k, v := range headers {
+		c[k] = v
+	}
+	return c
+}
+
+func newMessageID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 10)
+	}
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,53 @@
+package rabbit
+
+import (
+	"dussh/internal/config"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDelayQueues(t *testing.T) {
+	rc := config.RabbitMQ{
+		NotificationPublisher: config.NotificationPublisher{Exchange: "notification"},
+		NotificationConsumer: config.NotificationConsumer{
+			Queue:           "notification",
+			RetryQueue:      "notification.retry",
+			MaxRetries:      5,
+			RetryDelay:      time.Second,
+			MaxRetryDelay:   4 * time.Second,
+			InProgressDelay: time.Minute,
+		},
+		DeadLetter: config.DeadLetter{Exchange: "notification.dlx", Queue: "notification.dead"},
+	}
+	cfg := rc.NotificationConsumer
+
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, time.Minute}
+	if got := DelayTiers(cfg); !slices.Equal(got, want) {
+		t.Fatalf("tiers = %v, want %v", got, want)
+	}
+
+	for delay, queue := range map[time.Duration]string{
+		RetryDelay(cfg, 0):     "notification.retry.1s",
+		RetryDelay(cfg, 4):     "notification.retry.4s",
+		cfg.InProgressDelay:    "notification.retry.1m0s",
+		3 * time.Second:        "notification.retry.4s",
+		time.Millisecond:       "notification.retry.1s",
+		10 * cfg.MaxRetryDelay: "notification.retry.1m0s",
+	} {
+		if got := DelayQueue(cfg, delay); got != queue {
+			t.Errorf("queue of %s = %q, want %q", delay, got, queue)
+		}
+	}
+
+	// every tier gets its own queue expiring messages after the tier delay
+	topology := TopologyFromConfig(rc)
+	if err := ValidateTopology(rc, topology); err != nil {
+		t.Fatalf("derived topology is invalid: %v", err)
+	}
+
+	topology.Queues[1].MessageTTL = time.Hour
+	if err := ValidateTopology(rc, topology); err == nil {
+		t.Error("validated a delay queue with a wrong message ttl")
+	}
+}
@@ -0,0 +1,188 @@
+package rabbit
+
+import (
+	"dussh/internal/config"
+	"dussh/internal/domain/models"
+	"errors"
+	"fmt"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidTopology  = errors.New("invalid rabbitmq topology")
+	ErrTopologyMismatch = errors.New("rabbitmq topology does not match the existing declaration")
+	ErrTopologyMissing  = errors.New("rabbitmq topology references a missing entity")
+)
+
+// TopologyFromConfig returns the configured topology, or the topology derived
+// from the publisher, consumer and dead-letter settings if none is configured.
+func TopologyFromConfig(rc config.RabbitMQ) config.Topology {
+	t := rc.Topology
+	if len(t.Exchanges) != 0 || len(t.Queues) != 0 || len(t.Bindings) != 0 {
+		return t
+	}
+
+	queues := []config.Queue{{Name: rc.NotificationConsumer.Queue, Durable: true}}
+	for _, delay := range DelayTiers(rc.NotificationConsumer) {
+		queues = append(queues, config.Queue{
+			Name:                 DelayQueue(rc.NotificationConsumer, delay),
+			Durable:              true,
+			MessageTTL:           delay,
+			DeadLetterRoutingKey: rc.NotificationConsumer.Queue,
+		})
+	}
+	queues = append(queues, config.Queue{Name: rc.DeadLetter.Queue, Durable: true})
+
+	return config.Topology{
+		Exchanges: []config.Exchange{
+			{Name: rc.NotificationPublisher.Exchange, Kind: amqp.ExchangeDirect, Durable: true},
+			{Name: rc.DeadLetter.Exchange, Kind: amqp.ExchangeFanout, Durable: true},
+		},
+		Queues: queues,
+		Bindings: []config.Binding{
+			{
+				Queue:      rc.NotificationConsumer.Queue,
+				Exchange:   rc.NotificationPublisher.Exchange,
+				RoutingKey: models.NotificationRoutingKey,
+			},
+			{Queue: rc.DeadLetter.Queue, Exchange: rc.DeadLetter.Exchange},
+		},
+	}
+}
+
+// ValidateTopology checks the topology is complete: every binding refers to a
+// declared exchange and queue, and every exchange and queue the application
+// uses is declared.
+func ValidateTopology(rc config.RabbitMQ, t config.Topology) error {
+	var errs []error
+
+	exchanges := make(map[string]bool, len(t.Exchanges))
+	for _, e := range t.Exchanges {
+		switch {
+		case e.Name == "":
+			errs = append(errs, errors.New("exchange without name"))
+		case exchanges[e.Name]:
+			errs = append(errs, fmt.Errorf("exchange %q is declared twice", e.Name))
+		}
+		switch e.Kind {
+		case amqp.ExchangeDirect, amqp.ExchangeFanout, amqp.ExchangeTopic, amqp.ExchangeHeaders:
+		default:
+			errs = append(errs, fmt.Errorf("exchange %q has unknown kind %q", e.Name, e.Kind))
+		}
+		exchanges[e.Name] = true
+	}
+
+	queues := make(map[string]bool, len(t.Queues))
+	ttls := make(map[string]time.Duration, len(t.Queues))
+	for _, q := range t.Queues {
+		switch {
+		case q.Name == "":
+			errs = append(errs, errors.New("queue without name"))
+		case queues[q.Name]:
+			errs = append(errs, fmt.Errorf("queue %q is declared twice", q.Name))
+		}
+		if q.DeadLetterExchange != "" && !exchanges[q.DeadLetterExchange] {
+			errs = append(errs, fmt.Errorf("queue %q dead-letters to undeclared exchange %q", q.Name, q.DeadLetterExchange))
+		}
+		queues[q.Name] = true
+		ttls[q.Name] = q.MessageTTL
+	}
+
+	for _, b := range t.Bindings {
+		if !queues[b.Queue] {
+			errs = append(errs, fmt.Errorf("binding refers to undeclared queue %q", b.Queue))
+		}
+		if !exchanges[b.Exchange] {
+			errs = append(errs, fmt.Errorf("binding of queue %q refers to undeclared exchange %q", b.Queue, b.Exchange))
+		}
+	}
+
+	required := []struct {
+		kind, name, setting string
+		declared            map[string]bool
+	}{
+		{"exchange", rc.NotificationPublisher.Exchange, "notification_publisher.exchange", exchanges},
+		{"queue", rc.NotificationConsumer.Queue, "notification_consumer.queue", queues},
+		{"exchange", rc.DeadLetter.Exchange, "dead_letter.exchange", exchanges},
+		{"queue", rc.DeadLetter.Queue, "dead_letter.queue", queues},
+	}
+	for _, r := range required {
+		if !r.declared[r.name] {
+			errs = append(errs, fmt.Errorf("%s %q from %s is not declared", r.kind, r.name, r.setting))
+		}
+	}
+
+	for _, delay := range DelayTiers(rc.NotificationConsumer) {
+		name := DelayQueue(rc.NotificationConsumer, delay)
+		switch {
+		case !queues[name]:
+			errs = append(errs, fmt.Errorf("delay queue %q of notification_consumer.retry_queue is not declared", name))
+		case ttls[name] != delay:
+			errs = append(errs, fmt.Errorf("delay queue %q has message ttl %s, want %s", name, ttls[name], delay))
+		}
+	}
+
+	if len(errs) != 0 {
+		return fmt.Errorf("%w: %w", ErrInvalidTopology, errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// DeclareTopology declares the topology. Declarations are idempotent, an
+// entity that already exists with different settings results in ErrTopologyMismatch.
+func DeclareTopology(ch *amqp.Channel, t config.Topology) error {
+	for _, e := range t.Exchanges {
+		if err := ch.ExchangeDeclare(e.Name, e.Kind, e.Durable, e.AutoDelete, false, false, nil); err != nil {
+			return declareError("exchange", e.Name, err)
+		}
+	}
+
+	for _, q := range t.Queues {
+		if _, err := ch.QueueDeclare(q.Name, q.Durable, q.AutoDelete, false, false, queueArgs(q)); err != nil {
+			return declareError("queue", q.Name, err)
+		}
+	}
+
+	for _, b := range t.Bindings {
+		if err := ch.QueueBind(b.Queue, b.RoutingKey, b.Exchange, false, nil); err != nil {
+			return declareError("binding", b.Exchange+" -> "+b.Queue, err)
+		}
+	}
+
+	return nil
+}
+
+func queueArgs(q config.Queue) amqp.Table {
+	args := amqp.Table{}
+	if q.MessageTTL > 0 {
+		args[amqp.QueueMessageTTLArg] = q.MessageTTL.Milliseconds()
+	}
+	if q.MaxLength > 0 {
+		args[amqp.QueueMaxLenArg] = int64(q.MaxLength)
+	}
+	if q.DeadLetterExchange != "" || q.DeadLetterRoutingKey != "" {
+		args["x-dead-letter-exchange"] = q.DeadLetterExchange
+	}
+	if q.DeadLetterRoutingKey != "" {
+		args["x-dead-letter-routing-key"] = q.DeadLetterRoutingKey
+	}
+
+	return args
+}
+
+func declareError(kind, name string, err error) error {
+	var amqpErr *amqp.Error
+	if errors.As(err, &amqpErr) {
+		switch amqpErr.Code {
+		case amqp.PreconditionFailed:
+			return fmt.Errorf("%w: %s %q: %s", ErrTopologyMismatch, kind, name, strings.ToLower(amqpErr.Reason))
+		case amqp.NotFound:
+			return fmt.Errorf("%w: %s %q: %s", ErrTopologyMissing, kind, name, strings.ToLower(amqpErr.Reason))
+		}
+	}
+
+	return fmt.Errorf("declare %s %q: %w", kind, name, err)
+}
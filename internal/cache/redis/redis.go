@@ -1,6 +1,8 @@
 package redis
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"github.com/redis/go-redis/v9"
@@ -10,9 +12,29 @@ import (
 
 // TODO add fingerprint (?)
 
+var ErrNotFound = errors.New("key not found")
+
 type Cache interface {
 	Get(ctx context.Context, key string) (string, error)
 	Set(ctx context.Context, key string, value any, ttl time.Duration) error
+	SetNX(ctx context.Context, key string, value any, ttl time.Duration) (bool, error)
+	Delete(ctx context.Context, key string) error
+	// GetDel returns the value and deletes the key atomically, so the value
+	// is returned to one caller only.
+	GetDel(ctx context.Context, key string) (string, error)
+	// Incr increments the counter and returns its value, a new counter
+	// expires after the ttl.
+	Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+	// TryLock acquires the lock for the ttl unless another holder has it, the
+	// returned token releases the lock.
+	TryLock(ctx context.Context, key string, ttl time.Duration) (string, bool, error)
+	// Unlock releases the lock if it is still held with the token.
+	Unlock(ctx context.Context, key, token string) error
+	// Publish sends the message to the subscribers of the channel on every instance.
+	Publish(ctx context.Context, channel string, message any) error
+	// Subscribe returns the messages published to the channel, the returned
+	// channel is closed once ctx is canceled or the connection is closed.
+	Subscribe(ctx context.Context, channel string) <-chan string
 	SetRefreshToken(ctx context.Context, userID string, token string, ttl time.Duration) error
 	DeleteRefreshToken(ctx context.Context, userID string, token string) error
 	UpdateRefreshToken(ctx context.Context, userID string, token string, ttl time.Duration) error
@@ -40,6 +62,9 @@ func MustNew(ctx context.Context, addr, password string) Cache {
 func (rc *redisCache) Get(ctx context.Context, key string) (string, error) {
 	value, err := rc.client.Get(ctx, key).Result()
 	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", ErrNotFound
+		}
 		return "", err
 	}
 	return value, nil
@@ -59,6 +84,106 @@ func (rc *redisCache) Set(
 	return nil
 }
 
+// SetNX sets the key only if it does not exist yet and reports whether it was set.
+func (rc *redisCache) SetNX(
+	ctx context.Context,
+	key string,
+	value any,
+	ttl time.Duration,
+) (bool, error) {
+	return rc.client.SetNX(ctx, key, value, ttl).Result()
+}
+
+func (rc *redisCache) Delete(ctx context.Context, key string) error {
+	return rc.client.Del(ctx, key).Err()
+}
+
+func (rc *redisCache) GetDel(ctx context.Context, key string) (string, error) {
+	value, err := rc.client.GetDel(ctx, key).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+	return value, nil
+}
+
+// incrScript sets the expiry together with the first increment, a counter is
+// never left without one if the client fails in between.
+var incrScript = redis.NewScript(`
+local n = redis.call("INCR", KEYS[1])
+if n == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return n
+`)
+
+func (rc *redisCache) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	return incrScript.Run(ctx, rc.client, []string{key}, ttl.Milliseconds()).Int64()
+}
+
+func (rc *redisCache) TryLock(ctx context.Context, key string, ttl time.Duration) (string, bool, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", false, err
+	}
+	token := hex.EncodeToString(b)
+
+	ok, err := rc.client.SetNX(ctx, key, token, ttl).Result()
+	if err != nil || !ok {
+		return "", false, err
+	}
+
+	return token, true, nil
+}
+
+// unlockScript deletes the lock only if it was not taken over by another
+// holder after it expired.
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+func (rc *redisCache) Unlock(ctx context.Context, key, token string) error {
+	return unlockScript.Run(ctx, rc.client, []string{key}, token).Err()
+}
+
+func (rc *redisCache) Publish(ctx context.Context, channel string, message any) error {
+	return rc.client.Publish(ctx, channel, message).Err()
+}
+
+func (rc *redisCache) Subscribe(ctx context.Context, channel string) <-chan string {
+	pubsub := rc.client.Subscribe(ctx, channel)
+	messages := make(chan string)
+
+	go func() {
+		defer close(messages)
+		defer pubsub.Close()
+
+		received := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-received:
+				if !ok {
+					return
+				}
+				select {
+				case messages <- msg.Payload:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return messages
+}
+
 func (rc *redisCache) SetRefreshToken(
 	ctx context.Context,
 	userID string,
@@ -2,8 +2,10 @@ package config
 
 import (
 	"flag"
+	"fmt"
 	"github.com/joho/godotenv"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
@@ -16,8 +18,14 @@ type Config struct {
 	Logger     `yaml:"logger" env-required:"true"`
 	DB         `yaml:"database" env-required:"true"`
 	Redis      `yaml:"redis" env-required:"true"`
-	RabbitMQ   `yaml:"rabbit_mq" env-required:"true"`
+	Broker     string `yaml:"broker" env:"BROKER" env-default:"rabbitmq"`
+	RabbitMQ   `yaml:"rabbit_mq"`
 	Notify     `yaml:"notify" env-required:"true"`
+	Outbox     `yaml:"outbox"`
+	JobQueue   `yaml:"job_queue"`
+	Webhook    `yaml:"webhook"`
+	Reminder   `yaml:"reminder"`
+	Digest     `yaml:"digest"`
 }
 
 type HTTPServer struct {
@@ -25,6 +33,7 @@ type HTTPServer struct {
 	Timeout         time.Duration `yaml:"timeout" env-default:"4s"`
 	IdleTimeout     time.Duration `yaml:"idle_timeout" env-default:"60s"`
 	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env-default:"10s"`
+	IdempotencyTTL  time.Duration `yaml:"idempotency_ttl" env-default:"24h"`
 }
 
 type DB struct {
@@ -40,35 +49,193 @@ type Redis struct {
 	Password string `yaml:"password" env:"REDIS_PASSWORD" env-required:"true"`
 }
 
+// RabbitMQ configures the broker. User, Host and Password are required only
+// when it is the selected broker, the other backends reuse the topology.
 type RabbitMQ struct {
-	Port                  int    `yaml:"port" env:"RABBITMQ_PORT" env-default:"5672"`
-	User                  string `yaml:"user" env:"RABBITMQ_USER" env-required:"true"`
-	Host                  string `yaml:"host" env:"RABBITMQ_HOST" env-required:"true"`
-	Password              string `yaml:"password" env:"RABBITMQ_PASSWORD" env-required:"true"`
+	Port                  int           `yaml:"port" env:"RABBITMQ_PORT" env-default:"5672"`
+	User                  string        `yaml:"user" env:"RABBITMQ_USER"`
+	Host                  string        `yaml:"host" env:"RABBITMQ_HOST"`
+	Password              string        `yaml:"password" env:"RABBITMQ_PASSWORD"`
+	ChannelPoolSize       int           `yaml:"channel_pool_size" env-default:"8"`
+	ReconnectDelay        time.Duration `yaml:"reconnect_delay" env-default:"1s"`
+	MaxReconnectDelay     time.Duration `yaml:"max_reconnect_delay" env-default:"30s"`
+	StartupTimeout        time.Duration `yaml:"startup_timeout" env-default:"30s"`
 	NotificationPublisher `yaml:"notification_publisher"`
 	NotificationConsumer  `yaml:"notification_consumer"`
+	DeadLetter            `yaml:"dead_letter"`
+	Topology              `yaml:"topology"`
+}
+
+// Topology describes exchanges, queues and bindings declared on startup.
+// When it is empty, the topology is derived from the publisher, consumer
+// and dead-letter settings.
+type Topology struct {
+	Exchanges []Exchange `yaml:"exchanges"`
+	Queues    []Queue    `yaml:"queues"`
+	Bindings  []Binding  `yaml:"bindings"`
+}
+
+type Exchange struct {
+	Name       string `yaml:"name"`
+	Kind       string `yaml:"kind"`
+	Durable    bool   `yaml:"durable"`
+	AutoDelete bool   `yaml:"auto_delete"`
+}
+
+// Queue describes a queue. Dead-lettering is enabled when either dead-letter
+// field is set, an empty exchange then means the default exchange.
+type Queue struct {
+	Name                 string        `yaml:"name"`
+	Durable              bool          `yaml:"durable"`
+	AutoDelete           bool          `yaml:"auto_delete"`
+	MessageTTL           time.Duration `yaml:"message_ttl"`
+	MaxLength            int           `yaml:"max_length"`
+	DeadLetterExchange   string        `yaml:"dead_letter_exchange"`
+	DeadLetterRoutingKey string        `yaml:"dead_letter_routing_key"`
+}
+
+type Binding struct {
+	Queue      string `yaml:"queue"`
+	Exchange   string `yaml:"exchange"`
+	RoutingKey string `yaml:"routing_key"`
 }
 
 type NotificationPublisher struct {
 	Exchange string `yaml:"exchange"`
 }
 
+// NotificationConsumer configures the notification queue. Failed messages
+// wait in delay queues named after RetryQueue, one per backoff tier.
 type NotificationConsumer struct {
-	Name  string `yaml:"name"`
-	Queue string `yaml:"queue"`
+	Name          string        `yaml:"name"`
+	Queue         string        `yaml:"queue"`
+	RetryQueue    string        `yaml:"retry_queue" env-default:"notification.retry"`
+	MaxRetries    int           `yaml:"max_retries" env-default:"5"`
+	RetryDelay    time.Duration `yaml:"retry_delay" env-default:"1s"`
+	MaxRetryDelay time.Duration `yaml:"max_retry_delay" env-default:"10m"`
+	DedupTTL      time.Duration `yaml:"dedup_ttl" env-default:"72h"`
+	DedupLockTTL  time.Duration `yaml:"dedup_lock_ttl" env-default:"5m"`
+	Prefetch      int           `yaml:"prefetch" env-default:"20"`
+	Workers       int           `yaml:"workers" env-default:"4"`
+	// InProgressDelay delays a redelivery of a message another delivery is
+	// still processing, until that delivery is done or its lock expires.
+	InProgressDelay time.Duration `yaml:"in_progress_delay" env-default:"1m"`
+}
+
+type DeadLetter struct {
+	Exchange string `yaml:"exchange" env-default:"notification.dlx"`
+	Queue    string `yaml:"queue" env-default:"notification.dead"`
+}
+
+type Outbox struct {
+	PollInterval     time.Duration `yaml:"poll_interval" env-default:"1s"`
+	BatchSize        int64         `yaml:"batch_size" env-default:"100"`
+	Lease            time.Duration `yaml:"lease" env-default:"30s"`
+	RetryInterval    time.Duration `yaml:"retry_interval" env-default:"1s"`
+	MaxRetryInterval time.Duration `yaml:"max_retry_interval" env-default:"5m"`
+}
+
+// JobQueue configures the Postgres broker backend. Queues, bindings, retries
+// and dead-lettering follow the RabbitMQ settings.
+type JobQueue struct {
+	PollInterval      time.Duration `yaml:"poll_interval" env-default:"5s"`
+	VisibilityTimeout time.Duration `yaml:"visibility_timeout" env-default:"5m"`
+	ListenRetryDelay  time.Duration `yaml:"listen_retry_delay" env-default:"1s"`
+	// MaxAttempts bounds the deliveries of a job, retries and delayed
+	// redeliveries included. A job claimed that many times, usually one that
+	// crashes the consumer before it is settled, is dead-lettered instead of
+	// being delivered again.
+	MaxAttempts int `yaml:"max_attempts" env-default:"10"`
+}
+
+// Webhook configures the delivery of events to webhook subscriptions. A
+// delivery is failed after MaxAttempts and can be redelivered manually.
+// Workers send a batch concurrently, a batch is cut down so that every
+// delivery is sent within the Lease even if each request hits the Timeout.
+type Webhook struct {
+	PollInterval     time.Duration `yaml:"poll_interval" env-default:"5s"`
+	BatchSize        int64         `yaml:"batch_size" env-default:"50"`
+	Workers          int           `yaml:"workers" env-default:"10"`
+	Lease            time.Duration `yaml:"lease" env-default:"1m"`
+	Timeout          time.Duration `yaml:"timeout" env-default:"10s"`
+	MaxAttempts      int           `yaml:"max_attempts" env-default:"8"`
+	RetryInterval    time.Duration `yaml:"retry_interval" env-default:"30s"`
+	MaxRetryInterval time.Duration `yaml:"max_retry_interval" env-default:"1h"`
+}
+
+// Reminder configures the reminders sent Offsets before every session and
+// CertificateNotice before a medical certificate expires. Every instance runs
+// the scheduler, a redis lock lets one of them scan at a time.
+type Reminder struct {
+	Interval time.Duration   `yaml:"interval" env-default:"1m"`
+	Offsets  []time.Duration `yaml:"offsets" env-default:"24h,2h"`
+	// MaxDelay bounds how late a reminder is sent, e.g. after a downtime.
+	MaxDelay          time.Duration `yaml:"max_delay" env-default:"30m"`
+	LockTTL           time.Duration `yaml:"lock_ttl" env-default:"1m"`
+	CertificateNotice time.Duration `yaml:"certificate_notice" env-default:"336h"`
+}
+
+// Digest configures when the notifications collected for digests are sent:
+// daily digests at Hour and weekly ones on Weekday at Hour, in local time.
+// Every instance runs the scheduler, a redis lock lets one of them queue the
+// digests at a time.
+type Digest struct {
+	Interval time.Duration `yaml:"interval" env-default:"5m"`
+	Hour     int           `yaml:"hour" env-default:"8"`
+	Weekday  string        `yaml:"weekday" env-default:"monday"`
+	LockTTL  time.Duration `yaml:"lock_ttl" env-default:"1m"`
 }
 
 type Notify struct {
-	EmailProvider `yaml:"email_provider"`
+	EmailProvider    `yaml:"email_provider"`
+	TelegramProvider `yaml:"telegram_provider"`
+	SMSProvider      `yaml:"sms_provider"`
 }
 
+// EmailProvider configures the SMTP server. Security is starttls, tls or
+// none, empty means tls for port 465 and starttls otherwise. Driver file or
+// memory captures the emails instead of sending them, file also writes them
+// to SinkDir as .eml files; both are refused in prod. SinkUI serves the
+// captured emails without authentication and is allowed in dev only.
 type EmailProvider struct {
-	Port      int    `yaml:"port" env:"NOTIFY_EMAIL_PROVIDER_PORT" env-default:"2525"`
-	Host      string `yaml:"host" env:"NOTIFY_EMAIL_PROVIDER_HOST" env-required:"true"`
-	From      string `yaml:"from" env-default:"dussh@school.com"`
-	Username  string `yaml:"username" env:"NOTIFY_EMAIL_PROVIDER_USERNAME" env-required:"true"`
-	Password  string `yaml:"password" env:"NOTIFY_EMAIL_PROVIDER_PASSWORD" env-required:"true"`
-	TLSEnable bool   `yaml:"tls_enable" env:"NOTIFY_EMAIL_PROVIDER_TLS" env-default:"false"`
+	Driver             string        `yaml:"driver" env:"NOTIFY_EMAIL_PROVIDER_DRIVER" env-default:"smtp"`
+	Port               int           `yaml:"port" env:"NOTIFY_EMAIL_PROVIDER_PORT" env-default:"2525"`
+	Host               string        `yaml:"host" env:"NOTIFY_EMAIL_PROVIDER_HOST"`
+	From               string        `yaml:"from" env-default:"dussh@school.com"`
+	Username           string        `yaml:"username" env:"NOTIFY_EMAIL_PROVIDER_USERNAME"`
+	Password           string        `yaml:"password" env:"NOTIFY_EMAIL_PROVIDER_PASSWORD"`
+	Security           string        `yaml:"security" env:"NOTIFY_EMAIL_PROVIDER_SECURITY"`
+	InsecureSkipVerify bool          `yaml:"insecure_skip_verify" env:"NOTIFY_EMAIL_PROVIDER_INSECURE_SKIP_VERIFY" env-default:"false"`
+	PoolSize           int           `yaml:"pool_size" env-default:"2"`
+	IdleTimeout        time.Duration `yaml:"idle_timeout" env-default:"1m"`
+	SinkDir            string        `yaml:"sink_dir" env:"NOTIFY_EMAIL_PROVIDER_SINK_DIR" env-default:"tmp/mail"`
+	SinkLimit          int           `yaml:"sink_limit" env-default:"100"`
+	SinkUI             bool          `yaml:"sink_ui" env:"NOTIFY_EMAIL_PROVIDER_SINK_UI" env-default:"false"`
+}
+
+// TelegramProvider configures the telegram bot, it is disabled without a token.
+type TelegramProvider struct {
+	Token       string        `yaml:"token" env:"NOTIFY_TELEGRAM_PROVIDER_TOKEN"`
+	BaseURL     string        `yaml:"base_url" env:"NOTIFY_TELEGRAM_PROVIDER_BASE_URL" env-default:"https://api.telegram.org"`
+	BotUsername string        `yaml:"bot_username" env:"NOTIFY_TELEGRAM_PROVIDER_BOT_USERNAME"`
+	PollTimeout time.Duration `yaml:"poll_timeout" env-default:"30s"`
+	LinkCodeTTL time.Duration `yaml:"link_code_ttl" env-default:"10m"`
+	// LinkAttempts limits the wrong link codes a chat can send per LinkCodeTTL.
+	LinkAttempts int `yaml:"link_attempts" env-default:"5"`
+}
+
+// SMSProvider configures the sms gateway. Gateway is http or fake and must
+// be set outside dev, where it falls back to fake.
+type SMSProvider struct {
+	Gateway           string        `yaml:"gateway" env:"NOTIFY_SMS_PROVIDER_GATEWAY"`
+	URL               string        `yaml:"url" env:"NOTIFY_SMS_PROVIDER_URL"`
+	APIKey            string        `yaml:"api_key" env:"NOTIFY_SMS_PROVIDER_API_KEY"`
+	Timeout           time.Duration `yaml:"timeout" env-default:"10s"`
+	From              string        `yaml:"from" env-default:"DUSSH"`
+	MaxParts          int           `yaml:"max_parts" env-default:"3"`
+	OTPTTL            time.Duration `yaml:"otp_ttl" env-default:"5m"`
+	OTPResendInterval time.Duration `yaml:"otp_resend_interval" env-default:"1m"`
+	OTPMaxAttempts    int           `yaml:"otp_max_attempts" env-default:"5"`
 }
 
 type Auth struct {
@@ -108,9 +275,37 @@ func MustLoadPath(configPath string) *Config {
 		panic("cannot read config: " + err.Error())
 	}
 
+	if err := cfg.validate(); err != nil {
+		panic("invalid config: " + err.Error())
+	}
+
 	return &cfg
 }
 
+// validate checks the settings that are required depending on other settings.
+func (c *Config) validate() error {
+	// "rabbitmq" is broker.BackendRabbitMQ, internal/broker imports this package
+	if c.Broker != "rabbitmq" {
+		return nil
+	}
+
+	var missing []string
+	for _, field := range []struct{ name, value string }{
+		{"user", c.RabbitMQ.User},
+		{"host", c.RabbitMQ.Host},
+		{"password", c.RabbitMQ.Password},
+	} {
+		if field.value == "" {
+			missing = append(missing, field.name)
+		}
+	}
+	if len(missing) != 0 {
+		return fmt.Errorf("rabbit_mq %s required by broker %q", strings.Join(missing, ", "), c.Broker)
+	}
+
+	return nil
+}
+
 func fetchConfigPath() string {
 	var res string
 
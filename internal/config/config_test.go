@@ -0,0 +1,22 @@
+package config
+
+import "testing"
+
+func TestValidateRequiresRabbitMQOnlyWhenSelected(t *testing.T) {
+	cfg := Config{Broker: "memory"}
+	if err := cfg.validate(); err != nil {
+		t.Errorf("memory broker without rabbitmq settings: %v", err)
+	}
+
+	cfg.Broker = "rabbitmq"
+	cfg.RabbitMQ.Host = "localhost"
+	err := cfg.validate()
+	if err == nil || err.Error() != `rabbit_mq user, password required by broker "rabbitmq"` {
+		t.Errorf("rabbitmq broker without credentials: %v", err)
+	}
+
+	cfg.RabbitMQ.User, cfg.RabbitMQ.Password = "guest", "guest"
+	if err := cfg.validate(); err != nil {
+		t.Errorf("rabbitmq broker with its settings: %v", err)
+	}
+}
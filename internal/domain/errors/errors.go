@@ -3,5 +3,24 @@ package errors
 import "errors"
 
 var (
-	ErrInvalidURLPattern = errors.New("invalid url pattern")
+	ErrInvalidURLPattern           = errors.New("invalid url pattern")
+	ErrInvalidStatusTransition     = errors.New("invalid course status transition")
+	ErrDeadLetterNotFound          = errors.New("dead-lettered message not found")
+	ErrJobLeaseLost                = errors.New("job visibility timeout expired, it was redelivered")
+	ErrUnauthenticated             = errors.New("authentication required")
+	ErrTelegramDisabled            = errors.New("telegram bot is not configured")
+	ErrOTPInvalid                  = errors.New("one-time code is invalid or expired")
+	ErrOTPResendTooSoon            = errors.New("one-time code was sent recently, try again later")
+	ErrPhoneAlreadyVerified        = errors.New("phone is already verified")
+	ErrUnknownWebhookEvent         = errors.New("unknown webhook event type")
+	ErrUnknownNotificationCategory = errors.New("unknown notification category")
+	ErrUnknownNotificationChannel  = errors.New("unknown notification channel")
+	ErrUnknownLocale               = errors.New("unknown locale")
+	ErrUnknownDeliveryMode         = errors.New("unknown notification delivery mode")
+	ErrUnknownTemplateEvent        = errors.New("no template can be rendered for the event type")
+	ErrUnknownTemplateLayout       = errors.New("unknown template layout")
+	ErrInvalidTemplate             = errors.New("invalid template")
+	ErrTemplateNotFound            = errors.New("template not found")
+	ErrNotificationNotResendable   = errors.New("only failed notifications can be resent")
+	ErrInboxClosed                 = errors.New("inbox streams are closed, the server is shutting down")
 )
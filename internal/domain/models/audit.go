@@ -0,0 +1,48 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AuditAction string
+
+const (
+	AuditCreate AuditAction = "create"
+	AuditUpdate AuditAction = "update"
+	AuditDelete AuditAction = "delete"
+)
+
+// Audited entity types.
+const (
+	EntityUser               = "user"
+	EntityCourse             = "course"
+	EntityEnrollment         = "enrollment"
+	EntitySession            = "session"
+	EntityInvoice            = "invoice"
+	EntityMedicalCertificate = "medical_certificate"
+)
+
+type AuditEntry struct {
+	ID         int64           `json:"id" db:"audit_log.id"`
+	ActorID    *int64          `json:"actor_id,omitempty" db:"audit_log.actor_id"`
+	ActorEmail string          `json:"actor_email,omitempty" db:"audit_log.actor_email"`
+	Action     AuditAction     `json:"action" db:"audit_log.action"`
+	EntityType string          `json:"entity_type" db:"audit_log.entity_type"`
+	EntityID   int64           `json:"entity_id" db:"audit_log.entity_id"`
+	Before     json.RawMessage `json:"before,omitempty" db:"audit_log.before"`
+	After      json.RawMessage `json:"after,omitempty" db:"audit_log.after"`
+	Diff       json.RawMessage `json:"diff,omitempty" db:"audit_log.diff"`
+	RequestID  string          `json:"request_id,omitempty" db:"audit_log.request_id"`
+	CreatedAt  time.Time       `json:"created_at" db:"audit_log.created_at"`
+}
+
+type AuditFilter struct {
+	ActorID    *int64
+	EntityType string
+	EntityID   *int64
+	From       *time.Time
+	To         *time.Time
+	Limit      int64
+	Offset     int64
+}
@@ -2,15 +2,25 @@ package models
 
 import (
 	"encoding/json"
+	"fmt"
 	"time"
 )
 
 type Course struct {
-	ID                      int64    `json:"id" db:"courses.course_id"`
-	Name                    string   `json:"name" db:"courses.course_name" validate:"required"`
-	MonthlySubscriptionCost *float64 `json:"monthly_subscription_cost" db:"courses.monthly_subscription_cost" validate:"required,min=0"`
-	Events                  []*Event `json:"events" db:"events" validate:"required,dive"`
-	Employees               []int64  `json:"employees" validate:"required"`
+	ID                      int64        `json:"id" db:"courses.course_id"`
+	Name                    string       `json:"name" db:"courses.course_name" validate:"required"`
+	MonthlySubscriptionCost *float64     `json:"monthly_subscription_cost" db:"courses.monthly_subscription_cost" validate:"required,min=0"`
+	Status                  CourseStatus `json:"status" db:"courses.status"`
+	ArchivedAt              *time.Time   `json:"archived_at,omitempty" db:"courses.archived_at"`
+	Version                 int64        `json:"version" db:"courses.version"`
+	Events                  []*Event     `json:"events" db:"events" validate:"required,dive"`
+	Employees               []int64      `json:"employees" validate:"required"`
+}
+
+type Enrollment struct {
+	ID       int64 `json:"id" db:"enrollments.id"`
+	CourseID int64 `json:"course_id" db:"enrollments.course_id"`
+	UserID   int64 `json:"user_id" db:"enrollments.personal_info_id"`
 }
 
 type MyTime time.Time
@@ -23,6 +33,44 @@ type Event struct {
 	PeriodFreq     *int64      `json:"period_freq" db:"events.period_freq" validate:"required,min=1,max=365"`
 	PeriodType     *PeriodType `json:"period_type" db:"events.period_type" validate:"required"`
 	CourseID       int64       `json:"course_id" db:"events.course_id"`
+	Version        int64       `json:"version,omitempty" db:"events.version"`
+}
+
+// Occurrences returns the start times of the event within [from, to).
+func (e *Event) Occurrences(from, to time.Time) []time.Time {
+	if e.StartDate == nil || e.RecurrentCount == nil || e.PeriodFreq == nil || e.PeriodType == nil {
+		return nil
+	}
+
+	var (
+		occurrences []time.Time
+		start       = time.Time(*e.StartDate)
+		freq        = int(*e.PeriodFreq)
+	)
+	for i := 0; i < int(*e.RecurrentCount); i++ {
+		var t time.Time
+		switch *e.PeriodType {
+		case Day:
+			t = start.AddDate(0, 0, i*freq)
+		case Week:
+			t = start.AddDate(0, 0, 7*i*freq)
+		case Month:
+			t = start.AddDate(0, i*freq, 0)
+		case Year:
+			t = start.AddDate(i*freq, 0, 0)
+		default:
+			return occurrences
+		}
+
+		if !t.Before(to) {
+			break
+		}
+		if !t.Before(from) {
+			occurrences = append(occurrences, t)
+		}
+	}
+
+	return occurrences
 }
 
 func (mt *MyTime) UnmarshalJSON(b []byte) error {
@@ -53,3 +101,46 @@ const (
 	Month
 	Year
 )
+
+//go:generate ../../../tools/enumer -type=CourseStatus -json -transform=snake
+type CourseStatus int
+
+const (
+	Draft CourseStatus = iota + 1
+	Published
+	EnrollmentClosed
+	Archived
+)
+
+// courseStatusTransitions describes which statuses a course may be moved to
+// from its current one. Archived is terminal.
+var courseStatusTransitions = map[CourseStatus][]CourseStatus{
+	Draft:            {Published, Archived},
+	Published:        {EnrollmentClosed, Archived},
+	EnrollmentClosed: {Published, Archived},
+}
+
+// CanTransitionTo reports whether a course in status s may be moved to next.
+func (s CourseStatus) CanTransitionTo(next CourseStatus) bool {
+	for _, st := range courseStatusTransitions[s] {
+		if st == next {
+			return true
+		}
+	}
+	return false
+}
+
+// Scan implements the sql.Scanner interface for CourseStatus stored as text.
+func (s *CourseStatus) Scan(src any) error {
+	str, ok := src.(string)
+	if !ok {
+		return fmt.Errorf("CourseStatus should be a string, got %T", src)
+	}
+
+	status, err := CourseStatusString(str)
+	if err != nil {
+		return err
+	}
+	*s = status
+	return nil
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// DeadLetter is a message that exhausted its retries or could not be decoded.
+type DeadLetter struct {
+	ID             string     `json:"id"`
+	OriginalQueue  string     `json:"original_queue"`
+	RetryCount     int        `json:"retry_count"`
+	LastError      string     `json:"last_error,omitempty"`
+	DeadLetteredAt *time.Time `json:"dead_lettered_at,omitempty"`
+	ContentType    string     `json:"content_type,omitempty"`
+	Body           string     `json:"body"`
+}
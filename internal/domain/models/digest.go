@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// DigestItem is a notification collected for the digest of the user instead of
+// being sent, it is queued once the digest of its mode is due.
+type DigestItem struct {
+	ID        int64                `json:"id" db:"notification_digest_items.id"`
+	UserID    int64                `json:"user_id" db:"notification_digest_items.personal_info_id"`
+	Mode      DeliveryMode         `json:"mode" db:"notification_digest_items.mode"`
+	Category  NotificationCategory `json:"category" db:"notification_digest_items.category"`
+	EventType string               `json:"event_type" db:"notification_digest_items.event_type"`
+	Subject   string               `json:"subject" db:"notification_digest_items.subject"`
+	Body      string               `json:"body" db:"notification_digest_items.body"`
+	CreatedAt time.Time            `json:"created_at" db:"notification_digest_items.created_at"`
+	QueuedAt  *time.Time           `json:"queued_at,omitempty" db:"notification_digest_items.queued_at"`
+}
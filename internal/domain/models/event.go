@@ -1,6 +1,238 @@
 package models
 
+import (
+	"strconv"
+	"time"
+)
+
+const (
+	EventTypeEnrollmentCreated   = "enrollment.created"
+	EventTypeEnrollmentCancelled = "enrollment.cancelled"
+	// EventTypeScheduleChanged is raised when sessions of a course are changed
+	// or removed.
+	EventTypeScheduleChanged = "schedule.changed"
+	EventTypeUserUpdated     = "user.updated"
+	EventTypeCourseUpdated   = "course.updated"
+	// EventTypeNotificationDeferred is a notification held back by quiet hours.
+	EventTypeNotificationDeferred = "notification.deferred"
+	// EventTypeSessionReminder reminds an enrolled user of an upcoming session.
+	EventTypeSessionReminder = "session.reminder"
+	// EventTypeNotificationDigest sends the collected notifications of a user
+	// in one email.
+	EventTypeNotificationDigest = "notification.digest"
+	// EventTypeMedicalCertificateExpiring announces that the medical
+	// certificate of a user expires soon.
+	EventTypeMedicalCertificateExpiring = "medical_certificate.expiring"
+
+	// NotificationRoutingKey routes events to the notification consumer.
+	NotificationRoutingKey = "notification"
+)
+
 type EnrollmentEvent struct {
-	CourseID int64
-	UserID   int64
+	CourseID int64 `json:"course_id"`
+	UserID   int64 `json:"user_id"`
+}
+
+func (EnrollmentEvent) EventType() string {
+	return EventTypeEnrollmentCreated
+}
+
+// SchemaVersion 2 added snake case json field names.
+func (EnrollmentEvent) SchemaVersion() int {
+	return 2
+}
+
+func (EnrollmentEvent) RoutingKey() string {
+	return NotificationRoutingKey
+}
+
+// OrderingKey keeps notifications of the same user in order.
+func (e EnrollmentEvent) OrderingKey() string {
+	return "user:" + strconv.FormatInt(e.UserID, 10)
+}
+
+// EnrollmentCancelledEvent is raised when an enrollment is deleted.
+type EnrollmentCancelledEvent struct {
+	CourseID int64 `json:"course_id"`
+	UserID   int64 `json:"user_id"`
+}
+
+func (EnrollmentCancelledEvent) EventType() string {
+	return EventTypeEnrollmentCancelled
+}
+
+func (EnrollmentCancelledEvent) SchemaVersion() int {
+	return 1
+}
+
+func (EnrollmentCancelledEvent) RoutingKey() string {
+	return NotificationRoutingKey
+}
+
+func (e EnrollmentCancelledEvent) OrderingKey() string {
+	return "user:" + strconv.FormatInt(e.UserID, 10)
+}
+
+// ScheduleChangedEvent notifies the enrolled users and the trainers of the
+// course that its sessions changed.
+type ScheduleChangedEvent struct {
+	CourseID int64 `json:"course_id"`
+}
+
+func (ScheduleChangedEvent) EventType() string {
+	return EventTypeScheduleChanged
+}
+
+func (ScheduleChangedEvent) SchemaVersion() int {
+	return 1
+}
+
+func (ScheduleChangedEvent) RoutingKey() string {
+	return NotificationRoutingKey
+}
+
+func (e ScheduleChangedEvent) OrderingKey() string {
+	return "course:" + strconv.FormatInt(e.CourseID, 10)
+}
+
+// UserUpdatedEvent is delivered to webhooks only, it has no broker consumers.
+type UserUpdatedEvent struct {
+	UserID  int64 `json:"user_id"`
+	Version int64 `json:"version"`
+}
+
+func (UserUpdatedEvent) EventType() string {
+	return EventTypeUserUpdated
+}
+
+func (UserUpdatedEvent) SchemaVersion() int {
+	return 1
+}
+
+func (UserUpdatedEvent) RoutingKey() string {
+	return ""
+}
+
+// CourseUpdatedEvent is raised when the course, its schedule or status
+// changes. It is delivered to webhooks only, it has no broker consumers.
+type CourseUpdatedEvent struct {
+	CourseID int64  `json:"course_id"`
+	Status   string `json:"status,omitempty"`
+	Version  int64  `json:"version"`
+}
+
+func (CourseUpdatedEvent) EventType() string {
+	return EventTypeCourseUpdated
+}
+
+func (CourseUpdatedEvent) SchemaVersion() int {
+	return 1
+}
+
+func (CourseUpdatedEvent) RoutingKey() string {
+	return ""
+}
+
+// DeferredNotificationEvent carries a notification to be sent once the quiet
+// hours of the user are over.
+type DeferredNotificationEvent struct {
+	UserID      int64                    `json:"user_id"`
+	Type        string                   `json:"type"`
+	ContentType string                   `json:"content_type"`
+	To          []string                 `json:"to"`
+	Subject     string                   `json:"subject"`
+	Body        string                   `json:"body"`
+	AltBody     string                   `json:"alt_body,omitempty"`
+	Attachments []NotificationAttachment `json:"attachments,omitempty"`
+	// DeliveryID is the entry of the notification in the delivery log.
+	DeliveryID int64 `json:"delivery_id,omitempty"`
+}
+
+func (DeferredNotificationEvent) EventType() string {
+	return EventTypeNotificationDeferred
+}
+
+func (DeferredNotificationEvent) SchemaVersion() int {
+	return 1
+}
+
+func (DeferredNotificationEvent) RoutingKey() string {
+	return NotificationRoutingKey
+}
+
+func (e DeferredNotificationEvent) OrderingKey() string {
+	return "user:" + strconv.FormatInt(e.UserID, 10)
+}
+
+// SessionReminderEvent is enqueued by the reminder scheduler, StartsAt is the
+// wall clock start of the session.
+type SessionReminderEvent struct {
+	UserID   int64     `json:"user_id"`
+	CourseID int64     `json:"course_id"`
+	EventID  int64     `json:"event_id"`
+	StartsAt time.Time `json:"starts_at"`
+}
+
+func (SessionReminderEvent) EventType() string {
+	return EventTypeSessionReminder
+}
+
+func (SessionReminderEvent) SchemaVersion() int {
+	return 1
+}
+
+func (SessionReminderEvent) RoutingKey() string {
+	return NotificationRoutingKey
+}
+
+func (e SessionReminderEvent) OrderingKey() string {
+	return "user:" + strconv.FormatInt(e.UserID, 10)
+}
+
+// MedicalCertificateExpiringEvent is enqueued by the reminder scheduler once
+// per certificate, ExpiresAt is the wall clock expiry of the certificate.
+type MedicalCertificateExpiringEvent struct {
+	UserID        int64     `json:"user_id"`
+	CertificateID int64     `json:"certificate_id"`
+	ExpiresAt     time.Time `json:"expires_at"`
+}
+
+func (MedicalCertificateExpiringEvent) EventType() string {
+	return EventTypeMedicalCertificateExpiring
+}
+
+func (MedicalCertificateExpiringEvent) SchemaVersion() int {
+	return 1
+}
+
+func (MedicalCertificateExpiringEvent) RoutingKey() string {
+	return NotificationRoutingKey
+}
+
+func (e MedicalCertificateExpiringEvent) OrderingKey() string {
+	return "user:" + strconv.FormatInt(e.UserID, 10)
+}
+
+// NotificationDigestEvent is enqueued by the digest scheduler with the items
+// collected for the user since the previous digest.
+type NotificationDigestEvent struct {
+	UserID  int64        `json:"user_id"`
+	Mode    DeliveryMode `json:"mode"`
+	ItemIDs []int64      `json:"item_ids"`
+}
+
+func (NotificationDigestEvent) EventType() string {
+	return EventTypeNotificationDigest
+}
+
+func (NotificationDigestEvent) SchemaVersion() int {
+	return 1
+}
+
+func (NotificationDigestEvent) RoutingKey() string {
+	return NotificationRoutingKey
+}
+
+func (e NotificationDigestEvent) OrderingKey() string {
+	return "user:" + strconv.FormatInt(e.UserID, 10)
 }
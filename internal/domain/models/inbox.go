@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// InboxItem is an in-app notification of a user.
+type InboxItem struct {
+	ID          int64      `json:"id" db:"inbox_notifications.id"`
+	UserID      int64      `json:"-" db:"inbox_notifications.personal_info_id"`
+	Subject     string     `json:"subject" db:"inbox_notifications.subject"`
+	Body        string     `json:"body" db:"inbox_notifications.body"`
+	ContentType string     `json:"content_type" db:"inbox_notifications.content_type"`
+	ReadAt      *time.Time `json:"read_at,omitempty" db:"inbox_notifications.read_at"`
+	CreatedAt   time.Time  `json:"created_at" db:"inbox_notifications.created_at"`
+}
+
+// InboxFilter pages through the inbox newest first, BeforeID is the last item
+// of the previous page.
+type InboxFilter struct {
+	UnreadOnly bool
+	BeforeID   int64
+	Limit      int64
+}
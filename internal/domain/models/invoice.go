@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// Invoice is a payment for the course expected from an enrolled user. A
+// course with unpaid invoices is archived only when forced.
+type Invoice struct {
+	ID       int64      `json:"id" db:"invoices.id"`
+	CourseID int64      `json:"course_id" db:"invoices.course_id"`
+	UserID   int64      `json:"user_id" db:"invoices.personal_info_id"`
+	Amount   float64    `json:"amount" db:"invoices.amount"`
+	IssuedAt time.Time  `json:"issued_at" db:"invoices.issued_at"`
+	PaidAt   *time.Time `json:"paid_at" db:"invoices.paid_at"`
+}
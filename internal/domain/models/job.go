@@ -0,0 +1,31 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Job is a broker message stored in the Postgres job queue. Attempts counts
+// deliveries since the job was enqueued or last replayed, together with
+// Replays it is the receipt of the current delivery. RetryCount counts failed
+// processing attempts.
+type Job struct {
+	ID             int64           `db:"jobs.id"`
+	Queue          string          `db:"jobs.queue"`
+	MessageID      string          `db:"jobs.message_id"`
+	RoutingKey     string          `db:"jobs.routing_key"`
+	Type           string          `db:"jobs.type"`
+	CorrelationID  string          `db:"jobs.correlation_id"`
+	Producer       string          `db:"jobs.producer"`
+	ContentType    string          `db:"jobs.content_type"`
+	Headers        json.RawMessage `db:"jobs.headers"`
+	Body           []byte          `db:"jobs.body"`
+	PublishedAt    time.Time       `db:"jobs.published_at"`
+	Attempts       int             `db:"jobs.attempts"`
+	RetryCount     int             `db:"jobs.retry_count"`
+	LastError      *string         `db:"jobs.last_error"`
+	CreatedAt      time.Time       `db:"jobs.created_at"`
+	RunAt          time.Time       `db:"jobs.run_at"`
+	DeadLetteredAt *time.Time      `db:"jobs.dead_lettered_at"`
+	Replays        int             `db:"jobs.replays"`
+}
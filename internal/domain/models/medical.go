@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// MedicalCertificate admits the user to training until it expires. NotifiedAt
+// is set once the expiry was announced to the user and the trainers.
+type MedicalCertificate struct {
+	ID         int64      `json:"id" db:"medical_certificates.id"`
+	UserID     int64      `json:"user_id" db:"medical_certificates.personal_info_id"`
+	ExpiresAt  time.Time  `json:"expires_at" db:"medical_certificates.expires_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"medical_certificates.created_at"`
+	NotifiedAt *time.Time `json:"notified_at,omitempty" db:"medical_certificates.notified_at"`
+}
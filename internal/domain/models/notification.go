@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// EventTypeOTP marks deliveries of one-time codes, the codes are not logged
+// and the deliveries can't be resent.
+const EventTypeOTP = "otp"
+
+type NotificationStatus string
+
+const (
+	NotificationQueued NotificationStatus = "queued"
+	NotificationSent   NotificationStatus = "sent"
+	NotificationFailed NotificationStatus = "failed"
+)
+
+// NotificationDelivery is a notification sent to one channel of a user. The
+// status and provider response are those of the last attempt.
+type NotificationDelivery struct {
+	ID               int64               `json:"id" db:"notification_deliveries.id"`
+	UserID           *int64              `json:"user_id,omitempty" db:"notification_deliveries.personal_info_id"`
+	EventType        string              `json:"event_type" db:"notification_deliveries.event_type"`
+	Locale           *string             `json:"locale,omitempty" db:"notification_deliveries.locale"`
+	TemplateVersion  int                 `json:"template_version" db:"notification_deliveries.template_version"`
+	Channel          NotificationChannel `json:"channel" db:"notification_deliveries.channel"`
+	Recipients       []string            `json:"recipients" db:"notification_deliveries.recipients"`
+	ContentType      string              `json:"content_type" db:"notification_deliveries.content_type"`
+	Subject          string              `json:"subject" db:"notification_deliveries.subject"`
+	Body             string              `json:"body" db:"notification_deliveries.body"`
+	AltBody          string              `json:"alt_body,omitempty" db:"notification_deliveries.alt_body"`
+	Status           NotificationStatus  `json:"status" db:"notification_deliveries.status"`
+	Attempts         int                 `json:"attempts" db:"notification_deliveries.attempts"`
+	ProviderResponse *string             `json:"provider_response,omitempty" db:"notification_deliveries.provider_response"`
+	CreatedAt        time.Time           `json:"created_at" db:"notification_deliveries.created_at"`
+	UpdatedAt        time.Time           `json:"updated_at" db:"notification_deliveries.updated_at"`
+	SentAt           *time.Time          `json:"sent_at,omitempty" db:"notification_deliveries.sent_at"`
+
+	Attachments []NotificationAttachment `json:"attachments,omitempty" db:"notification_deliveries.attachments"`
+}
+
+// NotificationAttachment is a file sent with an email notification.
+type NotificationAttachment struct {
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	Data        []byte `json:"data"`
+	Inline      bool   `json:"inline,omitempty"`
+}
+
+// NotificationDeliveryFilter selects deliveries from the log, zero fields
+// match every delivery.
+type NotificationDeliveryFilter struct {
+	UserID    int64
+	EventType string
+	Status    NotificationStatus
+	Limit     int64
+}
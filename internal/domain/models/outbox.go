@@ -0,0 +1,33 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// DomainEvent is an event that is published to the broker through the outbox.
+type DomainEvent interface {
+	EventType() string
+	// SchemaVersion is incremented on every incompatible payload change.
+	SchemaVersion() int
+	RoutingKey() string
+}
+
+// OrderedEvent is a domain event that must be consumed in order with other
+// events of the same key.
+type OrderedEvent interface {
+	DomainEvent
+	OrderingKey() string
+}
+
+type OutboxMessage struct {
+	ID            int64           `db:"outbox.id"`
+	EventType     string          `db:"outbox.event_type"`
+	RoutingKey    string          `db:"outbox.routing_key"`
+	Payload       json.RawMessage `db:"outbox.payload"`
+	Attempts      int             `db:"outbox.attempts"`
+	LastError     *string         `db:"outbox.last_error"`
+	CreatedAt     time.Time       `db:"outbox.created_at"`
+	NextAttemptAt time.Time       `db:"outbox.next_attempt_at"`
+	SentAt        *time.Time      `db:"outbox.sent_at"`
+}
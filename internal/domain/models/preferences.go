@@ -0,0 +1,178 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrInvalidQuietHours = errors.New("invalid quiet hours")
+
+// NotificationCategory groups notifications the user can route separately.
+type NotificationCategory string
+
+const (
+	CategoryEnrollment     NotificationCategory = "enrollment"
+	CategoryCancellation   NotificationCategory = "cancellation"
+	CategoryScheduleChange NotificationCategory = "schedule_change"
+	CategoryBilling        NotificationCategory = "billing"
+	CategoryReminder       NotificationCategory = "reminder"
+	// CategoryMedicalCertificate announces expiring medical certificates.
+	CategoryMedicalCertificate NotificationCategory = "medical_certificate"
+)
+
+var NotificationCategories = []NotificationCategory{
+	CategoryEnrollment,
+	CategoryCancellation,
+	CategoryScheduleChange,
+	CategoryBilling,
+	CategoryReminder,
+	CategoryMedicalCertificate,
+}
+
+// NotificationChannel values match the notification types of the providers.
+type NotificationChannel string
+
+const (
+	ChannelEmail    NotificationChannel = "email"
+	ChannelSMS      NotificationChannel = "sms"
+	ChannelTelegram NotificationChannel = "telegram"
+	ChannelInApp    NotificationChannel = "in_app"
+)
+
+var NotificationChannels = []NotificationChannel{
+	ChannelEmail,
+	ChannelSMS,
+	ChannelTelegram,
+	ChannelInApp,
+}
+
+// Intrusive reports whether the channel disturbs the user, notifications to
+// such channels are held back during quiet hours.
+func (c NotificationChannel) Intrusive() bool {
+	return c == ChannelSMS || c == ChannelTelegram
+}
+
+// DeliveryMode tells whether notifications of a category are sent at once or
+// collected into a daily or weekly digest email.
+type DeliveryMode string
+
+const (
+	DeliveryInstant DeliveryMode = "instant"
+	DeliveryDaily   DeliveryMode = "daily"
+	DeliveryWeekly  DeliveryMode = "weekly"
+)
+
+var DeliveryModes = []DeliveryMode{DeliveryInstant, DeliveryDaily, DeliveryWeekly}
+
+// Digest reports whether notifications are collected into a digest.
+func (m DeliveryMode) Digest() bool {
+	return m == DeliveryDaily || m == DeliveryWeekly
+}
+
+// defaultChannels are used for categories the user has not configured, sms
+// costs money and is opt-in.
+var defaultChannels = []NotificationChannel{ChannelEmail, ChannelTelegram, ChannelInApp}
+
+type NotificationPreferences struct {
+	UserID int64 `json:"-" db:"notification_preferences.personal_info_id"`
+	// Channels per category, an empty list opts out of the category.
+	Channels   map[NotificationCategory][]NotificationChannel `json:"channels" db:"notification_preferences.channels"`
+	QuietHours *QuietHours                                    `json:"quiet_hours" db:"notification_preferences.quiet_hours"`
+	// Delivery per category, categories left out are delivered instantly.
+	Delivery map[NotificationCategory]DeliveryMode `json:"delivery" db:"notification_preferences.delivery"`
+	// Locale the notifications are rendered in.
+	Locale    string     `json:"locale" db:"notification_preferences.locale"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty" db:"notification_preferences.updated_at"`
+}
+
+// DefaultNotificationPreferences are the preferences of a user who has not
+// saved any.
+func DefaultNotificationPreferences(userID int64) *NotificationPreferences {
+	channels := make(map[NotificationCategory][]NotificationChannel, len(NotificationCategories))
+	for _, category := range NotificationCategories {
+		channels[category] = append([]NotificationChannel(nil), defaultChannels...)
+	}
+
+	return &NotificationPreferences{
+		UserID:   userID,
+		Channels: channels,
+		Delivery: make(map[NotificationCategory]DeliveryMode),
+		Locale:   DefaultLocale,
+	}
+}
+
+// ChannelsOf returns the channels of the category.
+func (p *NotificationPreferences) ChannelsOf(category NotificationCategory) []NotificationChannel {
+	channels, ok := p.Channels[category]
+	if !ok {
+		return defaultChannels
+	}
+	return channels
+}
+
+// DeliveryOf returns the delivery mode of the category.
+func (p *NotificationPreferences) DeliveryOf(category NotificationCategory) DeliveryMode {
+	mode, ok := p.Delivery[category]
+	if !ok {
+		return DeliveryInstant
+	}
+	return mode
+}
+
+// QuietHours is a daily period in the time zone of the user, it may span
+// midnight.
+type QuietHours struct {
+	Start    string `json:"start" validate:"required"`
+	End      string `json:"end" validate:"required"`
+	TimeZone string `json:"time_zone" validate:"required"`
+}
+
+// Validate checks the period is given as HH:MM and the time zone is known.
+func (q *QuietHours) Validate() error {
+	if _, err := time.Parse("15:04", q.Start); err != nil {
+		return fmt.Errorf("%w: start %q is not HH:MM", ErrInvalidQuietHours, q.Start)
+	}
+	if _, err := time.Parse("15:04", q.End); err != nil {
+		return fmt.Errorf("%w: end %q is not HH:MM", ErrInvalidQuietHours, q.End)
+	}
+	if q.Start == q.End {
+		return fmt.Errorf("%w: start and end are equal", ErrInvalidQuietHours)
+	}
+	if _, err := time.LoadLocation(q.TimeZone); err != nil {
+		return fmt.Errorf("%w: unknown time zone %q", ErrInvalidQuietHours, q.TimeZone)
+	}
+	return nil
+}
+
+// Remaining returns how long the quiet hours last after now, zero if now is
+// outside of them.
+func (q *QuietHours) Remaining(now time.Time) time.Duration {
+	if q == nil || q.Validate() != nil {
+		return 0
+	}
+
+	loc, _ := time.LoadLocation(q.TimeZone)
+	now = now.In(loc)
+	start, _ := time.Parse("15:04", q.Start)
+	end, _ := time.Parse("15:04", q.End)
+
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	at := func(t time.Time, days int) time.Time {
+		return time.Date(day.Year(), day.Month(), day.Day()+days, t.Hour(), t.Minute(), 0, 0, loc)
+	}
+
+	// the period that started today or, when it spans midnight, yesterday
+	for _, days := range []int{0, -1} {
+		from := at(start, days)
+		to := at(end, days)
+		if !to.After(from) {
+			to = at(end, days+1)
+		}
+		if !now.Before(from) && now.Before(to) {
+			return to.Sub(now)
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuietHoursRemaining(t *testing.T) {
+	q := &QuietHours{Start: "22:00", End: "08:00", TimeZone: "Europe/Moscow"}
+	msk := time.FixedZone("MSK", 3*60*60)
+
+	tests := []struct {
+		now  time.Time
+		want time.Duration
+	}{
+		{time.Date(2024, 3, 1, 21, 59, 0, 0, msk), 0},
+		{time.Date(2024, 3, 1, 22, 0, 0, 0, msk), 10 * time.Hour},
+		{time.Date(2024, 3, 2, 7, 30, 0, 0, msk), 30 * time.Minute},
+		{time.Date(2024, 3, 2, 8, 0, 0, 0, msk), 0},
+		// the time zone of the user applies, 19:30 UTC is 22:30 in Moscow
+		{time.Date(2024, 3, 1, 19, 30, 0, 0, time.UTC), 9*time.Hour + 30*time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := q.Remaining(tt.now); got != tt.want {
+			t.Errorf("Remaining(%s) = %s, want %s", tt.now, got, tt.want)
+		}
+	}
+
+	day := &QuietHours{Start: "13:00", End: "15:00", TimeZone: "UTC"}
+	if got := day.Remaining(time.Date(2024, 3, 1, 14, 0, 0, 0, time.UTC)); got != time.Hour {
+		t.Errorf("Remaining within a daytime period = %s, want 1h", got)
+	}
+	if got := day.Remaining(time.Date(2024, 3, 1, 16, 0, 0, 0, time.UTC)); got != 0 {
+		t.Errorf("Remaining after a daytime period = %s, want 0", got)
+	}
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// SessionReminder is a reminder of a session sent to an enrolled user the
+// offset before the session starts.
+type SessionReminder struct {
+	CourseID int64
+	EventID  int64
+	UserID   int64
+	StartsAt time.Time
+	Offset   time.Duration
+}
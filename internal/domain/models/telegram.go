@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// TelegramLink binds a user to the telegram chat notifications are sent to.
+type TelegramLink struct {
+	UserID   int64     `json:"user_id" db:"telegram_links.personal_info_id"`
+	ChatID   int64     `json:"chat_id" db:"telegram_links.chat_id"`
+	LinkedAt time.Time `json:"linked_at" db:"telegram_links.linked_at"`
+}
+
+// TelegramLinkCode is a one-time code the user sends to the bot to link the chat.
+type TelegramLinkCode struct {
+	Code      string    `json:"code"`
+	ExpiresAt time.Time `json:"expires_at"`
+	// BotURL opens the bot with the code already filled in.
+	BotURL string `json:"bot_url,omitempty"`
+}
@@ -0,0 +1,97 @@
+package models
+
+import "time"
+
+// Locales notifications are rendered in.
+const (
+	LocaleRU = "ru"
+	LocaleEN = "en"
+
+	DefaultLocale = LocaleRU
+)
+
+var Locales = []string{LocaleRU, LocaleEN}
+
+// DefaultLayout is the shared layout of emails.
+const DefaultLayout = "email"
+
+// Templates of the notifications sent to the trainers of a course, the events
+// themselves are rendered with the templates of their event types.
+const (
+	TemplateTrainerEnrollmentCreated   = "trainer.enrollment.created"
+	TemplateTrainerEnrollmentCancelled = "trainer.enrollment.cancelled"
+	TemplateTrainerMedicalCertificate  = "trainer.medical_certificate.expiring"
+)
+
+// NotificationTemplate renders the notification of an event type in a locale.
+// Every save adds a version, the latest one is used. Templates embedded in
+// the binary have version 0.
+type NotificationTemplate struct {
+	ID        int64     `json:"id,omitempty" db:"notification_templates.id"`
+	EventType string    `json:"event_type" db:"notification_templates.event_type"`
+	Locale    string    `json:"locale" db:"notification_templates.locale"`
+	Version   int       `json:"version" db:"notification_templates.version"`
+	Layout    string    `json:"layout" db:"notification_templates.layout"`
+	Subject   string    `json:"subject" db:"notification_templates.subject"`
+	Text      string    `json:"text" db:"notification_templates.text_body"`
+	HTML      string    `json:"html" db:"notification_templates.html_body"`
+	CreatedAt time.Time `json:"created_at,omitempty" db:"notification_templates.created_at"`
+}
+
+// RenderedTemplate is a notification ready to be sent, HTML is the body of
+// emails and Text of the other channels. Locale and Version are those of the
+// template that was rendered.
+type RenderedTemplate struct {
+	Locale  string `json:"locale"`
+	Version int    `json:"version"`
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"html"`
+}
+
+// EnrollmentTemplateData is rendered into enrollment.created,
+// enrollment.cancelled and the trainer enrollment templates.
+type EnrollmentTemplateData struct {
+	User       string
+	CourseName string
+}
+
+// SessionReminderTemplateData is rendered into session.reminder templates.
+type SessionReminderTemplateData struct {
+	User       string
+	CourseName string
+	Session    string
+	// StartsAt is formatted as 02.01.2006 15:04.
+	StartsAt string
+}
+
+// ScheduleChangeTemplateData is rendered into schedule.changed templates.
+type ScheduleChangeTemplateData struct {
+	User       string
+	CourseName string
+}
+
+// MedicalCertificateTemplateData is rendered into medical_certificate.expiring
+// and the trainer medical certificate templates, CourseName is set for
+// trainers only.
+type MedicalCertificateTemplateData struct {
+	User       string
+	CourseName string
+	// ExpiresAt is formatted as 02.01.2006.
+	ExpiresAt string
+}
+
+// DigestTemplateData is rendered into notification.digest templates.
+type DigestTemplateData struct {
+	User   string
+	Weekly bool
+	Items  []DigestTemplateItem
+}
+
+// DigestTemplateItem is a notification collected into the digest.
+type DigestTemplateItem struct {
+	Subject string
+	Text    string
+	// At is formatted as 02.01.2006 15:04.
+	At string
+}
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 type User struct {
 	ID         int64  `json:"id" db:"personal_info.personal_info_id"`
 	FirstName  string `json:"first_name" db:"personal_info.name" validate:"required"`
@@ -10,6 +12,9 @@ type User struct {
 	Phone      string `json:"phone" db:"personal_info.phone" validate:"required,e164"`
 	Role       Role   `json:"role,omitempty" db:"personal_info.roles_id"`
 	PositionID int64  `json:"position_id,omitempty" db:"positions.position_id"`
+	Version    int64  `json:"version,omitempty" db:"personal_info.version"`
+	// PhoneVerifiedAt is set once the user confirmed the phone with a one-time code.
+	PhoneVerifiedAt *time.Time `json:"phone_verified_at,omitempty" db:"personal_info.phone_verified_at"`
 }
 
 type UserInfo struct {
@@ -21,6 +26,25 @@ type UserInfo struct {
 	Phone        string `json:"phone" db:"personal_info.phone"`
 	Role         Role   `json:"role,omitempty" db:"personal_info.roles_id"`
 	PositionName string `json:"position_name,omitempty"`
+	Version      int64  `json:"version,omitempty" db:"personal_info.version"`
+
+	PhoneVerifiedAt *time.Time `json:"phone_verified_at,omitempty" db:"personal_info.phone_verified_at"`
+}
+
+// Info returns the user without credentials.
+func (u *User) Info() UserInfo {
+	return UserInfo{
+		ID:         u.ID,
+		FirstName:  u.FirstName,
+		MiddleName: u.MiddleName,
+		Surname:    u.Surname,
+		Email:      u.Email,
+		Phone:      u.Phone,
+		Role:       u.Role,
+		Version:    u.Version,
+
+		PhoneVerifiedAt: u.PhoneVerifiedAt,
+	}
 }
 
 //go:generate ../../../tools/enumer -type=Role -json -transform=snake
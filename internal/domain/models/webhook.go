@@ -0,0 +1,59 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WebhookEventTypes are the event types webhooks can subscribe to.
+var WebhookEventTypes = []string{
+	EventTypeEnrollmentCreated,
+	EventTypeUserUpdated,
+	EventTypeCourseUpdated,
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookPending   WebhookDeliveryStatus = "pending"
+	WebhookSucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookFailed    WebhookDeliveryStatus = "failed"
+)
+
+type WebhookSubscription struct {
+	ID         int64    `json:"id" db:"webhook_subscriptions.id"`
+	URL        string   `json:"url" db:"webhook_subscriptions.url"`
+	EventTypes []string `json:"event_types" db:"webhook_subscriptions.event_types"`
+	// Secret signs the payloads, it is returned only once on creation.
+	Secret    string    `json:"-" db:"webhook_subscriptions.secret"`
+	Active    bool      `json:"active" db:"webhook_subscriptions.active"`
+	CreatedAt time.Time `json:"created_at" db:"webhook_subscriptions.created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"webhook_subscriptions.updated_at"`
+}
+
+type WebhookDelivery struct {
+	ID               int64                 `json:"id" db:"webhook_deliveries.id"`
+	SubscriptionID   int64                 `json:"subscription_id" db:"webhook_deliveries.subscription_id"`
+	EventID          string                `json:"event_id" db:"webhook_deliveries.event_id"`
+	EventType        string                `json:"event_type" db:"webhook_deliveries.event_type"`
+	Payload          json.RawMessage       `json:"payload" db:"webhook_deliveries.payload"`
+	Status           WebhookDeliveryStatus `json:"status" db:"webhook_deliveries.status"`
+	Attempts         int                   `json:"attempts" db:"webhook_deliveries.attempts"`
+	LastResponseCode *int                  `json:"last_response_code,omitempty" db:"webhook_deliveries.last_response_code"`
+	LastError        *string               `json:"last_error,omitempty" db:"webhook_deliveries.last_error"`
+	CreatedAt        time.Time             `json:"created_at" db:"webhook_deliveries.created_at"`
+	NextAttemptAt    time.Time             `json:"next_attempt_at" db:"webhook_deliveries.next_attempt_at"`
+	DeliveredAt      *time.Time            `json:"delivered_at,omitempty" db:"webhook_deliveries.delivered_at"`
+	// History is the attempt log, it is filled only for a single delivery.
+	History []*WebhookAttempt `json:"history,omitempty"`
+}
+
+type WebhookAttempt struct {
+	ID           int64     `json:"id" db:"webhook_delivery_attempts.id"`
+	DeliveryID   int64     `json:"delivery_id" db:"webhook_delivery_attempts.delivery_id"`
+	ResponseCode *int      `json:"response_code,omitempty" db:"webhook_delivery_attempts.response_code"`
+	ResponseBody *string   `json:"response_body,omitempty" db:"webhook_delivery_attempts.response_body"`
+	Error        *string   `json:"error,omitempty" db:"webhook_delivery_attempts.error"`
+	DurationMs   int64     `json:"duration_ms" db:"webhook_delivery_attempts.duration_ms"`
+	AttemptedAt  time.Time `json:"attempted_at" db:"webhook_delivery_attempts.attempted_at"`
+}
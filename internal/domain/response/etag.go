@@ -0,0 +1,35 @@
+package response
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// SetETag sets the ETag header to the entity version.
+func SetETag(c *gin.Context, version int64) {
+	c.Header("ETag", fmt.Sprintf("%q", strconv.FormatInt(version, 10)))
+}
+
+// IfMatch returns the entity version expected by the If-Match header.
+// It returns 0 if the header is absent or matches any version.
+func IfMatch(c *gin.Context) (int64, bool) {
+	value := strings.TrimSpace(c.GetHeader("If-Match"))
+	if value == "" || value == "*" {
+		return 0, true
+	}
+
+	value = strings.Trim(strings.TrimPrefix(value, "W/"), `"`)
+	version, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || version < 1 {
+		return 0, false
+	}
+
+	return version, true
+}
+
+func PreconditionFailed(c *gin.Context, err error) {
+	New(http.StatusPreconditionFailed, err.Error()).Error(c)
+}
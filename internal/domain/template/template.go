@@ -0,0 +1,56 @@
+// Package template embeds the shared layouts and the default notification
+// templates, the defaults are used when no template is stored in the database.
+package template
+
+import (
+	domainerrors "dussh/internal/domain/errors"
+	"dussh/internal/domain/models"
+	"embed"
+	"errors"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+//go:embed layouts defaults
+var files embed.FS
+
+// Default returns the embedded template of the event type in the locale, the
+// parts are read from defaults/<event type>/<locale>.{subject.txt,txt,html}.
+func Default(eventType, locale string) (*models.NotificationTemplate, error) {
+	dir := path.Join("defaults", eventType)
+
+	parts := make([]string, 3)
+	for i, ext := range []string{".subject.txt", ".txt", ".html"} {
+		data, err := files.ReadFile(path.Join(dir, locale+ext))
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, domainerrors.ErrTemplateNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		parts[i] = string(data)
+	}
+
+	return &models.NotificationTemplate{
+		EventType: eventType,
+		Locale:    locale,
+		Layout:    models.DefaultLayout,
+		Subject:   strings.TrimSpace(parts[0]),
+		Text:      parts[1],
+		HTML:      parts[2],
+	}, nil
+}
+
+// Layout returns the shared layout the html part is rendered into.
+func Layout(name string) (string, error) {
+	data, err := files.ReadFile(path.Join("layouts", name+".html"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", domainerrors.ErrUnknownTemplateLayout
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
@@ -0,0 +1,213 @@
+package idempotency
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"dussh/internal/cache/redis"
+	"dussh/pkg/jwt"
+	"dussh/pkg/requestid"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// Header is the HTTP header carrying the client generated idempotency key.
+	Header = "Idempotency-Key"
+	// ReplayedHeader is set on responses replayed from the stored record.
+	ReplayedHeader = "Idempotent-Replayed"
+
+	keyPrefix    = "idempotency:"
+	maxKeyLength = 255
+	// maxBodySize bounds the request body buffered for the fingerprint.
+	maxBodySize = 1 << 20
+	// pendingTTL bounds how long a key stays locked if the instance dies mid-request.
+	pendingTTL = time.Minute
+)
+
+var (
+	ErrKeyTooLong   = errors.New("idempotency key is too long")
+	ErrKeyReused    = errors.New("idempotency key was already used with a different request")
+	ErrInProgress   = errors.New("request with this idempotency key is still in progress")
+	ErrUnavailable  = errors.New("idempotency storage is unavailable")
+	ErrBodyTooLarge = errors.New("request body is too large")
+)
+
+// record is the state stored in the cache for a single idempotency key.
+type record struct {
+	Fingerprint string      `json:"fingerprint"`
+	Done        bool        `json:"done"`
+	Status      int         `json:"status,omitempty"`
+	Header      http.Header `json:"header,omitempty"`
+	Body        []byte      `json:"body,omitempty"`
+}
+
+// New returns middleware that makes a handler idempotent for requests carrying
+// the Idempotency-Key header. The first response for a key is stored for ttl
+// and replayed on retries, a retry with a different payload gets 422.
+// Requests without the header are passed through unchanged.
+func New(cache redis.Cache, ttl time.Duration, log *zap.Logger) gin.HandlerFunc {
+	log = log.Named("idempotency")
+
+	return func(c *gin.Context) {
+		key := c.GetHeader(Header)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxKeyLength {
+			abort(c, http.StatusBadRequest, ErrKeyTooLong)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBodySize))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				abort(c, http.StatusRequestEntityTooLarge, ErrBodyTooLarge)
+				return
+			}
+			abort(c, http.StatusBadRequest, err)
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		storageKey := keyPrefix + actor(c) + ":" + key
+		fingerprint := fingerprint(c.Request.Method, c.FullPath(), c.Request.URL.RawQuery, body)
+
+		pending, err := json.Marshal(record{Fingerprint: fingerprint})
+		if err != nil {
+			abort(c, http.StatusInternalServerError, err)
+			return
+		}
+
+		acquired, err := cache.SetNX(c, storageKey, pending, pendingTTL)
+		if err != nil {
+			log.Error("failed to lock idempotency key", zap.Error(err))
+			abort(c, http.StatusServiceUnavailable, ErrUnavailable)
+			return
+		}
+		if !acquired {
+			replay(c, cache, storageKey, fingerprint, log)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		c.Next()
+
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			// server errors are not final, let the client retry with the same key
+			if err := cache.Delete(c, storageKey); err != nil {
+				log.Error("failed to release idempotency key", zap.Error(err))
+			}
+			return
+		}
+
+		done, err := json.Marshal(record{
+			Fingerprint: fingerprint,
+			Done:        true,
+			Status:      status,
+			Header:      storedHeader(recorder.Header()),
+			Body:        recorder.body.Bytes(),
+		})
+		if err != nil {
+			log.Error("failed to marshal idempotency record", zap.Error(err))
+			return
+		}
+
+		if err := cache.Set(c, storageKey, done, ttl); err != nil {
+			log.Error("failed to store idempotency record", zap.Error(err))
+		}
+	}
+}
+
+func replay(c *gin.Context, cache redis.Cache, storageKey, fingerprint string, log *zap.Logger) {
+	value, err := cache.Get(c, storageKey)
+	if err != nil {
+		if errors.Is(err, redis.ErrNotFound) {
+			// the previous attempt failed and released the key in between
+			abort(c, http.StatusConflict, ErrInProgress)
+			return
+		}
+		log.Error("failed to get idempotency record", zap.Error(err))
+		abort(c, http.StatusServiceUnavailable, ErrUnavailable)
+		return
+	}
+
+	var rec record
+	if err := json.Unmarshal([]byte(value), &rec); err != nil {
+		log.Error("failed to unmarshal idempotency record", zap.Error(err))
+		abort(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if rec.Fingerprint != fingerprint {
+		abort(c, http.StatusUnprocessableEntity, ErrKeyReused)
+		return
+	}
+	if !rec.Done {
+		abort(c, http.StatusConflict, ErrInProgress)
+		return
+	}
+
+	for name, values := range rec.Header {
+		for _, v := range values {
+			c.Writer.Header().Add(name, v)
+		}
+	}
+	c.Header(ReplayedHeader, strconv.FormatBool(true))
+	c.Data(rec.Status, rec.Header.Get("Content-Type"), rec.Body)
+	c.Abort()
+}
+
+// actor scopes keys by the authenticated user, or by client IP for anonymous requests.
+func actor(c *gin.Context) string {
+	if claims, ok := jwt.UserClaimsFromContext(c); ok {
+		return "user:" + strconv.FormatInt(claims.ID, 10)
+	}
+	return "ip:" + c.ClientIP()
+}
+
+func fingerprint(method, path, query string, body []byte) string {
+	h := sha256.New()
+	for _, part := range [][]byte{[]byte(method), []byte(path), []byte(query), body} {
+		h.Write(part)
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func storedHeader(header http.Header) http.Header {
+	stored := header.Clone()
+	stored.Del(requestid.Header)
+	return stored
+}
+
+func abort(c *gin.Context, status int, err error) {
+	c.AbortWithStatusJSON(status, gin.H{"message": err.Error()})
+}
+
+// responseRecorder copies everything written to the response.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
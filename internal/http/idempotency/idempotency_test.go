@@ -0,0 +1,155 @@
+package idempotency
+
+import (
+	"context"
+	"dussh/internal/cache/redis"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeCache struct {
+	redis.Cache
+	values map[string]string
+}
+
+func (c *fakeCache) Get(_ context.Context, key string) (string, error) {
+	v, ok := c.values[key]
+	if !ok {
+		return "", redis.ErrNotFound
+	}
+	return v, nil
+}
+
+func (c *fakeCache) Set(_ context.Context, key string, value any, _ time.Duration) error {
+	c.values[key] = string(value.([]byte))
+	return nil
+}
+
+func (c *fakeCache) SetNX(ctx context.Context, key string, value any, ttl time.Duration) (bool, error) {
+	if _, ok := c.values[key]; ok {
+		return false, nil
+	}
+	return true, c.Set(ctx, key, value, ttl)
+}
+
+func (c *fakeCache) Delete(_ context.Context, key string) error {
+	delete(c.values, key)
+	return nil
+}
+
+func TestIdempotency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name string
+		// status the handler responds with
+		status int
+		first  string
+		// second is sent with the same key after the first, or from within
+		// the handler of the first if nested is set; empty to send one request
+		second string
+		nested bool
+
+		wantStatus   int
+		wantCalls    int
+		wantReplayed bool
+	}{
+		{
+			name:         "replay returns the stored response",
+			status:       http.StatusCreated,
+			first:        `{"user_id":1}`,
+			second:       `{"user_id":1}`,
+			wantStatus:   http.StatusCreated,
+			wantCalls:    1,
+			wantReplayed: true,
+		},
+		{
+			name:       "same key with a different payload",
+			status:     http.StatusCreated,
+			first:      `{"user_id":1}`,
+			second:     `{"user_id":2}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCalls:  1,
+		},
+		{
+			name:       "key still in progress",
+			status:     http.StatusCreated,
+			first:      `{"user_id":1}`,
+			second:     `{"user_id":1}`,
+			nested:     true,
+			wantStatus: http.StatusConflict,
+			wantCalls:  1,
+		},
+		{
+			name:       "server error releases the key",
+			status:     http.StatusInternalServerError,
+			first:      `{"user_id":1}`,
+			second:     `{"user_id":1}`,
+			wantStatus: http.StatusInternalServerError,
+			wantCalls:  2,
+		},
+		{
+			name:       "oversized body",
+			status:     http.StatusCreated,
+			first:      `{"name":"` + strings.Repeat("x", maxBodySize) + `"}`,
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantCalls:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				router = gin.New()
+				calls  int
+				nested *httptest.ResponseRecorder
+			)
+
+			send := func(body string) *httptest.ResponseRecorder {
+				req := httptest.NewRequest(http.MethodPost, "/users/", strings.NewReader(body))
+				req.Header.Set(Header, "key-1")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				return w
+			}
+
+			cache := &fakeCache{values: make(map[string]string)}
+			router.POST("/users/", New(cache, time.Hour, zap.NewNop()), func(c *gin.Context) {
+				calls++
+				if tt.nested && nested == nil {
+					nested = send(tt.second)
+				}
+				c.JSON(tt.status, gin.H{"call": calls})
+			})
+
+			first := send(tt.first)
+			last := first
+			switch {
+			case tt.nested:
+				last = nested
+			case tt.second != "":
+				last = send(tt.second)
+			}
+
+			if last.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", last.Code, tt.wantStatus, last.Body)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("handler called %d times, want %d", calls, tt.wantCalls)
+			}
+
+			replayed := last.Header().Get(ReplayedHeader) == "true"
+			if replayed != tt.wantReplayed {
+				t.Errorf("replayed = %v, want %v", replayed, tt.wantReplayed)
+			}
+			if tt.wantReplayed && last.Body.String() != first.Body.String() {
+				t.Errorf("replayed body = %s, want %s", last.Body, first.Body)
+			}
+		})
+	}
+}
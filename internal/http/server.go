@@ -1,12 +1,26 @@
 package http
 
 import (
+	"dussh/internal/cache/redis"
 	"dussh/internal/config"
+	"dussh/internal/http/idempotency"
+	"dussh/internal/services/audit"
 	"dussh/internal/services/auth"
 	"dussh/internal/services/course"
+	"dussh/internal/services/deadletter"
+	"dussh/internal/services/debug"
+	"dussh/internal/services/inbox"
+	"dussh/internal/services/mailsink"
+	"dussh/internal/services/notification"
+	"dussh/internal/services/preference"
+	"dussh/internal/services/telegram"
+	"dussh/internal/services/template"
 	"dussh/internal/services/user"
+	"dussh/internal/services/webhook"
 	"dussh/pkg/rbac"
+	"dussh/pkg/requestid"
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 	"net/http"
 	"time"
 )
@@ -15,6 +29,7 @@ func NewRouter() *gin.Engine {
 	router := gin.New()
 	router.Use(gin.Logger())
 	router.Use(gin.Recovery())
+	router.Use(requestid.New())
 
 	return router
 }
@@ -41,10 +56,38 @@ func MustNewModules(
 	authAPI auth.Api,
 	userAPI user.Api,
 	courseAPI course.Api,
+	auditAPI audit.Api,
+	deadLetterAPI deadletter.Api,
+	telegramAPI telegram.Api,
+	webhookAPI webhook.Api,
+	preferenceAPI preference.Api,
+	templateAPI template.Api,
+	notificationAPI notification.Api,
+	inboxAPI inbox.Api,
+	mailSinkAPI mailsink.Api,
 	roleManager rbac.RoleManager,
+	cache redis.Cache,
+	log *zap.Logger,
 ) {
 	secretKey := cfg.Auth.SecretKey
+	baseRouteGroup.Use(auth.Identify(secretKey))
+
+	idempotent := idempotency.New(cache, cfg.HTTPServer.IdempotencyTTL, log)
+
 	auth.InitRoutes(baseRouteGroup, authAPI, secretKey)
-	user.InitRoutes(baseRouteGroup, userAPI, roleManager, secretKey)
-	course.InitRoutes(baseRouteGroup, courseAPI, roleManager, secretKey)
+	user.InitRoutes(baseRouteGroup, userAPI, roleManager, secretKey, idempotent)
+	course.InitRoutes(baseRouteGroup, courseAPI, roleManager, secretKey, idempotent)
+	audit.InitRoutes(baseRouteGroup, auditAPI, roleManager, secretKey)
+	deadletter.InitRoutes(baseRouteGroup, deadLetterAPI, roleManager, secretKey)
+	telegram.InitRoutes(baseRouteGroup, telegramAPI, secretKey)
+	webhook.InitRoutes(baseRouteGroup, webhookAPI, roleManager, secretKey)
+	preference.InitRoutes(baseRouteGroup, preferenceAPI, secretKey)
+	template.InitRoutes(baseRouteGroup, templateAPI, roleManager, secretKey)
+	notification.InitRoutes(baseRouteGroup, notificationAPI, roleManager, secretKey)
+	inbox.InitRoutes(baseRouteGroup, inboxAPI, secretKey)
+	debug.InitRoutes(baseRouteGroup, roleManager, secretKey)
+	// the captured emails are served only when the mail sink ui is enabled
+	if mailSinkAPI != nil {
+		mailsink.InitRoutes(baseRouteGroup, mailSinkAPI)
+	}
 }
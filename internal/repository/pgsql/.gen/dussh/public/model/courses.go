@@ -7,8 +7,15 @@
 
 package model
 
+import (
+	"time"
+)
+
 type Courses struct {
 	CourseID                int32 `sql:"primary_key"`
 	CourseName              string
 	MonthlySubscriptionCost *float64
+	Status                  string
+	ArchivedAt              *time.Time
+	Version                 int32
 }
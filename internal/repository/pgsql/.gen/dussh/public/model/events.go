@@ -19,4 +19,5 @@ type Events struct {
 	PeriodFreq       int32
 	PeriodType       string
 	CourseID         int32 `sql:"primary_key"`
+	Version          int32
 }
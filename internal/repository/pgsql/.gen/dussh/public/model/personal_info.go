@@ -7,13 +7,19 @@
 
 package model
 
+import (
+	"time"
+)
+
 type PersonalInfo struct {
-	PersonalInfoID int32 `sql:"primary_key"`
-	CredsID        int32
-	Name           string
-	MiddleName     *string
-	Surname        string
-	Email          string
-	RolesID        int32
-	Phone          *string
+	PersonalInfoID  int32 `sql:"primary_key"`
+	CredsID         int32
+	Name            string
+	MiddleName      *string
+	Surname         string
+	Email           string
+	RolesID         int32
+	Phone           *string
+	Version         int32
+	PhoneVerifiedAt *time.Time
 }
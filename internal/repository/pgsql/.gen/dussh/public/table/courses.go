@@ -20,6 +20,9 @@ type coursesTable struct {
 	CourseID                postgres.ColumnInteger
 	CourseName              postgres.ColumnString
 	MonthlySubscriptionCost postgres.ColumnFloat
+	Status                  postgres.ColumnString
+	ArchivedAt              postgres.ColumnTimestamp
+	Version                 postgres.ColumnInteger
 
 	AllColumns     postgres.ColumnList
 	MutableColumns postgres.ColumnList
@@ -63,8 +66,11 @@ func newCoursesTableImpl(schemaName, tableName, alias string) coursesTable {
 		CourseIDColumn                = postgres.IntegerColumn("course_id")
 		CourseNameColumn              = postgres.StringColumn("course_name")
 		MonthlySubscriptionCostColumn = postgres.FloatColumn("monthly_subscription_cost")
-		allColumns                    = postgres.ColumnList{CourseIDColumn, CourseNameColumn, MonthlySubscriptionCostColumn}
-		mutableColumns                = postgres.ColumnList{CourseNameColumn, MonthlySubscriptionCostColumn}
+		StatusColumn                  = postgres.StringColumn("status")
+		ArchivedAtColumn              = postgres.TimestampColumn("archived_at")
+		VersionColumn                 = postgres.IntegerColumn("version")
+		allColumns                    = postgres.ColumnList{CourseIDColumn, CourseNameColumn, MonthlySubscriptionCostColumn, StatusColumn, ArchivedAtColumn, VersionColumn}
+		mutableColumns                = postgres.ColumnList{CourseNameColumn, MonthlySubscriptionCostColumn, StatusColumn, ArchivedAtColumn, VersionColumn}
 	)
 
 	return coursesTable{
@@ -74,6 +80,9 @@ func newCoursesTableImpl(schemaName, tableName, alias string) coursesTable {
 		CourseID:                CourseIDColumn,
 		CourseName:              CourseNameColumn,
 		MonthlySubscriptionCost: MonthlySubscriptionCostColumn,
+		Status:                  StatusColumn,
+		ArchivedAt:              ArchivedAtColumn,
+		Version:                 VersionColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
@@ -24,6 +24,7 @@ type eventsTable struct {
 	PeriodFreq       postgres.ColumnInteger
 	PeriodType       postgres.ColumnString
 	CourseID         postgres.ColumnInteger
+	Version          postgres.ColumnInteger
 
 	AllColumns     postgres.ColumnList
 	MutableColumns postgres.ColumnList
@@ -71,8 +72,9 @@ func newEventsTableImpl(schemaName, tableName, alias string) eventsTable {
 		PeriodFreqColumn       = postgres.IntegerColumn("period_freq")
 		PeriodTypeColumn       = postgres.StringColumn("period_type")
 		CourseIDColumn         = postgres.IntegerColumn("course_id")
-		allColumns             = postgres.ColumnList{EventIDColumn, EventDescriptionColumn, StartDateColumn, RecurrentCountColumn, PeriodFreqColumn, PeriodTypeColumn, CourseIDColumn}
-		mutableColumns         = postgres.ColumnList{EventDescriptionColumn, StartDateColumn, RecurrentCountColumn, PeriodFreqColumn, PeriodTypeColumn}
+		VersionColumn          = postgres.IntegerColumn("version")
+		allColumns             = postgres.ColumnList{EventIDColumn, EventDescriptionColumn, StartDateColumn, RecurrentCountColumn, PeriodFreqColumn, PeriodTypeColumn, CourseIDColumn, VersionColumn}
+		mutableColumns         = postgres.ColumnList{EventDescriptionColumn, StartDateColumn, RecurrentCountColumn, PeriodFreqColumn, PeriodTypeColumn, VersionColumn}
 	)
 
 	return eventsTable{
@@ -86,6 +88,7 @@ func newEventsTableImpl(schemaName, tableName, alias string) eventsTable {
 		PeriodFreq:       PeriodFreqColumn,
 		PeriodType:       PeriodTypeColumn,
 		CourseID:         CourseIDColumn,
+		Version:          VersionColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
@@ -17,14 +17,16 @@ type personalInfoTable struct {
 	postgres.Table
 
 	// Columns
-	PersonalInfoID postgres.ColumnInteger
-	CredsID        postgres.ColumnInteger
-	Name           postgres.ColumnString
-	MiddleName     postgres.ColumnString
-	Surname        postgres.ColumnString
-	Email          postgres.ColumnString
-	RolesID        postgres.ColumnInteger
-	Phone          postgres.ColumnString
+	PersonalInfoID  postgres.ColumnInteger
+	CredsID         postgres.ColumnInteger
+	Name            postgres.ColumnString
+	MiddleName      postgres.ColumnString
+	Surname         postgres.ColumnString
+	Email           postgres.ColumnString
+	RolesID         postgres.ColumnInteger
+	Phone           postgres.ColumnString
+	Version         postgres.ColumnInteger
+	PhoneVerifiedAt postgres.ColumnTimestamp
 
 	AllColumns     postgres.ColumnList
 	MutableColumns postgres.ColumnList
@@ -65,30 +67,34 @@ func newPersonalInfoTable(schemaName, tableName, alias string) *PersonalInfoTabl
 
 func newPersonalInfoTableImpl(schemaName, tableName, alias string) personalInfoTable {
 	var (
-		PersonalInfoIDColumn = postgres.IntegerColumn("personal_info_id")
-		CredsIDColumn        = postgres.IntegerColumn("creds_id")
-		NameColumn           = postgres.StringColumn("name")
-		MiddleNameColumn     = postgres.StringColumn("middle_name")
-		SurnameColumn        = postgres.StringColumn("surname")
-		EmailColumn          = postgres.StringColumn("email")
-		RolesIDColumn        = postgres.IntegerColumn("roles_id")
-		PhoneColumn          = postgres.StringColumn("phone")
-		allColumns           = postgres.ColumnList{PersonalInfoIDColumn, CredsIDColumn, NameColumn, MiddleNameColumn, SurnameColumn, EmailColumn, RolesIDColumn, PhoneColumn}
-		mutableColumns       = postgres.ColumnList{CredsIDColumn, NameColumn, MiddleNameColumn, SurnameColumn, EmailColumn, RolesIDColumn, PhoneColumn}
+		PersonalInfoIDColumn  = postgres.IntegerColumn("personal_info_id")
+		CredsIDColumn         = postgres.IntegerColumn("creds_id")
+		NameColumn            = postgres.StringColumn("name")
+		MiddleNameColumn      = postgres.StringColumn("middle_name")
+		SurnameColumn         = postgres.StringColumn("surname")
+		EmailColumn           = postgres.StringColumn("email")
+		RolesIDColumn         = postgres.IntegerColumn("roles_id")
+		PhoneColumn           = postgres.StringColumn("phone")
+		VersionColumn         = postgres.IntegerColumn("version")
+		PhoneVerifiedAtColumn = postgres.TimestampColumn("phone_verified_at")
+		allColumns            = postgres.ColumnList{PersonalInfoIDColumn, CredsIDColumn, NameColumn, MiddleNameColumn, SurnameColumn, EmailColumn, RolesIDColumn, PhoneColumn, VersionColumn, PhoneVerifiedAtColumn}
+		mutableColumns        = postgres.ColumnList{CredsIDColumn, NameColumn, MiddleNameColumn, SurnameColumn, EmailColumn, RolesIDColumn, PhoneColumn, VersionColumn, PhoneVerifiedAtColumn}
 	)
 
 	return personalInfoTable{
 		Table: postgres.NewTable(schemaName, tableName, alias, allColumns...),
 
 		//Columns
-		PersonalInfoID: PersonalInfoIDColumn,
-		CredsID:        CredsIDColumn,
-		Name:           NameColumn,
-		MiddleName:     MiddleNameColumn,
-		Surname:        SurnameColumn,
-		Email:          EmailColumn,
-		RolesID:        RolesIDColumn,
-		Phone:          PhoneColumn,
+		PersonalInfoID:  PersonalInfoIDColumn,
+		CredsID:         CredsIDColumn,
+		Name:            NameColumn,
+		MiddleName:      MiddleNameColumn,
+		Surname:         SurnameColumn,
+		Email:           EmailColumn,
+		RolesID:         RolesIDColumn,
+		Phone:           PhoneColumn,
+		Version:         VersionColumn,
+		PhoneVerifiedAt: PhoneVerifiedAtColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
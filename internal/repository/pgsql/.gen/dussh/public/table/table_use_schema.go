@@ -12,6 +12,7 @@ package table
 func UseSchema(schema string) {
 	AcademicDegrees = AcademicDegrees.FromSchema(schema)
 	AcademicTitles = AcademicTitles.FromSchema(schema)
+	AuditLog = AuditLog.FromSchema(schema)
 	Courses = Courses.FromSchema(schema)
 	Creds = Creds.FromSchema(schema)
 	Diplomas = Diplomas.FromSchema(schema)
@@ -19,7 +20,21 @@ func UseSchema(schema string) {
 	Employees = Employees.FromSchema(schema)
 	Enrollments = Enrollments.FromSchema(schema)
 	Events = Events.FromSchema(schema)
+	InboxNotifications = InboxNotifications.FromSchema(schema)
+	Invoices = Invoices.FromSchema(schema)
+	Jobs = Jobs.FromSchema(schema)
+	MedicalCertificates = MedicalCertificates.FromSchema(schema)
+	NotificationDeliveries = NotificationDeliveries.FromSchema(schema)
+	NotificationDigestItems = NotificationDigestItems.FromSchema(schema)
+	NotificationPreferences = NotificationPreferences.FromSchema(schema)
+	NotificationTemplates = NotificationTemplates.FromSchema(schema)
+	Outbox = Outbox.FromSchema(schema)
 	PersonalInfo = PersonalInfo.FromSchema(schema)
 	Positions = Positions.FromSchema(schema)
 	Roles = Roles.FromSchema(schema)
+	SessionReminders = SessionReminders.FromSchema(schema)
+	TelegramLinks = TelegramLinks.FromSchema(schema)
+	WebhookDeliveries = WebhookDeliveries.FromSchema(schema)
+	WebhookDeliveryAttempts = WebhookDeliveryAttempts.FromSchema(schema)
+	WebhookSubscriptions = WebhookSubscriptions.FromSchema(schema)
 }
@@ -0,0 +1,75 @@
+package pgsql
+
+import (
+	"context"
+	"dussh/internal/domain/models"
+	"dussh/internal/repository/pgsql/.gen/dussh/public/table"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/go-jet/jet/v2/postgres"
+	"go.uber.org/zap"
+)
+
+func (r *Repository) SaveAuditEntry(ctx context.Context, e *models.AuditEntry) error {
+	r.log.Debug("saving audit entry")
+
+	auditLog := table.AuditLog
+	query, args := auditLog.INSERT(
+		auditLog.ActorID, auditLog.ActorEmail, auditLog.Action,
+		auditLog.EntityType, auditLog.EntityID,
+		auditLog.Before, auditLog.After, auditLog.Diff,
+		auditLog.RequestID,
+	).
+		VALUES(
+			e.ActorID, e.ActorEmail, string(e.Action),
+			e.EntityType, e.EntityID,
+			e.Before, e.After, e.Diff,
+			e.RequestID,
+		).Sql()
+
+	if _, err := r.db.Exec(ctx, query, args...); err != nil {
+		r.log.Error("failed to save audit entry", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) GetAuditEntries(ctx context.Context, f *models.AuditFilter) ([]*models.AuditEntry, error) {
+	r.log.Debug("getting audit entries")
+
+	var (
+		entries    []*models.AuditEntry
+		auditLog   = table.AuditLog
+		conditions = []postgres.BoolExpression{postgres.Bool(true)}
+	)
+
+	if f.ActorID != nil {
+		conditions = append(conditions, auditLog.ActorID.EQ(postgres.Int(*f.ActorID)))
+	}
+	if f.EntityType != "" {
+		conditions = append(conditions, auditLog.EntityType.EQ(postgres.String(f.EntityType)))
+	}
+	if f.EntityID != nil {
+		conditions = append(conditions, auditLog.EntityID.EQ(postgres.Int(*f.EntityID)))
+	}
+	if f.From != nil {
+		conditions = append(conditions, auditLog.CreatedAt.GT_EQ(postgres.TimestampT(*f.From)))
+	}
+	if f.To != nil {
+		conditions = append(conditions, auditLog.CreatedAt.LT(postgres.TimestampT(*f.To)))
+	}
+
+	query, args := auditLog.SELECT(auditLog.AllColumns).
+		WHERE(postgres.AND(conditions...)).
+		ORDER_BY(auditLog.CreatedAt.DESC(), auditLog.ID.DESC()).
+		LIMIT(f.Limit).
+		OFFSET(f.Offset).
+		Sql()
+
+	if err := pgxscan.Select(ctx, r.db, &entries, query, args...); err != nil {
+		r.log.Debug("failed to get audit entries", zap.Error(err))
+		return nil, err
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,114 @@
+package pgsql
+
+import (
+	"context"
+	"dussh/internal/domain/models"
+	"dussh/internal/repository"
+	"dussh/internal/repository/pgsql/.gen/dussh/public/table"
+	"errors"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+	"time"
+)
+
+// SaveMedicalCertificate stores the certificate of the user, it fails with
+// repository.ErrUserNotFound if there is no such user.
+func (r *Repository) SaveMedicalCertificate(ctx context.Context, c *models.MedicalCertificate) (int64, error) {
+	r.log.Debug("saving medical certificate")
+
+	var (
+		id           int64
+		certificates = table.MedicalCertificates
+		personalInfo = table.PersonalInfo
+	)
+
+	query, args := certificates.INSERT(certificates.PersonalInfoID, certificates.ExpiresAt).
+		QUERY(
+			personalInfo.SELECT(
+				personalInfo.PersonalInfoID,
+				postgres.TimestampT(c.ExpiresAt),
+			).WHERE(personalInfo.PersonalInfoID.EQ(postgres.Int(c.UserID))),
+		).
+		RETURNING(certificates.ID).Sql()
+
+	if err := r.db.QueryRow(ctx, query, args...).Scan(&id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, repository.ErrUserNotFound
+		}
+		r.log.Error("failed to save medical certificate", zap.Error(err))
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (r *Repository) GetMedicalCertificates(ctx context.Context, userID int64) ([]*models.MedicalCertificate, error) {
+	r.log.Debug("getting medical certificates")
+
+	var (
+		result       []*models.MedicalCertificate
+		certificates = table.MedicalCertificates
+	)
+
+	query, args := certificates.SELECT(certificates.AllColumns).
+		WHERE(certificates.PersonalInfoID.EQ(postgres.Int(userID))).
+		ORDER_BY(certificates.ExpiresAt.DESC()).Sql()
+
+	if err := pgxscan.Select(ctx, r.db, &result, query, args...); err != nil {
+		r.log.Error("failed to get medical certificates", zap.Error(err))
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// QueueExpiringMedicalCertificates enqueues a notification for every valid
+// certificate that expires before the given time and was not announced yet,
+// and returns how many were enqueued. The notifications are published
+// through the outbox.
+func (r *Repository) QueueExpiringMedicalCertificates(ctx context.Context, now, before time.Time) (int, error) {
+	r.log.Debug("queueing expiring medical certificates")
+
+	var (
+		expiring     []*models.MedicalCertificate
+		certificates = table.MedicalCertificates
+	)
+
+	pending := certificates.SELECT(certificates.ID).
+		WHERE(postgres.AND(
+			certificates.NotifiedAt.IS_NULL(),
+			certificates.ExpiresAt.GT(postgres.TimestampT(now)),
+			certificates.ExpiresAt.LT_EQ(postgres.TimestampT(before)),
+		)).
+		FOR(postgres.UPDATE().SKIP_LOCKED())
+
+	query, args := certificates.UPDATE(certificates.NotifiedAt).
+		SET(postgres.LOCALTIMESTAMP()).
+		WHERE(certificates.ID.IN(pending)).
+		RETURNING(certificates.AllColumns).Sql()
+
+	if err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		if err := pgxscan.Select(ctx, tx, &expiring, query, args...); err != nil {
+			return err
+		}
+
+		for _, c := range expiring {
+			if err := r.saveOutboxMessage(ctx, tx, models.MedicalCertificateExpiringEvent{
+				UserID:        c.UserID,
+				CertificateID: c.ID,
+				ExpiresAt:     c.ExpiresAt,
+			}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		r.log.Error("failed to queue expiring medical certificates", zap.Error(err))
+		return 0, err
+	}
+
+	return len(expiring), nil
+}
@@ -0,0 +1,127 @@
+package pgsql
+
+import (
+	"context"
+	"dussh/internal/domain/models"
+	"dussh/internal/repository/pgsql/.gen/dussh/public/table"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+	"time"
+)
+
+// SaveDigestItem collects the notification for the next digest of its mode.
+func (r *Repository) SaveDigestItem(ctx context.Context, item *models.DigestItem) error {
+	r.log.Debug("saving notification digest item")
+
+	items := table.NotificationDigestItems
+	query, args := items.INSERT(
+		items.PersonalInfoID,
+		items.Mode,
+		items.Category,
+		items.EventType,
+		items.Subject,
+		items.Body,
+	).
+		VALUES(
+			item.UserID,
+			string(item.Mode),
+			string(item.Category),
+			item.EventType,
+			item.Subject,
+			item.Body,
+		).Sql()
+
+	if _, err := r.db.Exec(ctx, query, args...); err != nil {
+		r.log.Error("failed to save notification digest item", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// QueueNotificationDigests queues the items of the mode collected before the
+// time and publishes a digest of them per user through the outbox. It returns
+// the number of digests, an item is queued once.
+func (r *Repository) QueueNotificationDigests(
+	ctx context.Context,
+	mode models.DeliveryMode,
+	before time.Time,
+) (int, error) {
+	r.log.Debug("queueing notification digests")
+
+	var digests []models.NotificationDigestEvent
+
+	items := table.NotificationDigestItems
+	if err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		query, args := items.UPDATE(items.QueuedAt).
+			SET(postgres.LOCALTIMESTAMP()).
+			WHERE(
+				items.Mode.EQ(postgres.String(string(mode))).
+					AND(items.QueuedAt.IS_NULL()).
+					AND(items.CreatedAt.LT(postgres.TimestampT(before))),
+			).
+			RETURNING(items.ID, items.PersonalInfoID).Sql()
+
+		var queued []*models.DigestItem
+		if err := pgxscan.Select(ctx, tx, &queued, query, args...); err != nil {
+			return err
+		}
+
+		byUser := make(map[int64]int)
+		for _, item := range queued {
+			i, ok := byUser[item.UserID]
+			if !ok {
+				i = len(digests)
+				byUser[item.UserID] = i
+				digests = append(digests, models.NotificationDigestEvent{UserID: item.UserID, Mode: mode})
+			}
+			digests[i].ItemIDs = append(digests[i].ItemIDs, item.ID)
+		}
+
+		for _, digest := range digests {
+			if err := r.saveOutboxMessage(ctx, tx, digest); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		r.log.Error("failed to queue notification digests", zap.Error(err))
+		return 0, err
+	}
+
+	return len(digests), nil
+}
+
+// GetDigestItems returns the items of the user in the order they were
+// collected.
+func (r *Repository) GetDigestItems(ctx context.Context, userID int64, ids []int64) ([]*models.DigestItem, error) {
+	r.log.Debug("getting notification digest items")
+
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var (
+		result []*models.DigestItem
+		items  = table.NotificationDigestItems
+	)
+
+	itemIDs := make([]postgres.Expression, 0, len(ids))
+	for _, id := range ids {
+		itemIDs = append(itemIDs, postgres.Int(id))
+	}
+
+	query, args := items.SELECT(items.AllColumns).
+		WHERE(items.PersonalInfoID.EQ(postgres.Int(userID)).AND(items.ID.IN(itemIDs...))).
+		ORDER_BY(items.ID).Sql()
+
+	if err := pgxscan.Select(ctx, r.db, &result, query, args...); err != nil {
+		r.log.Error("failed to get notification digest items", zap.Error(err))
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,125 @@
+package pgsql
+
+import (
+	"context"
+	"dussh/internal/domain/models"
+	"dussh/internal/repository"
+	"dussh/internal/repository/pgsql/.gen/dussh/public/table"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/go-jet/jet/v2/postgres"
+	"go.uber.org/zap"
+)
+
+func (r *Repository) CreateInboxItem(ctx context.Context, item *models.InboxItem) (*models.InboxItem, error) {
+	r.log.Debug("creating inbox notification")
+
+	var (
+		created models.InboxItem
+		inbox   = table.InboxNotifications
+	)
+
+	query, args := inbox.INSERT(inbox.PersonalInfoID, inbox.Subject, inbox.Body, inbox.ContentType).
+		VALUES(item.UserID, item.Subject, item.Body, item.ContentType).
+		RETURNING(inbox.AllColumns).Sql()
+
+	if err := pgxscan.Get(ctx, r.db, &created, query, args...); err != nil {
+		r.log.Error("failed to create inbox notification", zap.Error(err))
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// GetInboxItems returns a page of the inbox of the user, newest first.
+func (r *Repository) GetInboxItems(
+	ctx context.Context,
+	userID int64,
+	filter models.InboxFilter,
+) ([]*models.InboxItem, error) {
+	r.log.Debug("getting inbox notifications")
+
+	var (
+		items []*models.InboxItem
+		inbox = table.InboxNotifications
+	)
+
+	condition := inbox.PersonalInfoID.EQ(postgres.Int(userID))
+	if filter.UnreadOnly {
+		condition = condition.AND(inbox.ReadAt.IS_NULL())
+	}
+	if filter.BeforeID > 0 {
+		condition = condition.AND(inbox.ID.LT(postgres.Int(filter.BeforeID)))
+	}
+
+	query, args := inbox.SELECT(inbox.AllColumns).
+		WHERE(condition).
+		ORDER_BY(inbox.ID.DESC()).
+		LIMIT(filter.Limit).Sql()
+
+	if err := pgxscan.Select(ctx, r.db, &items, query, args...); err != nil {
+		r.log.Error("failed to get inbox notifications", zap.Error(err))
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (r *Repository) CountUnreadInboxItems(ctx context.Context, userID int64) (int64, error) {
+	r.log.Debug("counting unread inbox notifications")
+
+	var (
+		count int64
+		inbox = table.InboxNotifications
+	)
+
+	query, args := inbox.SELECT(postgres.COUNT(postgres.STAR)).
+		WHERE(inbox.PersonalInfoID.EQ(postgres.Int(userID)).AND(inbox.ReadAt.IS_NULL())).Sql()
+
+	if err := r.db.QueryRow(ctx, query, args...).Scan(&count); err != nil {
+		r.log.Error("failed to count unread inbox notifications", zap.Error(err))
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// MarkInboxItemRead marks the notification of the user read, marking it again
+// keeps the time it was first read.
+func (r *Repository) MarkInboxItemRead(ctx context.Context, userID, id int64) error {
+	r.log.Debug("marking inbox notification read")
+
+	inbox := table.InboxNotifications
+	query, args := inbox.UPDATE(inbox.ReadAt).
+		SET(postgres.COALESCE(inbox.ReadAt, postgres.LOCALTIMESTAMP())).
+		WHERE(inbox.ID.EQ(postgres.Int(id)).AND(inbox.PersonalInfoID.EQ(postgres.Int(userID)))).Sql()
+
+	tag, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		r.log.Error("failed to mark inbox notification read", zap.Error(err))
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrInboxItemNotFound
+	}
+
+	return nil
+}
+
+// MarkAllInboxItemsRead marks every unread notification of the user read and
+// returns how many there were.
+func (r *Repository) MarkAllInboxItemsRead(ctx context.Context, userID int64) (int64, error) {
+	r.log.Debug("marking all inbox notifications read")
+
+	inbox := table.InboxNotifications
+	query, args := inbox.UPDATE(inbox.ReadAt).
+		SET(postgres.LOCALTIMESTAMP()).
+		WHERE(inbox.PersonalInfoID.EQ(postgres.Int(userID)).AND(inbox.ReadAt.IS_NULL())).Sql()
+
+	tag, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		r.log.Error("failed to mark all inbox notifications read", zap.Error(err))
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,111 @@
+package pgsql
+
+import (
+	"context"
+	"dussh/internal/domain/models"
+	"dussh/internal/repository"
+	"dussh/internal/repository/pgsql/.gen/dussh/public/table"
+	"errors"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// SaveInvoice issues the invoice to a user enrolled in the course, it fails
+// with repository.ErrEnrollmentNotFound otherwise.
+func (r *Repository) SaveInvoice(ctx context.Context, inv *models.Invoice) (int64, error) {
+	r.log.Debug("saving invoice")
+
+	var (
+		id          int64
+		invoices    = table.Invoices
+		enrollments = table.Enrollments
+	)
+
+	query, args := invoices.INSERT(invoices.CourseID, invoices.PersonalInfoID, invoices.Amount).
+		QUERY(
+			enrollments.SELECT(
+				enrollments.CourseID,
+				enrollments.PersonalInfoID,
+				postgres.CAST(postgres.Float(inv.Amount)).AS_NUMERIC(),
+			).WHERE(postgres.AND(
+				enrollments.CourseID.EQ(postgres.Int(inv.CourseID)),
+				enrollments.PersonalInfoID.EQ(postgres.Int(inv.UserID)),
+			)),
+		).
+		RETURNING(invoices.ID).Sql()
+
+	if err := r.db.QueryRow(ctx, query, args...).Scan(&id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, repository.ErrEnrollmentNotFound
+		}
+		r.log.Error("failed to save invoice", zap.Error(err))
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// PayInvoice marks the unpaid invoice of the course as paid.
+func (r *Repository) PayInvoice(ctx context.Context, courseID, id int64) error {
+	r.log.Debug("paying invoice")
+
+	invoices := table.Invoices
+	query, args := invoices.UPDATE(invoices.PaidAt).
+		SET(postgres.LOCALTIMESTAMP()).
+		WHERE(postgres.AND(
+			invoices.ID.EQ(postgres.Int(id)),
+			invoices.CourseID.EQ(postgres.Int(courseID)),
+			invoices.PaidAt.IS_NULL(),
+		)).Sql()
+
+	tag, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		r.log.Error("failed to pay invoice", zap.Error(err))
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrInvoiceNotPayable
+	}
+
+	return nil
+}
+
+func (r *Repository) GetInvoices(ctx context.Context, courseID int64) ([]*models.Invoice, error) {
+	r.log.Debug("getting course invoices")
+
+	var (
+		result   []*models.Invoice
+		invoices = table.Invoices
+	)
+
+	query, args := invoices.SELECT(invoices.AllColumns).
+		WHERE(invoices.CourseID.EQ(postgres.Int(courseID))).
+		ORDER_BY(invoices.ID).Sql()
+
+	if err := pgxscan.Select(ctx, r.db, &result, query, args...); err != nil {
+		r.log.Error("failed to get course invoices", zap.Error(err))
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// countUnpaidInvoices counts the invoices of the course that are not paid yet.
+func countUnpaidInvoices(ctx context.Context, tx pgx.Tx, courseID int64) (int, error) {
+	invoices := table.Invoices
+
+	query, args := invoices.SELECT(postgres.COUNT(invoices.ID)).
+		WHERE(
+			postgres.AND(
+				invoices.CourseID.EQ(postgres.Int(courseID)),
+				invoices.PaidAt.IS_NULL(),
+			),
+		).
+		Sql()
+
+	var count int
+	err := tx.QueryRow(ctx, query, args...).Scan(&count)
+	return count, err
+}
@@ -0,0 +1,279 @@
+package pgsql
+
+import (
+	"context"
+	domainerrors "dussh/internal/domain/errors"
+	"dussh/internal/domain/models"
+	"dussh/internal/repository/pgsql/.gen/dussh/public/table"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+	"time"
+)
+
+// jobsChannel is the LISTEN/NOTIFY channel, the payload is the queue name.
+const jobsChannel = "jobs"
+
+// EnqueueJobs stores a copy of the job for every queue and notifies listeners
+// once the transaction commits.
+func (r *Repository) EnqueueJobs(ctx context.Context, queues []string, job *models.Job) error {
+	r.log.Debug("enqueueing jobs")
+
+	jobs := table.Jobs
+	stmt := jobs.INSERT(
+		jobs.Queue, jobs.MessageID, jobs.RoutingKey, jobs.Type, jobs.CorrelationID,
+		jobs.Producer, jobs.ContentType, jobs.Headers, jobs.Body, jobs.PublishedAt,
+	)
+	for _, queue := range queues {
+		stmt = stmt.VALUES(
+			queue, job.MessageID, job.RoutingKey, job.Type, job.CorrelationID,
+			job.Producer, job.ContentType, job.Headers, job.Body, job.PublishedAt,
+		)
+	}
+
+	return withTx(ctx, r.db, func(tx pgx.Tx) error {
+		query, args := stmt.Sql()
+		if _, err := tx.Exec(ctx, query, args...); err != nil {
+			r.log.Error("failed to enqueue jobs", zap.Error(err))
+			return err
+		}
+
+		for _, queue := range queues {
+			if _, err := tx.Exec(ctx, "SELECT pg_notify($1, $2)", jobsChannel, queue); err != nil {
+				r.log.Error("failed to notify about jobs", zap.Error(err))
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// ClaimJob returns the next ready job of the queue, or nil if there is none,
+// and hides it from other consumers for the visibility timeout. A job that is
+// not settled before the timeout expires is delivered again.
+func (r *Repository) ClaimJob(ctx context.Context, queue string, visibility time.Duration) (*models.Job, error) {
+	var (
+		claimed []*models.Job
+		jobs    = table.Jobs
+	)
+
+	ready := jobs.SELECT(jobs.ID).
+		WHERE(postgres.AND(
+			jobs.Queue.EQ(postgres.String(queue)),
+			jobs.DeadLetteredAt.IS_NULL(),
+			jobs.RunAt.LT_EQ(postgres.LOCALTIMESTAMP()),
+		)).
+		ORDER_BY(jobs.RunAt, jobs.ID).
+		LIMIT(1).
+		FOR(postgres.UPDATE().SKIP_LOCKED())
+
+	query, args := jobs.UPDATE(jobs.Attempts, jobs.RunAt).
+		SET(
+			jobs.Attempts.ADD(postgres.Int(1)),
+			postgres.LOCALTIMESTAMP().ADD(postgres.INTERVALd(visibility)),
+		).
+		WHERE(jobs.ID.IN(ready)).
+		RETURNING(jobs.AllColumns).Sql()
+
+	if err := pgxscan.Select(ctx, r.db, &claimed, query, args...); err != nil {
+		r.log.Error("failed to claim job", zap.Error(err))
+		return nil, err
+	}
+
+	if len(claimed) == 0 {
+		return nil, nil
+	}
+	return claimed[0], nil
+}
+
+// DeleteJob removes a processed job. It returns ErrJobLeaseLost if the job
+// was delivered again or replayed after the given delivery.
+func (r *Repository) DeleteJob(ctx context.Context, job *models.Job) error {
+	r.log.Debug("deleting job")
+
+	jobs := table.Jobs
+	query, args := jobs.DELETE().
+		WHERE(jobDelivery(job)).Sql()
+
+	return r.execJob(ctx, "failed to delete job", query, args)
+}
+
+// RescheduleJob makes the job ready again after runIn. A non-nil reason
+// counts as a failed processing attempt.
+func (r *Repository) RescheduleJob(
+	ctx context.Context,
+	job *models.Job,
+	runIn time.Duration,
+	reason *string,
+) error {
+	r.log.Debug("rescheduling job")
+
+	var (
+		jobs       = table.Jobs
+		retryCount = postgres.IntegerExpression(jobs.RetryCount)
+		lastError  = postgres.StringExpression(jobs.LastError)
+	)
+	if reason != nil {
+		retryCount = jobs.RetryCount.ADD(postgres.Int(1))
+		lastError = postgres.String(*reason)
+	}
+
+	query, args := jobs.UPDATE(jobs.RunAt, jobs.RetryCount, jobs.LastError).
+		SET(
+			postgres.LOCALTIMESTAMP().ADD(postgres.INTERVALd(runIn)),
+			retryCount,
+			lastError,
+		).
+		WHERE(jobDelivery(job)).Sql()
+
+	return r.execJob(ctx, "failed to reschedule job", query, args)
+}
+
+// DeadLetterJob moves the job to the dead letters, it is not delivered until replayed.
+func (r *Repository) DeadLetterJob(ctx context.Context, job *models.Job, reason string) error {
+	r.log.Debug("dead-lettering job")
+
+	jobs := table.Jobs
+	query, args := jobs.UPDATE(jobs.DeadLetteredAt, jobs.LastError).
+		SET(postgres.LOCALTIMESTAMP(), postgres.String(reason)).
+		WHERE(jobDelivery(job)).Sql()
+
+	return r.execJob(ctx, "failed to dead-letter job", query, args)
+}
+
+func (r *Repository) ListDeadLetteredJobs(ctx context.Context, limit int64) ([]*models.Job, error) {
+	r.log.Debug("listing dead-lettered jobs")
+
+	var (
+		dead []*models.Job
+		jobs = table.Jobs
+	)
+
+	query, args := jobs.SELECT(jobs.AllColumns).
+		WHERE(jobs.DeadLetteredAt.IS_NOT_NULL()).
+		ORDER_BY(jobs.DeadLetteredAt, jobs.ID).
+		LIMIT(limit).Sql()
+
+	if err := pgxscan.Select(ctx, r.db, &dead, query, args...); err != nil {
+		r.log.Error("failed to list dead-lettered jobs", zap.Error(err))
+		return nil, err
+	}
+
+	return dead, nil
+}
+
+// ReplayDeadLetteredJob returns the dead-lettered job to its queue with reset
+// attempts and retry counter, the replay generation is bumped so the old
+// delivery can no longer settle it.
+func (r *Repository) ReplayDeadLetteredJob(ctx context.Context, messageID string) error {
+	r.log.Debug("replaying dead-lettered job")
+
+	jobs := table.Jobs
+	query, args := jobs.UPDATE(jobs.DeadLetteredAt, jobs.Attempts, jobs.RetryCount, jobs.Replays, jobs.RunAt).
+		SET(
+			postgres.NULL,
+			postgres.Int(0),
+			postgres.Int(0),
+			jobs.Replays.ADD(postgres.Int(1)),
+			postgres.LOCALTIMESTAMP(),
+		).
+		WHERE(jobs.ID.IN(deadLetteredJob(messageID))).
+		RETURNING(jobs.Queue).Sql()
+
+	return withTx(ctx, r.db, func(tx pgx.Tx) error {
+		var queue string
+		if err := tx.QueryRow(ctx, query, args...).Scan(&queue); err != nil {
+			if pgxscan.NotFound(err) {
+				return domainerrors.ErrDeadLetterNotFound
+			}
+			r.log.Error("failed to replay dead-lettered job", zap.Error(err))
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, "SELECT pg_notify($1, $2)", jobsChannel, queue); err != nil {
+			r.log.Error("failed to notify about jobs", zap.Error(err))
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (r *Repository) DiscardDeadLetteredJob(ctx context.Context, messageID string) error {
+	r.log.Debug("discarding dead-lettered job")
+
+	jobs := table.Jobs
+	query, args := jobs.DELETE().
+		WHERE(jobs.ID.IN(deadLetteredJob(messageID))).Sql()
+
+	tag, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		r.log.Error("failed to discard dead-lettered job", zap.Error(err))
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domainerrors.ErrDeadLetterNotFound
+	}
+
+	return nil
+}
+
+// ListenJobs calls notify with the queue name whenever jobs are enqueued.
+// It holds a pool connection until ctx is canceled or the connection fails.
+func (r *Repository) ListenJobs(ctx context.Context, notify func(queue string)) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+jobsChannel); err != nil {
+		return err
+	}
+
+	for {
+		n, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		notify(n.Payload)
+	}
+}
+
+func (r *Repository) execJob(ctx context.Context, msg, query string, args []interface{}) error {
+	tag, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		r.log.Error(msg, zap.Error(err))
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domainerrors.ErrJobLeaseLost
+	}
+
+	return nil
+}
+
+// jobDelivery matches the job only while the given delivery still holds it.
+func jobDelivery(job *models.Job) postgres.BoolExpression {
+	jobs := table.Jobs
+	return postgres.AND(
+		jobs.ID.EQ(postgres.Int(job.ID)),
+		jobs.Attempts.EQ(postgres.Int(int64(job.Attempts))),
+		jobs.Replays.EQ(postgres.Int(int64(job.Replays))),
+		jobs.DeadLetteredAt.IS_NULL(),
+	)
+}
+
+func deadLetteredJob(messageID string) postgres.SelectStatement {
+	jobs := table.Jobs
+	return jobs.SELECT(jobs.ID).
+		WHERE(postgres.AND(
+			jobs.MessageID.EQ(postgres.String(messageID)),
+			jobs.DeadLetteredAt.IS_NOT_NULL(),
+		)).
+		ORDER_BY(jobs.ID).
+		LIMIT(1).
+		FOR(postgres.UPDATE().SKIP_LOCKED())
+}
@@ -0,0 +1,203 @@
+package pgsql
+
+import (
+	"context"
+	"dussh/internal/domain/models"
+	"dussh/internal/repository"
+	"dussh/internal/repository/pgsql/.gen/dussh/public/table"
+	"encoding/json"
+	"errors"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// CreateNotificationDelivery logs the delivery as queued.
+func (r *Repository) CreateNotificationDelivery(ctx context.Context, d *models.NotificationDelivery) (int64, error) {
+	r.log.Debug("creating notification delivery")
+
+	attachments := d.Attachments
+	if attachments == nil {
+		attachments = []models.NotificationAttachment{}
+	}
+	data, err := json.Marshal(attachments)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		id         int64
+		deliveries = table.NotificationDeliveries
+	)
+
+	query, args := deliveries.INSERT(
+		deliveries.PersonalInfoID,
+		deliveries.EventType,
+		deliveries.Locale,
+		deliveries.TemplateVersion,
+		deliveries.Channel,
+		deliveries.Recipients,
+		deliveries.ContentType,
+		deliveries.Subject,
+		deliveries.Body,
+		deliveries.AltBody,
+		deliveries.Attachments,
+		deliveries.Status,
+	).
+		VALUES(
+			d.UserID,
+			d.EventType,
+			d.Locale,
+			d.TemplateVersion,
+			string(d.Channel),
+			d.Recipients,
+			d.ContentType,
+			d.Subject,
+			d.Body,
+			d.AltBody,
+			json.RawMessage(data),
+			string(models.NotificationQueued),
+		).
+		RETURNING(deliveries.ID).Sql()
+
+	if err := r.db.QueryRow(ctx, query, args...).Scan(&id); err != nil {
+		r.log.Error("failed to create notification delivery", zap.Error(err))
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// RecordNotificationAttempt counts an attempt of the delivery and stores its
+// outcome, the response of the provider is kept for failed attempts.
+func (r *Repository) RecordNotificationAttempt(
+	ctx context.Context,
+	id int64,
+	status models.NotificationStatus,
+	response *string,
+) error {
+	r.log.Debug("recording notification attempt")
+
+	deliveries := table.NotificationDeliveries
+	columns := postgres.ColumnList{
+		deliveries.Status,
+		deliveries.Attempts,
+		deliveries.ProviderResponse,
+		deliveries.UpdatedAt,
+	}
+	values := []any{
+		postgres.String(string(status)),
+		deliveries.Attempts.ADD(postgres.Int(1)),
+		response,
+		postgres.LOCALTIMESTAMP(),
+	}
+	if status == models.NotificationSent {
+		columns = append(columns, deliveries.SentAt)
+		values = append(values, postgres.LOCALTIMESTAMP())
+	}
+
+	query, args := deliveries.UPDATE(columns).
+		SET(values[0], values[1:]...).
+		WHERE(deliveries.ID.EQ(postgres.Int(id))).Sql()
+
+	tag, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		r.log.Error("failed to record notification attempt", zap.Error(err))
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrDeliveryNotFound
+	}
+
+	return nil
+}
+
+// ClaimFailedNotificationDelivery queues the failed delivery again so that
+// only one caller resends it. It returns nil if the delivery is not failed,
+// is a one-time code or doesn't exist.
+func (r *Repository) ClaimFailedNotificationDelivery(ctx context.Context, id int64) (*models.NotificationDelivery, error) {
+	r.log.Debug("claiming failed notification delivery")
+
+	var (
+		claimed    []*models.NotificationDelivery
+		deliveries = table.NotificationDeliveries
+	)
+
+	query, args := deliveries.UPDATE(deliveries.Status, deliveries.UpdatedAt).
+		SET(postgres.String(string(models.NotificationQueued)), postgres.LOCALTIMESTAMP()).
+		WHERE(postgres.AND(
+			deliveries.ID.EQ(postgres.Int(id)),
+			deliveries.Status.EQ(postgres.String(string(models.NotificationFailed))),
+			deliveries.EventType.NOT_EQ(postgres.String(models.EventTypeOTP)),
+		)).
+		RETURNING(deliveries.AllColumns).Sql()
+
+	if err := pgxscan.Select(ctx, r.db, &claimed, query, args...); err != nil {
+		r.log.Error("failed to claim failed notification delivery", zap.Error(err))
+		return nil, err
+	}
+
+	if len(claimed) == 0 {
+		return nil, nil
+	}
+	return claimed[0], nil
+}
+
+// GetNotificationDeliveries returns the deliveries matching the filter, newest first.
+func (r *Repository) GetNotificationDeliveries(
+	ctx context.Context,
+	filter models.NotificationDeliveryFilter,
+) ([]*models.NotificationDelivery, error) {
+	r.log.Debug("getting notification deliveries")
+
+	var (
+		result     []*models.NotificationDelivery
+		deliveries = table.NotificationDeliveries
+	)
+
+	condition := postgres.Bool(true)
+	if filter.UserID != 0 {
+		condition = condition.AND(deliveries.PersonalInfoID.EQ(postgres.Int(filter.UserID)))
+	}
+	if filter.EventType != "" {
+		condition = condition.AND(deliveries.EventType.EQ(postgres.String(filter.EventType)))
+	}
+	if filter.Status != "" {
+		condition = condition.AND(deliveries.Status.EQ(postgres.String(string(filter.Status))))
+	}
+
+	query, args := deliveries.SELECT(deliveries.AllColumns).
+		WHERE(condition).
+		ORDER_BY(deliveries.ID.DESC()).
+		LIMIT(filter.Limit).Sql()
+
+	if err := pgxscan.Select(ctx, r.db, &result, query, args...); err != nil {
+		r.log.Error("failed to get notification deliveries", zap.Error(err))
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *Repository) GetNotificationDelivery(ctx context.Context, id int64) (*models.NotificationDelivery, error) {
+	r.log.Debug("getting notification delivery")
+
+	var (
+		delivery   models.NotificationDelivery
+		deliveries = table.NotificationDeliveries
+	)
+
+	query, args := deliveries.SELECT(deliveries.AllColumns).
+		WHERE(deliveries.ID.EQ(postgres.Int(id))).Sql()
+
+	if err := pgxscan.Get(ctx, r.db, &delivery, query, args...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrDeliveryNotFound
+		}
+		r.log.Error("failed to get notification delivery", zap.Error(err))
+		return nil, err
+	}
+
+	return &delivery, nil
+}
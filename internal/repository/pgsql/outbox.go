@@ -0,0 +1,156 @@
+package pgsql
+
+import (
+	"context"
+	"dussh/internal/broker/envelope"
+	"dussh/internal/domain/models"
+	"dussh/internal/repository/pgsql/.gen/dussh/public/table"
+	"encoding/json"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+	"time"
+)
+
+// saveOutboxMessage stores the event envelope in the outbox within the caller
+// transaction, so the event is published if and only if the transaction commits.
+// The event is delivered to the webhooks subscribed to it as well.
+func (r *Repository) saveOutboxMessage(ctx context.Context, tx pgx.Tx, e models.DomainEvent) error {
+	env, err := envelope.New(ctx, e)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	outbox := table.Outbox
+	query, args := outbox.INSERT(outbox.EventType, outbox.RoutingKey, outbox.Payload).
+		VALUES(e.EventType(), e.RoutingKey(), json.RawMessage(payload)).Sql()
+
+	if _, err := tx.Exec(ctx, query, args...); err != nil {
+		r.log.Error("failed to save outbox message", zap.Error(err))
+		return err
+	}
+
+	return r.saveWebhookDeliveries(ctx, tx, env)
+}
+
+// ClaimOutboxMessages returns up to limit pending messages and hides them from
+// other relays for the lease duration. Messages that are neither marked sent
+// nor failed before the lease expires are claimed again.
+func (r *Repository) ClaimOutboxMessages(
+	ctx context.Context,
+	limit int64,
+	lease time.Duration,
+) ([]*models.OutboxMessage, error) {
+	r.log.Debug("claiming outbox messages")
+
+	var (
+		messages []*models.OutboxMessage
+		outbox   = table.Outbox
+	)
+
+	pending := outbox.SELECT(outbox.ID).
+		WHERE(postgres.AND(
+			outbox.SentAt.IS_NULL(),
+			outbox.NextAttemptAt.LT_EQ(postgres.LOCALTIMESTAMP()),
+		)).
+		ORDER_BY(outbox.ID).
+		LIMIT(limit).
+		FOR(postgres.UPDATE().SKIP_LOCKED())
+
+	query, args := outbox.UPDATE(outbox.Attempts, outbox.NextAttemptAt).
+		SET(
+			outbox.Attempts.ADD(postgres.Int(1)),
+			postgres.LOCALTIMESTAMP().ADD(postgres.INTERVALd(lease)),
+		).
+		WHERE(outbox.ID.IN(pending)).
+		RETURNING(outbox.AllColumns).Sql()
+
+	if err := pgxscan.Select(ctx, r.db, &messages, query, args...); err != nil {
+		r.log.Error("failed to claim outbox messages", zap.Error(err))
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+func (r *Repository) MarkOutboxMessageSent(ctx context.Context, id int64) error {
+	r.log.Debug("marking outbox message as sent")
+
+	outbox := table.Outbox
+	query, args := outbox.UPDATE(outbox.SentAt, outbox.LastError).
+		SET(postgres.LOCALTIMESTAMP(), postgres.NULL).
+		WHERE(outbox.ID.EQ(postgres.Int(id))).Sql()
+
+	if _, err := r.db.Exec(ctx, query, args...); err != nil {
+		r.log.Error("failed to mark outbox message as sent", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) MarkOutboxMessageFailed(
+	ctx context.Context,
+	id int64,
+	reason string,
+	retryIn time.Duration,
+) error {
+	r.log.Debug("marking outbox message as failed")
+
+	outbox := table.Outbox
+	query, args := outbox.UPDATE(outbox.LastError, outbox.NextAttemptAt).
+		SET(
+			postgres.String(reason),
+			postgres.LOCALTIMESTAMP().ADD(postgres.INTERVALd(retryIn)),
+		).
+		WHERE(outbox.ID.EQ(postgres.Int(id))).Sql()
+
+	if _, err := r.db.Exec(ctx, query, args...); err != nil {
+		r.log.Error("failed to mark outbox message as failed", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// SaveDeferredNotification stores the notification in the outbox to be
+// published after the delay.
+func (r *Repository) SaveDeferredNotification(
+	ctx context.Context,
+	e models.DeferredNotificationEvent,
+	delay time.Duration,
+) error {
+	r.log.Debug("saving deferred notification")
+
+	env, err := envelope.New(ctx, e)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	outbox := table.Outbox
+	query, args := outbox.INSERT(outbox.EventType, outbox.RoutingKey, outbox.Payload, outbox.NextAttemptAt).
+		VALUES(
+			e.EventType(),
+			e.RoutingKey(),
+			json.RawMessage(payload),
+			postgres.LOCALTIMESTAMP().ADD(postgres.INTERVALd(delay)),
+		).Sql()
+
+	if _, err := r.db.Exec(ctx, query, args...); err != nil {
+		r.log.Error("failed to save deferred notification", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
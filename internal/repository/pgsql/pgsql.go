@@ -196,52 +196,118 @@ func (r *Repository) GetEmployeePosition(ctx context.Context, id int64) (*models
 	return &position, nil
 }
 
-func (r *Repository) UpdateUser(ctx context.Context, id int64, user *models.User) error {
+// UpdateUser updates the user and returns its new version. A non-zero version
+// must match the stored one, otherwise repository.ErrVersionMismatch is returned.
+func (r *Repository) UpdateUser(ctx context.Context, id int64, user *models.User, version int64) (int64, error) {
 	r.log.Debug("updating user by id")
 
-	updatePersonalInfo := table.PersonalInfo.UPDATE()
+	personalInfo := table.PersonalInfo
 	var columns []any
 	if user.FirstName != "" {
-		columns = append(columns, table.PersonalInfo.Name.SET(postgres.String(user.FirstName)))
+		columns = append(columns, personalInfo.Name.SET(postgres.String(user.FirstName)))
 	}
 	if user.Surname != "" {
-		columns = append(columns, table.PersonalInfo.Surname.SET(postgres.String(user.Surname)))
+		columns = append(columns, personalInfo.Surname.SET(postgres.String(user.Surname)))
 	}
 	if user.MiddleName != "" {
-		columns = append(columns, table.PersonalInfo.MiddleName.SET(postgres.String(user.MiddleName)))
+		columns = append(columns, personalInfo.MiddleName.SET(postgres.String(user.MiddleName)))
 	}
 	if user.Email != "" {
-		columns = append(columns, table.PersonalInfo.Email.SET(postgres.String(user.Email)))
+		columns = append(columns, personalInfo.Email.SET(postgres.String(user.Email)))
 	}
 	if user.Phone != "" {
-		columns = append(columns, table.PersonalInfo.Phone.SET(postgres.String(user.Phone)))
+		// a changed phone has to be verified again
+		columns = append(columns,
+			personalInfo.Phone.SET(postgres.String(user.Phone)),
+			personalInfo.PhoneVerifiedAt.SET(postgres.TimestampExp(
+				postgres.CASE().
+					WHEN(personalInfo.Phone.EQ(postgres.String(user.Phone))).THEN(personalInfo.PhoneVerifiedAt).
+					ELSE(postgres.NULL),
+			)),
+		)
 	}
 
-	if len(columns) < 1 {
-		r.log.Debug("nothing to updated")
-		return nil
-	}
+	var newVersion int64
+	if err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		current, err := lockVersion(ctx, tx, personalInfo, personalInfo.Version,
+			personalInfo.PersonalInfoID.EQ(postgres.Int(id)), version)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return repository.ErrUserNotFound
+			}
+			return err
+		}
+		newVersion = current
+
+		if len(columns) < 1 {
+			r.log.Debug("nothing to updated")
+			return nil
+		}
+
+		columns = append(columns, personalInfo.Version.SET(personalInfo.Version.ADD(postgres.Int(1))))
+		query, args := personalInfo.UPDATE().SET(columns[0], columns[1:]...).
+			WHERE(personalInfo.PersonalInfoID.EQ(postgres.Int(id))).
+			RETURNING(personalInfo.Version).Sql()
 
-	query, args := updatePersonalInfo.SET(columns[0], columns[1:]...).
-		WHERE(table.PersonalInfo.PersonalInfoID.EQ(postgres.Int(id))).Sql()
+		if err := tx.QueryRow(ctx, query, args...).Scan(&newVersion); err != nil {
+			return err
+		}
 
-	if _, err := r.db.Exec(ctx, query, args...); err != nil {
+		return r.saveWebhookEvent(ctx, tx, models.UserUpdatedEvent{UserID: id, Version: newVersion})
+	}); err != nil {
 		r.log.Debug("failed to update user", zap.Error(err))
-		return err
+		return 0, err
 	}
 
 	r.log.Debug("user updated successfully")
+	return newVersion, nil
+}
+
+// VerifyUserPhone marks the phone of the user as verified. It returns
+// repository.ErrUserNotFound if the user changed the phone in the meantime.
+func (r *Repository) VerifyUserPhone(ctx context.Context, id int64, phone string) error {
+	r.log.Debug("verifying user phone")
+
+	personalInfo := table.PersonalInfo
+	query, args := personalInfo.UPDATE(personalInfo.PhoneVerifiedAt).
+		SET(postgres.LOCALTIMESTAMP()).
+		WHERE(postgres.AND(
+			personalInfo.PersonalInfoID.EQ(postgres.Int(id)),
+			personalInfo.Phone.EQ(postgres.String(phone)),
+		)).Sql()
+
+	tag, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		r.log.Error("failed to verify user phone", zap.Error(err))
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrUserNotFound
+	}
+
 	return nil
 }
 
-func (r *Repository) DeleteUser(ctx context.Context, id int64) error {
+func (r *Repository) DeleteUser(ctx context.Context, id int64, version int64) error {
 	r.log.Debug("deleting user")
 
-	query, args := table.PersonalInfo.DELETE().
-		WHERE(table.PersonalInfo.PersonalInfoID.EQ(postgres.Int(id))).
-		Sql()
+	personalInfo := table.PersonalInfo
+	if err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		if _, err := lockVersion(ctx, tx, personalInfo, personalInfo.Version,
+			personalInfo.PersonalInfoID.EQ(postgres.Int(id)), version); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return repository.ErrUserNotFound
+			}
+			return err
+		}
 
-	if _, err := r.db.Exec(ctx, query, args...); err != nil {
+		query, args := personalInfo.DELETE().
+			WHERE(personalInfo.PersonalInfoID.EQ(postgres.Int(id))).
+			Sql()
+
+		_, err := tx.Exec(ctx, query, args...)
+		return err
+	}); err != nil {
 		r.log.Debug("failed to delete user", zap.Error(err))
 		return err
 	}
@@ -284,9 +350,9 @@ func (r *Repository) GetCourse(ctx context.Context, courseID int64) (*models.Cou
 			periodType string
 		)
 		if err := rows.Scan(
-			&csr.ID, &csr.Name, &csr.MonthlySubscriptionCost,
+			&csr.ID, &csr.Name, &csr.MonthlySubscriptionCost, &csr.Status, &csr.ArchivedAt, &csr.Version,
 			&event.ID, &event.Description, &startDate,
-			&event.RecurrentCount, &event.PeriodFreq, &periodType, &event.CourseID,
+			&event.RecurrentCount, &event.PeriodFreq, &periodType, &event.CourseID, &event.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -325,8 +391,8 @@ func (r *Repository) SaveCourse(ctx context.Context, crs *models.Course) (int64,
 	}
 
 	if err := withTx(ctx, r.db, func(tx pgx.Tx) error {
-		query, args := courses.INSERT(courses.AllColumns.Except(courses.CourseID)).
-			VALUES(crs.Name, crs.MonthlySubscriptionCost).RETURNING(courses.CourseID).Sql()
+		query, args := courses.INSERT(courses.CourseName, courses.MonthlySubscriptionCost, courses.Status).
+			VALUES(crs.Name, crs.MonthlySubscriptionCost, models.Draft.String()).RETURNING(courses.CourseID).Sql()
 
 		if err := tx.QueryRow(ctx, query, args...).Scan(&courseID); err != nil {
 			r.log.Error("failed to create course", zap.Error(err))
@@ -358,7 +424,7 @@ func (r *Repository) SaveEvents(ctx context.Context, courseID int64, events []*m
 			return err
 		}
 
-		return nil
+		return r.courseVersionIncrement(ctx, tx, courseID)
 	}); err != nil {
 		return err
 	}
@@ -375,7 +441,7 @@ func (r *Repository) SaveEmployees(ctx context.Context, courseID int64, employee
 			return err
 		}
 
-		return nil
+		return r.courseVersionIncrement(ctx, tx, courseID)
 	}); err != nil {
 		return err
 	}
@@ -389,7 +455,24 @@ func (r *Repository) SaveEnrollment(ctx context.Context, courseID, userID int64)
 
 	var enrollmentID int64
 	if err := withTx(ctx, r.db, func(tx pgx.Tx) error {
-		query, args := table.Enrollments.
+		var status models.CourseStatus
+		query, args := table.Courses.SELECT(table.Courses.Status).
+			WHERE(table.Courses.CourseID.EQ(postgres.Int(courseID))).
+			FOR(postgres.SHARE()).Sql()
+
+		if err := tx.QueryRow(ctx, query, args...).Scan(&status); err != nil {
+			r.log.Error("failed to get course status", zap.Error(err))
+			if errors.Is(err, pgx.ErrNoRows) {
+				return repository.ErrCourseNotFound
+			}
+			return err
+		}
+
+		if status != models.Published {
+			return repository.ErrCourseNotPublished
+		}
+
+		query, args = table.Enrollments.
 			INSERT(table.Enrollments.AllColumns.Except(table.Enrollments.ID)).
 			VALUES(courseID, userID).
 			RETURNING(table.Enrollments.ID).Sql()
@@ -405,12 +488,13 @@ func (r *Repository) SaveEnrollment(ctx context.Context, courseID, userID int64)
 		}
 
 		query, args = table.PersonalInfo.
-			UPDATE(table.PersonalInfo.RolesID).
+			UPDATE(table.PersonalInfo.RolesID, table.PersonalInfo.Version).
 			SET(
 				table.Roles.SELECT(table.Roles.RolesID).
 					WHERE(
 						table.Roles.Role.REGEXP_LIKE(postgres.String(models.Student.String()), false),
 					),
+				table.PersonalInfo.Version.ADD(postgres.Int(1)),
 			).
 			WHERE(table.PersonalInfo.PersonalInfoID.EQ(postgres.Int(userID))).Sql()
 
@@ -419,7 +503,10 @@ func (r *Repository) SaveEnrollment(ctx context.Context, courseID, userID int64)
 			return err
 		}
 
-		return nil
+		return r.saveOutboxMessage(ctx, tx, models.EnrollmentEvent{
+			CourseID: courseID,
+			UserID:   userID,
+		})
 	}); err != nil {
 		return 0, err
 	}
@@ -449,7 +536,7 @@ func (r *Repository) courseEventsCreate(
 	for _, e := range events {
 		if e != nil {
 			query, args := table.Events.
-				INSERT(table.Events.AllColumns.Except(table.Events.EventID)).
+				INSERT(table.Events.AllColumns.Except(table.Events.EventID, table.Events.Version)).
 				VALUES(e.Description, time.Time(*e.StartDate), e.RecurrentCount, e.PeriodFreq, e.PeriodType, courseID).
 				RETURNING(table.Events.EventID).Sql()
 
@@ -490,42 +577,78 @@ func (r *Repository) courseEmployeesCreate(
 
 // todo add unique constraint to courses table
 
-func (r *Repository) UpdateCourse(ctx context.Context, id int64, crs *models.Course) error {
+// UpdateCourse updates the course with its events and returns the new course
+// version. A non-zero version must match the stored one, otherwise
+// repository.ErrVersionMismatch is returned.
+func (r *Repository) UpdateCourse(ctx context.Context, id int64, crs *models.Course, version int64) (int64, error) {
 	r.log.Debug("updating course")
 
-	updateCourse := table.Courses.UPDATE()
+	courses := table.Courses
 	var columns []any
 	if crs.Name != "" {
-		columns = append(columns, table.Courses.CourseName.SET(postgres.String(crs.Name)))
+		columns = append(columns, courses.CourseName.SET(postgres.String(crs.Name)))
 	}
 	if crs.MonthlySubscriptionCost != nil {
-		columns = append(columns, table.Courses.MonthlySubscriptionCost.SET(postgres.Float(*crs.MonthlySubscriptionCost)))
+		columns = append(columns, courses.MonthlySubscriptionCost.SET(postgres.Float(*crs.MonthlySubscriptionCost)))
 	}
 
-	if len(crs.Events) > 0 {
-		if err := r.courseEventsUpdate(ctx, crs.Events); err != nil {
+	var newVersion int64
+	if err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		current, err := lockVersion(ctx, tx, courses, courses.Version,
+			courses.CourseID.EQ(postgres.Int(id)), version)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return repository.ErrCourseNotFound
+			}
 			return err
 		}
-	}
+		newVersion = current
 
-	if len(columns) < 1 {
-		r.log.Debug("course has nothing to update")
-		return nil
-	}
+		eventsUpdated, err := r.courseEventsUpdate(ctx, tx, id, crs.Events)
+		if err != nil {
+			return err
+		}
 
-	query, args := updateCourse.SET(columns[0], columns[1:]...).
-		WHERE(table.Courses.CourseID.EQ(postgres.Int(id))).Sql()
+		if len(columns) < 1 && !eventsUpdated {
+			r.log.Debug("course has nothing to update")
+			return nil
+		}
+
+		columns = append(columns, courses.Version.SET(courses.Version.ADD(postgres.Int(1))))
+		query, args := courses.UPDATE().SET(columns[0], columns[1:]...).
+			WHERE(courses.CourseID.EQ(postgres.Int(id))).
+			RETURNING(courses.Version).Sql()
+
+		if err := tx.QueryRow(ctx, query, args...).Scan(&newVersion); err != nil {
+			return err
+		}
 
-	if _, err := r.db.Exec(ctx, query, args...); err != nil {
+		if eventsUpdated {
+			if err := r.saveOutboxMessage(ctx, tx, models.ScheduleChangedEvent{CourseID: id}); err != nil {
+				return err
+			}
+		}
+
+		return r.saveWebhookEvent(ctx, tx, models.CourseUpdatedEvent{CourseID: id, Version: newVersion})
+	}); err != nil {
 		r.log.Debug("failed to update course", zap.Error(err))
-		return err
+		return 0, err
 	}
 
 	r.log.Debug("course updated successfully")
-	return nil
+	return newVersion, nil
 }
 
-func (r *Repository) courseEventsUpdate(ctx context.Context, events []*models.Event) error {
+// courseEventsUpdate updates the course events and reports whether any of
+// them changed. Events with a non-zero version are updated only if it
+// matches the stored one.
+func (r *Repository) courseEventsUpdate(
+	ctx context.Context,
+	tx pgx.Tx,
+	courseID int64,
+	events []*models.Event,
+) (bool, error) {
+	var updated bool
 	for _, e := range events {
 		eventUpdate := table.Events.UPDATE()
 		var columns []any
@@ -549,47 +672,204 @@ func (r *Repository) courseEventsUpdate(ctx context.Context, events []*models.Ev
 			continue
 		}
 
+		columns = append(columns, table.Events.Version.SET(table.Events.Version.ADD(postgres.Int(1))))
+		conditions := []postgres.BoolExpression{
+			table.Events.EventID.EQ(postgres.Int(e.ID)),
+			table.Events.CourseID.EQ(postgres.Int(courseID)),
+		}
+		if e.Version != 0 {
+			conditions = append(conditions, table.Events.Version.EQ(postgres.Int(e.Version)))
+		}
+
 		query, args := eventUpdate.SET(columns[0], columns[1:]...).
-			WHERE(table.Events.EventID.EQ(postgres.Int(e.ID))).Sql()
-		if _, err := r.db.Exec(ctx, query, args...); err != nil {
+			WHERE(postgres.AND(conditions...)).Sql()
+		tag, err := tx.Exec(ctx, query, args...)
+		if err != nil {
 			r.log.Debug("failed to update event", zap.Error(err))
-			return err
+			return false, err
 		}
+
+		if tag.RowsAffected() == 0 {
+			if e.Version != 0 {
+				return false, repository.ErrVersionMismatch
+			}
+			return false, repository.ErrEventNotFound
+		}
+
+		updated = true
 		r.log.Debug("event updated successfully", zap.Int64("event_id", e.ID))
 	}
 
-	return nil
+	return updated, nil
+}
+
+// courseVersionIncrement bumps the version of the course after its events or
+// employees changed and notifies webhooks about the new version.
+func (r *Repository) courseVersionIncrement(ctx context.Context, tx pgx.Tx, courseID int64) error {
+	query, args := table.Courses.UPDATE().
+		SET(table.Courses.Version.SET(table.Courses.Version.ADD(postgres.Int(1)))).
+		WHERE(table.Courses.CourseID.EQ(postgres.Int(courseID))).
+		RETURNING(table.Courses.Version).Sql()
+
+	var newVersion int64
+	if err := tx.QueryRow(ctx, query, args...).Scan(&newVersion); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repository.ErrCourseNotFound
+		}
+		r.log.Error("failed to increment course version", zap.Error(err))
+		return err
+	}
+
+	return r.saveWebhookEvent(ctx, tx, models.CourseUpdatedEvent{CourseID: courseID, Version: newVersion})
+}
+
+// lockVersion locks the row matching condition until the end of tx and
+// returns its version. A non-zero version must match the stored one.
+func lockVersion(
+	ctx context.Context,
+	tx pgx.Tx,
+	tbl postgres.ReadableTable,
+	versionColumn postgres.ColumnInteger,
+	condition postgres.BoolExpression,
+	version int64,
+) (int64, error) {
+	var current int64
+	query, args := postgres.SELECT(versionColumn).
+		FROM(tbl).
+		WHERE(condition).
+		FOR(postgres.UPDATE()).Sql()
+
+	if err := tx.QueryRow(ctx, query, args...).Scan(&current); err != nil {
+		return 0, err
+	}
+
+	if version != 0 && current != version {
+		return 0, repository.ErrVersionMismatch
+	}
+
+	return current, nil
 }
 
-func (r *Repository) DeleteCourse(ctx context.Context, id int64) error {
-	r.log.Debug("deleting course")
+func (r *Repository) GetCourseStatus(ctx context.Context, id int64) (models.CourseStatus, error) {
+	r.log.Debug("getting course status")
 
-	query, args := table.Courses.DELETE().
+	var status models.CourseStatus
+	query, args := table.Courses.SELECT(table.Courses.Status).
 		WHERE(table.Courses.CourseID.EQ(postgres.Int(id))).
 		Sql()
 
-	if _, err := r.db.Exec(ctx, query, args...); err != nil {
-		r.log.Debug("failed to delete course", zap.Error(err))
+	if err := r.db.QueryRow(ctx, query, args...).Scan(&status); err != nil {
+		r.log.Debug("failed to get course status", zap.Error(err))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, repository.ErrCourseNotFound
+		}
+		return 0, err
+	}
+
+	return status, nil
+}
+
+// UpdateCourseStatus moves the course from status to next. It fails with
+// repository.ErrCourseStatusChanged if the course is no longer in status from.
+// A non-zero version must match the stored one. A course with unpaid invoices
+// is archived only when force is set.
+func (r *Repository) UpdateCourseStatus(
+	ctx context.Context,
+	id int64,
+	from, next models.CourseStatus,
+	version int64,
+	force bool,
+) error {
+	r.log.Debug("updating course status")
+
+	courses := table.Courses
+	archivedAt := postgres.TimestampExp(postgres.NULL)
+	if next == models.Archived {
+		archivedAt = postgres.LOCALTIMESTAMP()
+	}
+
+	if err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		if _, err := lockVersion(ctx, tx, courses, courses.Version,
+			courses.CourseID.EQ(postgres.Int(id)), version); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return repository.ErrCourseNotFound
+			}
+			return err
+		}
+
+		if next == models.Archived && !force {
+			unpaid, err := countUnpaidInvoices(ctx, tx, id)
+			if err != nil {
+				return err
+			}
+			if unpaid > 0 {
+				return repository.ErrCourseHasUnpaidInvoices
+			}
+		}
+
+		query, args := courses.UPDATE().
+			SET(
+				courses.Status.SET(postgres.String(next.String())),
+				courses.ArchivedAt.SET(archivedAt),
+				courses.Version.SET(courses.Version.ADD(postgres.Int(1))),
+			).
+			WHERE(
+				postgres.AND(
+					courses.CourseID.EQ(postgres.Int(id)),
+					courses.Status.EQ(postgres.String(from.String())),
+				),
+			).
+			RETURNING(courses.Version).Sql()
+
+		var newVersion int64
+		if err := tx.QueryRow(ctx, query, args...).Scan(&newVersion); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return repository.ErrCourseStatusChanged
+			}
+			return err
+		}
+
+		return r.saveWebhookEvent(ctx, tx, models.CourseUpdatedEvent{
+			CourseID: id,
+			Status:   next.String(),
+			Version:  newVersion,
+		})
+	}); err != nil {
+		r.log.Debug("failed to update course status", zap.Error(err))
 		return err
 	}
 
-	r.log.Debug("course deleted successfully")
+	r.log.Debug("course status updated successfully", zap.String("status", next.String()))
 	return nil
 }
 
 func (r *Repository) DeleteEvent(ctx context.Context, courseID, eventID int64) error {
 	r.log.Debug("deleting course event")
 
-	query, args := table.Events.DELETE().
-		WHERE(
-			postgres.AND(
-				table.Events.CourseID.EQ(postgres.Int(courseID)),
-				table.Events.EventID.EQ(postgres.Int(eventID)),
-			),
-		).
-		Sql()
+	if err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		query, args := table.Events.DELETE().
+			WHERE(
+				postgres.AND(
+					table.Events.CourseID.EQ(postgres.Int(courseID)),
+					table.Events.EventID.EQ(postgres.Int(eventID)),
+				),
+			).
+			Sql()
+
+		tag, err := tx.Exec(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+
+		if err := r.courseVersionIncrement(ctx, tx, courseID); err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return nil
+		}
 
-	if _, err := r.db.Exec(ctx, query, args...); err != nil {
+		return r.saveOutboxMessage(ctx, tx, models.ScheduleChangedEvent{CourseID: courseID})
+	}); err != nil {
 		r.log.Debug("failed to delete course event", zap.Error(err))
 		return err
 	}
@@ -601,16 +881,22 @@ func (r *Repository) DeleteEvent(ctx context.Context, courseID, eventID int64) e
 func (r *Repository) DeleteEmployee(ctx context.Context, courseID, employeeID int64) error {
 	r.log.Debug("deleting course employee")
 
-	query, args := table.EmployeeCourses.DELETE().
-		WHERE(
-			postgres.AND(
-				table.EmployeeCourses.CourseID.EQ(postgres.Int(courseID)),
-				table.EmployeeCourses.EmployeeID.EQ(postgres.Int(employeeID)),
-			),
-		).
-		Sql()
+	if err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		query, args := table.EmployeeCourses.DELETE().
+			WHERE(
+				postgres.AND(
+					table.EmployeeCourses.CourseID.EQ(postgres.Int(courseID)),
+					table.EmployeeCourses.EmployeeID.EQ(postgres.Int(employeeID)),
+				),
+			).
+			Sql()
+
+		if _, err := tx.Exec(ctx, query, args...); err != nil {
+			return err
+		}
 
-	if _, err := r.db.Exec(ctx, query, args...); err != nil {
+		return r.courseVersionIncrement(ctx, tx, courseID)
+	}); err != nil {
 		r.log.Debug("failed to delete course employee", zap.Error(err))
 		return err
 	}
@@ -619,17 +905,49 @@ func (r *Repository) DeleteEmployee(ctx context.Context, courseID, employeeID in
 	return nil
 }
 
+func (r *Repository) GetEnrollment(ctx context.Context, enrollmentID int64) (*models.Enrollment, error) {
+	r.log.Debug("getting course enrollment")
+
+	var enrollment models.Enrollment
+	query, args := table.Enrollments.SELECT(table.Enrollments.AllColumns).
+		WHERE(table.Enrollments.ID.EQ(postgres.Int(enrollmentID))).
+		Sql()
+
+	if err := pgxscan.Get(ctx, r.db, &enrollment, query, args...); err != nil {
+		r.log.Debug("failed to get course enrollment", zap.Error(err))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrEnrollmentNotFound
+		}
+		return nil, err
+	}
+
+	return &enrollment, nil
+}
+
+// DeleteEnrollment deletes the enrollment and publishes its cancellation
+// through the outbox.
 func (r *Repository) DeleteEnrollment(ctx context.Context, enrollmentID int64) error {
 	r.log.Debug("deleting course enrollment")
 
-	query, args := table.Enrollments.DELETE().
-		WHERE(
-			table.Enrollments.ID.EQ(postgres.Int(enrollmentID)),
-		).
-		Sql()
+	if err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		query, args := table.Enrollments.DELETE().
+			WHERE(
+				table.Enrollments.ID.EQ(postgres.Int(enrollmentID)),
+			).
+			RETURNING(table.Enrollments.CourseID, table.Enrollments.PersonalInfoID).
+			Sql()
 
-	if _, err := r.db.Exec(ctx, query, args...); err != nil {
-		r.log.Debug("failed to delete course employee", zap.Error(err))
+		var e models.EnrollmentCancelledEvent
+		if err := tx.QueryRow(ctx, query, args...).Scan(&e.CourseID, &e.UserID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil
+			}
+			return err
+		}
+
+		return r.saveOutboxMessage(ctx, tx, e)
+	}); err != nil {
+		r.log.Debug("failed to delete course enrollment", zap.Error(err))
 		return err
 	}
 
@@ -727,7 +1045,9 @@ func (r *Repository) GetUsers(ctx context.Context) ([]*models.User, error) {
 func (r *Repository) GetCourses(ctx context.Context) ([]*models.Course, error) {
 	var courses []*models.Course
 
-	query, args := table.Courses.SELECT(table.Courses.AllColumns).Sql()
+	query, args := table.Courses.SELECT(table.Courses.AllColumns).
+		WHERE(table.Courses.Status.NOT_EQ(postgres.String(models.Archived.String()))).
+		Sql()
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		r.log.Debug("failed to get all courses", zap.Error(err))
@@ -0,0 +1,82 @@
+package pgsql
+
+import (
+	"context"
+	"dussh/internal/domain/models"
+	"dussh/internal/repository"
+	"dussh/internal/repository/pgsql/.gen/dussh/public/table"
+	"encoding/json"
+	"errors"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+func (r *Repository) GetNotificationPreferences(
+	ctx context.Context,
+	userID int64,
+) (*models.NotificationPreferences, error) {
+	r.log.Debug("getting notification preferences")
+
+	var (
+		prefs models.NotificationPreferences
+		np    = table.NotificationPreferences
+	)
+
+	query, args := np.SELECT(np.AllColumns).
+		WHERE(np.PersonalInfoID.EQ(postgres.Int(userID))).Sql()
+
+	if err := pgxscan.Get(ctx, r.db, &prefs, query, args...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrPreferencesNotFound
+		}
+		r.log.Error("failed to get notification preferences", zap.Error(err))
+		return nil, err
+	}
+
+	return &prefs, nil
+}
+
+// SaveNotificationPreferences replaces the preferences of the user.
+func (r *Repository) SaveNotificationPreferences(ctx context.Context, prefs *models.NotificationPreferences) error {
+	r.log.Debug("saving notification preferences")
+
+	channels, err := json.Marshal(prefs.Channels)
+	if err != nil {
+		return err
+	}
+
+	delivery, err := json.Marshal(prefs.Delivery)
+	if err != nil {
+		return err
+	}
+
+	var quietHours any
+	if prefs.QuietHours != nil {
+		data, err := json.Marshal(prefs.QuietHours)
+		if err != nil {
+			return err
+		}
+		quietHours = json.RawMessage(data)
+	}
+
+	np := table.NotificationPreferences
+	query, args := np.INSERT(np.PersonalInfoID, np.Channels, np.QuietHours, np.Locale, np.Delivery).
+		VALUES(prefs.UserID, json.RawMessage(channels), quietHours, prefs.Locale, json.RawMessage(delivery)).
+		ON_CONFLICT(np.PersonalInfoID).
+		DO_UPDATE(postgres.SET(
+			np.Channels.SET(np.EXCLUDED.Channels),
+			np.QuietHours.SET(np.EXCLUDED.QuietHours),
+			np.Locale.SET(np.EXCLUDED.Locale),
+			np.Delivery.SET(np.EXCLUDED.Delivery),
+			np.UpdatedAt.SET(postgres.LOCALTIMESTAMP()),
+		)).Sql()
+
+	if _, err := r.db.Exec(ctx, query, args...); err != nil {
+		r.log.Error("failed to save notification preferences", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package pgsql
+
+import (
+	"context"
+	"dussh/internal/domain/models"
+	"dussh/internal/repository/pgsql/.gen/dussh/public/table"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// GetCourseUserIDs returns the users enrolled in the course.
+func (r *Repository) GetCourseUserIDs(ctx context.Context, courseID int64) ([]int64, error) {
+	r.log.Debug("getting course users")
+
+	var (
+		ids         []int64
+		enrollments = table.Enrollments
+	)
+
+	query, args := enrollments.SELECT(enrollments.PersonalInfoID).
+		WHERE(enrollments.CourseID.EQ(postgres.Int(courseID))).
+		ORDER_BY(enrollments.PersonalInfoID).Sql()
+
+	if err := pgxscan.Select(ctx, r.db, &ids, query, args...); err != nil {
+		r.log.Error("failed to get course users", zap.Error(err))
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// GetCourseTrainerIDs returns the users employed as trainers of the course.
+func (r *Repository) GetCourseTrainerIDs(ctx context.Context, courseID int64) ([]int64, error) {
+	r.log.Debug("getting course trainers")
+
+	var ids []int64
+
+	query, args := table.Employees.
+		INNER_JOIN(table.EmployeeCourses, table.EmployeeCourses.EmployeeID.EQ(table.Employees.EmployeeID)).
+		SELECT(table.Employees.PersonalInfoID).
+		WHERE(table.EmployeeCourses.CourseID.EQ(postgres.Int(courseID))).
+		ORDER_BY(table.Employees.PersonalInfoID).Sql()
+
+	if err := pgxscan.Select(ctx, r.db, &ids, query, args...); err != nil {
+		r.log.Error("failed to get course trainers", zap.Error(err))
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// SaveSessionReminder enqueues the reminder unless it was enqueued before and
+// reports whether it was. The reminder is published through the outbox.
+func (r *Repository) SaveSessionReminder(ctx context.Context, reminder *models.SessionReminder) (bool, error) {
+	r.log.Debug("saving session reminder")
+
+	var saved bool
+
+	reminders := table.SessionReminders
+	if err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		query, args := reminders.INSERT(
+			reminders.EventID,
+			reminders.CourseID,
+			reminders.PersonalInfoID,
+			reminders.StartsAt,
+			reminders.OffsetMinutes,
+		).
+			VALUES(
+				reminder.EventID,
+				reminder.CourseID,
+				reminder.UserID,
+				reminder.StartsAt,
+				int64(reminder.Offset.Minutes()),
+			).
+			ON_CONFLICT().DO_NOTHING().Sql()
+
+		tag, err := tx.Exec(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return nil
+		}
+		saved = true
+
+		return r.saveOutboxMessage(ctx, tx, models.SessionReminderEvent{
+			UserID:   reminder.UserID,
+			CourseID: reminder.CourseID,
+			EventID:  reminder.EventID,
+			StartsAt: reminder.StartsAt,
+		})
+	}); err != nil {
+		r.log.Error("failed to save session reminder", zap.Error(err))
+		return false, err
+	}
+
+	return saved, nil
+}
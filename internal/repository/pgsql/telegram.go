@@ -0,0 +1,117 @@
+package pgsql
+
+import (
+	"context"
+	"dussh/internal/domain/models"
+	"dussh/internal/repository"
+	"dussh/internal/repository/pgsql/.gen/dussh/public/table"
+	"errors"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// SaveTelegramLink links the user to the chat, replacing a previous link of the user.
+func (r *Repository) SaveTelegramLink(ctx context.Context, userID, chatID int64) error {
+	r.log.Debug("saving telegram link")
+
+	links := table.TelegramLinks
+	query, args := links.INSERT(links.PersonalInfoID, links.ChatID).
+		VALUES(userID, chatID).
+		ON_CONFLICT(links.PersonalInfoID).
+		DO_UPDATE(postgres.SET(
+			links.ChatID.SET(links.EXCLUDED.ChatID),
+			links.LinkedAt.SET(postgres.LOCALTIMESTAMP()),
+		)).Sql()
+
+	if _, err := r.db.Exec(ctx, query, args...); err != nil {
+		r.log.Error("failed to save telegram link", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) DeleteTelegramLink(ctx context.Context, userID int64) error {
+	r.log.Debug("deleting telegram link")
+
+	links := table.TelegramLinks
+	query, args := links.DELETE().
+		WHERE(links.PersonalInfoID.EQ(postgres.Int(userID))).Sql()
+
+	tag, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		r.log.Error("failed to delete telegram link", zap.Error(err))
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrTelegramNotLinked
+	}
+
+	return nil
+}
+
+func (r *Repository) GetTelegramLink(ctx context.Context, userID int64) (*models.TelegramLink, error) {
+	r.log.Debug("getting telegram link")
+
+	var (
+		link  models.TelegramLink
+		links = table.TelegramLinks
+	)
+
+	query, args := links.SELECT(links.AllColumns).
+		WHERE(links.PersonalInfoID.EQ(postgres.Int(userID))).Sql()
+
+	if err := pgxscan.Get(ctx, r.db, &link, query, args...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrTelegramNotLinked
+		}
+		r.log.Error("failed to get telegram link", zap.Error(err))
+		return nil, err
+	}
+
+	return &link, nil
+}
+
+// GetTelegramLinksByChat returns the links of every user linked to the chat.
+func (r *Repository) GetTelegramLinksByChat(ctx context.Context, chatID int64) ([]*models.TelegramLink, error) {
+	r.log.Debug("getting telegram links by chat")
+
+	var (
+		result []*models.TelegramLink
+		links  = table.TelegramLinks
+	)
+
+	query, args := links.SELECT(links.AllColumns).
+		WHERE(links.ChatID.EQ(postgres.Int(chatID))).
+		ORDER_BY(links.PersonalInfoID).Sql()
+
+	if err := pgxscan.Select(ctx, r.db, &result, query, args...); err != nil {
+		r.log.Error("failed to get telegram links by chat", zap.Error(err))
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetUserCourseIDs returns the courses the user is enrolled in.
+func (r *Repository) GetUserCourseIDs(ctx context.Context, userID int64) ([]int64, error) {
+	r.log.Debug("getting user courses")
+
+	var (
+		ids         []int64
+		enrollments = table.Enrollments
+	)
+
+	query, args := enrollments.SELECT(enrollments.CourseID).
+		WHERE(enrollments.PersonalInfoID.EQ(postgres.Int(userID))).
+		ORDER_BY(enrollments.CourseID).Sql()
+
+	if err := pgxscan.Select(ctx, r.db, &ids, query, args...); err != nil {
+		r.log.Error("failed to get user courses", zap.Error(err))
+		return nil, err
+	}
+
+	return ids, nil
+}
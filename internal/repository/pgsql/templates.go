@@ -0,0 +1,110 @@
+package pgsql
+
+import (
+	"context"
+	"dussh/internal/domain/models"
+	"dussh/internal/repository"
+	"dussh/internal/repository/pgsql/.gen/dussh/public/table"
+	"errors"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// SaveNotificationTemplate stores the template as the next version of its
+// event type and locale.
+func (r *Repository) SaveNotificationTemplate(
+	ctx context.Context,
+	t *models.NotificationTemplate,
+) (*models.NotificationTemplate, error) {
+	r.log.Debug("saving notification template")
+
+	var (
+		saved models.NotificationTemplate
+		nt    = table.NotificationTemplates
+	)
+
+	nextVersion := nt.SELECT(postgres.IntExp(postgres.COALESCE(postgres.MAX(nt.Version), postgres.Int(0))).ADD(postgres.Int(1))).
+		WHERE(nt.EventType.EQ(postgres.String(t.EventType)).AND(nt.Locale.EQ(postgres.String(t.Locale))))
+
+	query, args := nt.INSERT(nt.EventType, nt.Locale, nt.Version, nt.Layout, nt.Subject, nt.TextBody, nt.HtmlBody).
+		VALUES(t.EventType, t.Locale, nextVersion, t.Layout, t.Subject, t.Text, t.HTML).
+		RETURNING(nt.AllColumns).Sql()
+
+	if err := pgxscan.Get(ctx, r.db, &saved, query, args...); err != nil {
+		r.log.Error("failed to save notification template", zap.Error(err))
+		return nil, err
+	}
+
+	return &saved, nil
+}
+
+// GetNotificationTemplate returns the version of the template, the latest
+// version if version is 0.
+func (r *Repository) GetNotificationTemplate(
+	ctx context.Context,
+	eventType, locale string,
+	version int,
+) (*models.NotificationTemplate, error) {
+	r.log.Debug("getting notification template")
+
+	var (
+		t  models.NotificationTemplate
+		nt = table.NotificationTemplates
+	)
+
+	condition := nt.EventType.EQ(postgres.String(eventType)).
+		AND(nt.Locale.EQ(postgres.String(locale)))
+	if version > 0 {
+		condition = condition.AND(nt.Version.EQ(postgres.Int(int64(version))))
+	}
+
+	query, args := nt.SELECT(nt.AllColumns).
+		WHERE(condition).
+		ORDER_BY(nt.Version.DESC()).
+		LIMIT(1).Sql()
+
+	if err := pgxscan.Get(ctx, r.db, &t, query, args...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrTemplateNotFound
+		}
+		r.log.Error("failed to get notification template", zap.Error(err))
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+// GetNotificationTemplates returns the stored versions, newest first. Empty
+// filters match every event type or locale.
+func (r *Repository) GetNotificationTemplates(
+	ctx context.Context,
+	eventType, locale string,
+) ([]*models.NotificationTemplate, error) {
+	r.log.Debug("getting notification templates")
+
+	var (
+		result []*models.NotificationTemplate
+		nt     = table.NotificationTemplates
+	)
+
+	condition := postgres.Bool(true)
+	if eventType != "" {
+		condition = condition.AND(nt.EventType.EQ(postgres.String(eventType)))
+	}
+	if locale != "" {
+		condition = condition.AND(nt.Locale.EQ(postgres.String(locale)))
+	}
+
+	query, args := nt.SELECT(nt.AllColumns).
+		WHERE(condition).
+		ORDER_BY(nt.EventType, nt.Locale, nt.Version.DESC()).Sql()
+
+	if err := pgxscan.Select(ctx, r.db, &result, query, args...); err != nil {
+		r.log.Error("failed to get notification templates", zap.Error(err))
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,351 @@
+package pgsql
+
+import (
+	"context"
+	"dussh/internal/broker/envelope"
+	"dussh/internal/domain/models"
+	"dussh/internal/repository"
+	"dussh/internal/repository/pgsql/.gen/dussh/public/table"
+	"encoding/json"
+	"errors"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+	"time"
+)
+
+func (r *Repository) CreateWebhookSubscription(ctx context.Context, s *models.WebhookSubscription) (int64, error) {
+	r.log.Debug("creating webhook subscription")
+
+	var (
+		id   int64
+		subs = table.WebhookSubscriptions
+	)
+
+	query, args := subs.INSERT(subs.URL, subs.EventTypes, subs.Secret, subs.Active).
+		VALUES(s.URL, s.EventTypes, s.Secret, s.Active).
+		RETURNING(subs.ID).Sql()
+
+	if err := r.db.QueryRow(ctx, query, args...).Scan(&id); err != nil {
+		r.log.Error("failed to create webhook subscription", zap.Error(err))
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (r *Repository) GetWebhookSubscriptions(ctx context.Context) ([]*models.WebhookSubscription, error) {
+	r.log.Debug("getting webhook subscriptions")
+
+	var (
+		result []*models.WebhookSubscription
+		subs   = table.WebhookSubscriptions
+	)
+
+	query, args := subs.SELECT(subs.AllColumns).ORDER_BY(subs.ID).Sql()
+
+	if err := pgxscan.Select(ctx, r.db, &result, query, args...); err != nil {
+		r.log.Error("failed to get webhook subscriptions", zap.Error(err))
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *Repository) GetWebhookSubscription(ctx context.Context, id int64) (*models.WebhookSubscription, error) {
+	r.log.Debug("getting webhook subscription")
+
+	var (
+		sub  models.WebhookSubscription
+		subs = table.WebhookSubscriptions
+	)
+
+	query, args := subs.SELECT(subs.AllColumns).
+		WHERE(subs.ID.EQ(postgres.Int(id))).Sql()
+
+	if err := pgxscan.Get(ctx, r.db, &sub, query, args...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrWebhookNotFound
+		}
+		r.log.Error("failed to get webhook subscription", zap.Error(err))
+		return nil, err
+	}
+
+	return &sub, nil
+}
+
+// UpdateWebhookSubscription replaces the subscription, the secret is kept if empty.
+func (r *Repository) UpdateWebhookSubscription(ctx context.Context, id int64, s *models.WebhookSubscription) error {
+	r.log.Debug("updating webhook subscription")
+
+	subs := table.WebhookSubscriptions
+	columns := postgres.ColumnList{subs.URL, subs.EventTypes, subs.Active, subs.UpdatedAt}
+	values := []any{s.URL, s.EventTypes, s.Active, postgres.LOCALTIMESTAMP()}
+	if s.Secret != "" {
+		columns = append(columns, subs.Secret)
+		values = append(values, s.Secret)
+	}
+
+	query, args := subs.UPDATE(columns).
+		SET(values[0], values[1:]...).
+		WHERE(subs.ID.EQ(postgres.Int(id))).Sql()
+
+	tag, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		r.log.Error("failed to update webhook subscription", zap.Error(err))
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// DeleteWebhookSubscription deletes the subscription with its delivery log.
+func (r *Repository) DeleteWebhookSubscription(ctx context.Context, id int64) error {
+	r.log.Debug("deleting webhook subscription")
+
+	subs := table.WebhookSubscriptions
+	query, args := subs.DELETE().WHERE(subs.ID.EQ(postgres.Int(id))).Sql()
+
+	tag, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		r.log.Error("failed to delete webhook subscription", zap.Error(err))
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// saveWebhookDeliveries creates a delivery of the event for every active
+// subscription to its type within the caller transaction.
+func (r *Repository) saveWebhookDeliveries(ctx context.Context, tx pgx.Tx, env *envelope.Envelope) error {
+	payload, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	var (
+		subs       = table.WebhookSubscriptions
+		deliveries = table.WebhookDeliveries
+	)
+
+	query, args := deliveries.INSERT(
+		deliveries.SubscriptionID, deliveries.EventID, deliveries.EventType, deliveries.Payload,
+	).QUERY(
+		subs.SELECT(
+			subs.ID,
+			postgres.String(env.ID),
+			postgres.String(env.Type),
+			postgres.Raw("#payload::jsonb", postgres.RawArgs{"#payload": string(payload)}),
+		).WHERE(postgres.AND(
+			subs.Active.IS_TRUE(),
+			postgres.RawBool("#type = ANY(webhook_subscriptions.event_types)", postgres.RawArgs{"#type": env.Type}),
+		)),
+	).Sql()
+
+	if _, err := tx.Exec(ctx, query, args...); err != nil {
+		r.log.Error("failed to save webhook deliveries", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// saveWebhookEvent delivers the event to webhooks only, for events without
+// broker consumers.
+func (r *Repository) saveWebhookEvent(ctx context.Context, tx pgx.Tx, e models.DomainEvent) error {
+	env, err := envelope.New(ctx, e)
+	if err != nil {
+		return err
+	}
+
+	return r.saveWebhookDeliveries(ctx, tx, env)
+}
+
+// ClaimWebhookDeliveries returns up to limit pending deliveries and hides
+// them from other workers for the lease duration.
+func (r *Repository) ClaimWebhookDeliveries(
+	ctx context.Context,
+	limit int64,
+	lease time.Duration,
+) ([]*models.WebhookDelivery, error) {
+	r.log.Debug("claiming webhook deliveries")
+
+	var (
+		claimed    []*models.WebhookDelivery
+		deliveries = table.WebhookDeliveries
+	)
+
+	pending := deliveries.SELECT(deliveries.ID).
+		WHERE(postgres.AND(
+			deliveries.Status.EQ(postgres.String(string(models.WebhookPending))),
+			deliveries.NextAttemptAt.LT_EQ(postgres.LOCALTIMESTAMP()),
+		)).
+		ORDER_BY(deliveries.ID).
+		LIMIT(limit).
+		FOR(postgres.UPDATE().SKIP_LOCKED())
+
+	query, args := deliveries.UPDATE(deliveries.Attempts, deliveries.NextAttemptAt).
+		SET(
+			deliveries.Attempts.ADD(postgres.Int(1)),
+			postgres.LOCALTIMESTAMP().ADD(postgres.INTERVALd(lease)),
+		).
+		WHERE(deliveries.ID.IN(pending)).
+		RETURNING(deliveries.AllColumns).Sql()
+
+	if err := pgxscan.Select(ctx, r.db, &claimed, query, args...); err != nil {
+		r.log.Error("failed to claim webhook deliveries", zap.Error(err))
+		return nil, err
+	}
+
+	return claimed, nil
+}
+
+// RecordWebhookAttempt logs the attempt and moves the delivery to the status,
+// a pending delivery is attempted again after retryIn.
+func (r *Repository) RecordWebhookAttempt(
+	ctx context.Context,
+	attempt *models.WebhookAttempt,
+	status models.WebhookDeliveryStatus,
+	retryIn time.Duration,
+) error {
+	r.log.Debug("recording webhook attempt")
+
+	var (
+		attempts   = table.WebhookDeliveryAttempts
+		deliveries = table.WebhookDeliveries
+	)
+
+	deliveredAt := postgres.TimestampExp(postgres.NULL)
+	if status == models.WebhookSucceeded {
+		deliveredAt = postgres.LOCALTIMESTAMP()
+	}
+
+	return withTx(ctx, r.db, func(tx pgx.Tx) error {
+		query, args := attempts.INSERT(
+			attempts.DeliveryID, attempts.ResponseCode, attempts.ResponseBody, attempts.Error, attempts.DurationMs,
+		).VALUES(
+			attempt.DeliveryID, attempt.ResponseCode, attempt.ResponseBody, attempt.Error, attempt.DurationMs,
+		).Sql()
+
+		if _, err := tx.Exec(ctx, query, args...); err != nil {
+			r.log.Error("failed to save webhook attempt", zap.Error(err))
+			return err
+		}
+
+		query, args = deliveries.UPDATE(
+			deliveries.Status, deliveries.LastResponseCode, deliveries.LastError,
+			deliveries.NextAttemptAt, deliveries.DeliveredAt,
+		).SET(
+			string(status), attempt.ResponseCode, attempt.Error,
+			postgres.LOCALTIMESTAMP().ADD(postgres.INTERVALd(retryIn)), deliveredAt,
+		).WHERE(deliveries.ID.EQ(postgres.Int(attempt.DeliveryID))).Sql()
+
+		if _, err := tx.Exec(ctx, query, args...); err != nil {
+			r.log.Error("failed to update webhook delivery", zap.Error(err))
+			return err
+		}
+
+		return nil
+	})
+}
+
+// GetWebhookDeliveries returns the latest deliveries of the subscription,
+// optionally of the status only.
+func (r *Repository) GetWebhookDeliveries(
+	ctx context.Context,
+	subscriptionID int64,
+	status models.WebhookDeliveryStatus,
+	limit int64,
+) ([]*models.WebhookDelivery, error) {
+	r.log.Debug("getting webhook deliveries")
+
+	var (
+		result     []*models.WebhookDelivery
+		deliveries = table.WebhookDeliveries
+	)
+
+	condition := deliveries.SubscriptionID.EQ(postgres.Int(subscriptionID))
+	if status != "" {
+		condition = condition.AND(deliveries.Status.EQ(postgres.String(string(status))))
+	}
+
+	query, args := deliveries.SELECT(deliveries.AllColumns).
+		WHERE(condition).
+		ORDER_BY(deliveries.ID.DESC()).
+		LIMIT(limit).Sql()
+
+	if err := pgxscan.Select(ctx, r.db, &result, query, args...); err != nil {
+		r.log.Error("failed to get webhook deliveries", zap.Error(err))
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetWebhookDelivery returns the delivery of the subscription with its attempt history.
+func (r *Repository) GetWebhookDelivery(ctx context.Context, subscriptionID, id int64) (*models.WebhookDelivery, error) {
+	r.log.Debug("getting webhook delivery")
+
+	var (
+		delivery   models.WebhookDelivery
+		deliveries = table.WebhookDeliveries
+		attempts   = table.WebhookDeliveryAttempts
+	)
+
+	query, args := deliveries.SELECT(deliveries.AllColumns).
+		WHERE(postgres.AND(
+			deliveries.ID.EQ(postgres.Int(id)),
+			deliveries.SubscriptionID.EQ(postgres.Int(subscriptionID)),
+		)).Sql()
+
+	if err := pgxscan.Get(ctx, r.db, &delivery, query, args...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrWebhookDeliveryNotFound
+		}
+		r.log.Error("failed to get webhook delivery", zap.Error(err))
+		return nil, err
+	}
+
+	query, args = attempts.SELECT(attempts.AllColumns).
+		WHERE(attempts.DeliveryID.EQ(postgres.Int(id))).
+		ORDER_BY(attempts.ID).Sql()
+
+	if err := pgxscan.Select(ctx, r.db, &delivery.History, query, args...); err != nil {
+		r.log.Error("failed to get webhook attempts", zap.Error(err))
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+// RedeliverWebhookDelivery schedules the delivery to be sent again right away
+// with a fresh retry budget.
+func (r *Repository) RedeliverWebhookDelivery(ctx context.Context, subscriptionID, id int64) error {
+	r.log.Debug("redelivering webhook delivery")
+
+	deliveries := table.WebhookDeliveries
+	query, args := deliveries.UPDATE(deliveries.Status, deliveries.Attempts, deliveries.NextAttemptAt).
+		SET(string(models.WebhookPending), 0, postgres.LOCALTIMESTAMP()).
+		WHERE(postgres.AND(
+			deliveries.ID.EQ(postgres.Int(id)),
+			deliveries.SubscriptionID.EQ(postgres.Int(subscriptionID)),
+		)).Sql()
+
+	tag, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		r.log.Error("failed to redeliver webhook delivery", zap.Error(err))
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrWebhookDeliveryNotFound
+	}
+
+	return nil
+}
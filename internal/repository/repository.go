@@ -5,9 +5,23 @@ import "errors"
 var (
 	ErrUserAlreadyExists       = errors.New("user already exists")
 	ErrEnrollmentAlreadyExists = errors.New("enrollment already exists")
+	ErrEnrollmentNotFound      = errors.New("enrollment not found")
 	ErrUserNotFound            = errors.New("user not found")
 	ErrPositionsNotFound       = errors.New("employees positions not found")
 	ErrCourseNotFound          = errors.New("course not found")
+	ErrEventNotFound           = errors.New("event not found")
+	ErrCourseNotPublished      = errors.New("course is not open for enrollment")
+	ErrCourseStatusChanged     = errors.New("course status was changed concurrently")
+	ErrCourseHasUnpaidInvoices = errors.New("course has unpaid invoices, archive it with force")
+	ErrInvoiceNotPayable       = errors.New("invoice not found or already paid")
 	ErrEventsRequired          = errors.New("events required")
 	ErrEmployeesRequired       = errors.New("employees required")
+	ErrVersionMismatch         = errors.New("entity version does not match")
+	ErrTelegramNotLinked       = errors.New("telegram is not linked")
+	ErrWebhookNotFound         = errors.New("webhook subscription not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrPreferencesNotFound     = errors.New("notification preferences not found")
+	ErrTemplateNotFound        = errors.New("notification template not found")
+	ErrDeliveryNotFound        = errors.New("notification delivery not found")
+	ErrInboxItemNotFound       = errors.New("inbox notification not found")
 )
@@ -25,6 +25,8 @@ func RoleAccess(roleManager rbac.RoleManager, secretKey string) gin.HandlerFunc
 			return
 		}
 
+		jwt.ContextWithUserClaims(c, userClaims)
+
 		granted, err := roleManager.IsGranted(models.Role(userClaims.Role).String(),
 			c.Request.Method, routeByFullPath(c.FullPath()))
 		if err != nil {
@@ -42,5 +44,5 @@ func RoleAccess(roleManager rbac.RoleManager, secretKey string) gin.HandlerFunc
 }
 
 func routeByFullPath(fullPth string) string {
-	return strings.Trim(fullPth, models.APIPath)
+	return strings.TrimPrefix(fullPth, "/"+models.APIPath)
 }
@@ -0,0 +1,79 @@
+package v1
+
+import (
+	"context"
+	"dussh/internal/domain/models"
+	"dussh/internal/domain/response"
+	"dussh/internal/services/audit"
+	"dussh/pkg/validator"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+const defaultListLimit = 100
+
+type Service interface {
+	List(ctx context.Context, filter *models.AuditFilter) ([]*models.AuditEntry, error)
+}
+
+func NewAuditAPI(service Service, log *zap.Logger) audit.Api {
+	return &auditAPI{
+		svc: service,
+		log: log.Named("audit.api"),
+	}
+}
+
+type auditAPI struct {
+	svc Service
+
+	log *zap.Logger
+}
+
+type ListRequest struct {
+	ActorID    *int64     `form:"actor_id"`
+	EntityType string     `form:"entity_type"`
+	EntityID   *int64     `form:"entity_id"`
+	From       *time.Time `form:"from"`
+	To         *time.Time `form:"to"`
+	Limit      int64      `form:"limit" validate:"omitempty,min=1,max=1000"`
+	Offset     int64      `form:"offset" validate:"omitempty,min=0"`
+}
+
+func (a *auditAPI) List(c *gin.Context) {
+	var req ListRequest
+	if err := c.BindQuery(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	if validateErrors := validator.StructValidate(req); validateErrors != nil {
+		response.BadRequest(c, validateErrors)
+		return
+	}
+
+	if req.Limit == 0 {
+		req.Limit = defaultListLimit
+	}
+
+	entries, err := a.svc.List(c, &models.AuditFilter{
+		ActorID:    req.ActorID,
+		EntityType: req.EntityType,
+		EntityID:   req.EntityID,
+		From:       req.From,
+		To:         req.To,
+		Limit:      req.Limit,
+		Offset:     req.Offset,
+	})
+	if err != nil {
+		response.InternalError(c, err)
+		return
+	}
+
+	response.New(
+		http.StatusOK,
+		"audit log received successfully",
+		response.WithValues(map[string]any{"entries": entries}),
+	).OK(c)
+}
@@ -0,0 +1,49 @@
+//go:generate go run /home/dmitry/dussh/pkg/rbac/rolegen
+package audit
+
+import (
+	"context"
+	"dussh/internal/domain/models"
+	rbacmiddleware "dussh/internal/role/middleware"
+	"dussh/pkg/rbac"
+	"github.com/gin-gonic/gin"
+)
+
+type Api interface {
+	List(c *gin.Context)
+}
+
+// Recorder records mutating operations performed on domain entities.
+type Recorder interface {
+	Record(
+		ctx context.Context,
+		action models.AuditAction,
+		entityType string,
+		entityID int64,
+		before, after any,
+	)
+}
+
+func InitRoutes(
+	routeGroup *gin.RouterGroup,
+	api Api,
+	roleManager rbac.RoleManager,
+	secretKey string,
+) {
+	//rolegen:routes
+	var routes = []models.Route{
+		{
+			Method: "GET",
+			Path:   "audit",
+			Role:   "admin",
+			Handlers: []gin.HandlerFunc{
+				rbacmiddleware.RoleAccess(roleManager, secretKey),
+				api.List,
+			},
+		},
+	}
+
+	for _, r := range routes {
+		routeGroup.Handle(r.Method, r.Path, r.Handlers...)
+	}
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"dussh/internal/domain/models"
+	"dussh/internal/services/audit"
+	auditv1 "dussh/internal/services/audit/api/v1"
+	"dussh/pkg/jwt"
+	"dussh/pkg/requestid"
+	"encoding/json"
+	"go.uber.org/zap"
+	"reflect"
+)
+
+type Repository interface {
+	SaveAuditEntry(ctx context.Context, e *models.AuditEntry) error
+	GetAuditEntries(ctx context.Context, f *models.AuditFilter) ([]*models.AuditEntry, error)
+}
+
+type Service interface {
+	auditv1.Service
+	audit.Recorder
+}
+
+func NewAuditService(
+	repository Repository,
+	log *zap.Logger,
+) Service {
+	return &auditService{
+		repo: repository,
+		log:  log.Named("audit.service"),
+	}
+}
+
+type auditService struct {
+	repo Repository
+
+	log *zap.Logger
+}
+
+func (s *auditService) List(ctx context.Context, filter *models.AuditFilter) ([]*models.AuditEntry, error) {
+	return s.repo.GetAuditEntries(ctx, filter)
+}
+
+// Record saves an audit entry for the entity with the actor and request ID
+// taken from ctx. The operation being audited has already happened, so
+// failures are logged instead of being returned.
+func (s *auditService) Record(
+	ctx context.Context,
+	action models.AuditAction,
+	entityType string,
+	entityID int64,
+	before, after any,
+) {
+	log := s.log.With(
+		zap.String("action", string(action)),
+		zap.String("entity_type", entityType),
+		zap.Int64("entity_id", entityID),
+	)
+
+	entry := &models.AuditEntry{
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		RequestID:  requestid.FromContext(ctx),
+	}
+
+	if claims, ok := jwt.UserClaimsFromContext(ctx); ok {
+		entry.ActorID = &claims.ID
+		entry.ActorEmail = claims.Email
+	}
+
+	beforeFields, beforeRaw, err := snapshot(before)
+	if err != nil {
+		log.Error("failed to marshal audit snapshot", zap.Error(err))
+		return
+	}
+
+	afterFields, afterRaw, err := snapshot(after)
+	if err != nil {
+		log.Error("failed to marshal audit snapshot", zap.Error(err))
+		return
+	}
+
+	entry.Before, entry.After = beforeRaw, afterRaw
+	if changes := diff(beforeFields, afterFields); len(changes) > 0 {
+		if entry.Diff, err = json.Marshal(changes); err != nil {
+			log.Error("failed to marshal audit diff", zap.Error(err))
+			return
+		}
+	}
+
+	if err := s.repo.SaveAuditEntry(ctx, entry); err != nil {
+		log.Error("failed to record audit entry", zap.Error(err))
+	}
+}
+
+type fieldChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// snapshot returns v as JSON and as a map of its top level fields.
+func snapshot(v any) (map[string]any, json.RawMessage, error) {
+	if v == nil {
+		return nil, nil, nil
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, nil, err
+	}
+
+	if fields == nil {
+		return nil, nil, nil
+	}
+
+	return fields, raw, nil
+}
+
+// diff returns the top level fields that differ between before and after.
+func diff(before, after map[string]any) map[string]fieldChange {
+	changes := make(map[string]fieldChange)
+
+	for k, b := range before {
+		if a, ok := after[k]; !ok || !reflect.DeepEqual(a, b) {
+			changes[k] = fieldChange{Before: b, After: after[k]}
+		}
+	}
+
+	for k, a := range after {
+		if _, ok := before[k]; !ok {
+			changes[k] = fieldChange{After: a}
+		}
+	}
+
+	return changes
+}
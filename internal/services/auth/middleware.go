@@ -21,6 +21,27 @@ func JWTAuth(secretKey string) gin.HandlerFunc {
 	}
 }
 
+// Identify stores the claims of a valid bearer token in the context so
+// handlers and services know who performs the request. Requests without a
+// valid token pass through anonymously.
+func Identify(secretKey string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+
+		token, err := JWTHandler(c, secretKey)
+		if err == nil && token != nil {
+			if claims, err := jwt.RetrieveJwtToken(token); err == nil {
+				jwt.ContextWithUserClaims(c, claims)
+			}
+		}
+
+		c.Next()
+	}
+}
+
 func JWTHandler(c *gin.Context, secretKey string) (*gojwt.Token, error) {
 	token, err := jwt.ExtractBearerToken(c.GetHeader("Authorization"))
 	if err != nil || token == "" {
@@ -4,6 +4,7 @@ import (
 	"dussh/internal/cache/redis"
 	"dussh/internal/domain/models"
 	"dussh/internal/repository"
+	"dussh/internal/services/audit"
 	"dussh/internal/services/auth/api/v1"
 	"dussh/internal/utils/bytesconv"
 	"dussh/pkg/jwt"
@@ -31,12 +32,14 @@ func NewAuthService(
 	repository AuthRepository,
 	manager jwt.TokenManager,
 	cache redis.Cache,
+	auditor audit.Recorder,
 	log *zap.Logger,
 ) v1.AuthService {
 	return &authService{
 		repo:         repository,
 		tokenManager: manager,
 		cache:        cache,
+		audit:        auditor,
 		log:          log.Named("auth.service"),
 	}
 }
@@ -45,6 +48,7 @@ type authService struct {
 	repo         AuthRepository
 	tokenManager jwt.TokenManager
 	cache        redis.Cache
+	audit        audit.Recorder
 
 	log *zap.Logger
 }
@@ -69,6 +73,9 @@ func (as *authService) Register(ctx context.Context, user *models.User) (int64,
 		return 0, err
 	}
 
+	user.ID = id
+	as.audit.Record(ctx, models.AuditCreate, models.EntityUser, id, nil, user.Info())
+
 	return id, nil
 }
 
@@ -106,9 +113,16 @@ func (as *authService) Login(ctx context.Context, email, password string) (*jwt.
 		return nil, err
 	}
 
+	as.audit.Record(ctx, models.AuditCreate, models.EntitySession, user.ID, nil, sessionInfo(user.ID))
 	return tokenPair, nil
 }
 
+// sessionInfo is the audit snapshot of a user session. Tokens are never
+// written to the audit log.
+func sessionInfo(userID int64) map[string]any {
+	return map[string]any{"user_id": userID}
+}
+
 func (as *authService) createJWTTokenPair(user *models.User) (*jwt.TokenPair, error) {
 	accessToken, err := as.tokenManager.NewAccessToken(user.ID, user.Email, int(user.Role))
 	if err != nil {
@@ -160,6 +174,7 @@ func (as *authService) RefreshToken(ctx context.Context, token string, userID in
 		return nil, err
 	}
 
+	as.audit.Record(ctx, models.AuditUpdate, models.EntitySession, userID, sessionInfo(userID), sessionInfo(userID))
 	return tokenPair, nil
 }
 
@@ -182,5 +197,6 @@ func (as *authService) Logout(ctx context.Context, token string, userID int64) e
 		return err
 	}
 
+	as.audit.Record(ctx, models.AuditDelete, models.EntitySession, userID, sessionInfo(userID), nil)
 	return nil
 }
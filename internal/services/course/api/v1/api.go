@@ -5,8 +5,10 @@ import (
 	domainerrors "dussh/internal/domain/errors"
 	"dussh/internal/domain/models"
 	"dussh/internal/domain/response"
+	"dussh/internal/repository"
 	"dussh/internal/services/course"
 	"dussh/pkg/validator"
+	"errors"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 	"net/http"
@@ -19,12 +21,20 @@ type Service interface {
 	CreateEnrollment(ctx context.Context, courseID, userID int64) (int64, error)
 	AddEvents(ctx context.Context, courseID int64, events []*models.Event) error
 	AddEmployees(ctx context.Context, courseID int64, employees []int64) error
-	Update(ctx context.Context, id int64, crs *models.Course) error
-	Delete(ctx context.Context, id int64) error
+	Update(ctx context.Context, id int64, crs *models.Course, version int64) (int64, error)
+	Delete(ctx context.Context, id int64, force bool, version int64) error
+	Publish(ctx context.Context, id int64) error
+	CloseEnrollment(ctx context.Context, id int64) error
+	Archive(ctx context.Context, id int64, force bool, version int64) error
 	DeleteEvent(ctx context.Context, courseID, eventID int64) error
 	DeleteEmployee(ctx context.Context, courseID, employeeID int64) error
 	DeleteEnrollment(ctx context.Context, enrollmentID int64) error
 	List(ctx context.Context) ([]*models.Course, error)
+	// IssueInvoice issues an invoice to the user enrolled in the course, the
+	// amount defaults to the monthly subscription cost if nil.
+	IssueInvoice(ctx context.Context, courseID, userID int64, amount *float64) (int64, error)
+	PayInvoice(ctx context.Context, courseID, invoiceID int64) error
+	Invoices(ctx context.Context, courseID int64) ([]*models.Invoice, error)
 }
 
 func NewCourseAPI(service Service, log *zap.Logger) course.Api {
@@ -49,10 +59,11 @@ func (ca *courseAPI) Get(c *gin.Context) {
 
 	crs, err := ca.svc.Get(c, courseID)
 	if err != nil {
-		response.InternalError(c, err)
+		statusError(c, err)
 		return
 	}
 
+	response.SetETag(c, crs.Version)
 	response.New(
 		http.StatusOK,
 		"get course successfully",
@@ -165,6 +176,12 @@ func (ca *courseAPI) Update(c *gin.Context) {
 		return
 	}
 
+	version, ok := response.IfMatch(c)
+	if !ok {
+		response.PreconditionFailed(c, repository.ErrVersionMismatch)
+		return
+	}
+
 	var req UpdateRequest
 	if err := c.BindJSON(&req); err != nil {
 		response.BadRequest(c, err)
@@ -182,11 +199,13 @@ func (ca *courseAPI) Update(c *gin.Context) {
 		Events:                  req.Events,
 	}
 
-	if err := ca.svc.Update(c, courseID, crs); err != nil {
-		response.InternalError(c, err)
+	newVersion, err := ca.svc.Update(c, courseID, crs, version)
+	if err != nil {
+		statusError(c, err)
 		return
 	}
 
+	response.SetETag(c, newVersion)
 	response.New(
 		http.StatusOK,
 		"update course successfully",
@@ -200,8 +219,14 @@ func (ca *courseAPI) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := ca.svc.Delete(c, courseID); err != nil {
-		response.InternalError(c, err)
+	version, ok := response.IfMatch(c)
+	if !ok {
+		response.PreconditionFailed(c, repository.ErrVersionMismatch)
+		return
+	}
+
+	if err := ca.svc.Delete(c, courseID, c.Query("force") == "true", version); err != nil {
+		statusError(c, err)
 		return
 	}
 
@@ -211,6 +236,87 @@ func (ca *courseAPI) Delete(c *gin.Context) {
 	).OK(c)
 }
 
+func (ca *courseAPI) Publish(c *gin.Context) {
+	courseID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, domainerrors.ErrInvalidURLPattern)
+		return
+	}
+
+	if err := ca.svc.Publish(c, courseID); err != nil {
+		statusError(c, err)
+		return
+	}
+
+	response.New(
+		http.StatusOK,
+		"publish course successfully",
+	).OK(c)
+}
+
+func (ca *courseAPI) CloseEnrollment(c *gin.Context) {
+	courseID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, domainerrors.ErrInvalidURLPattern)
+		return
+	}
+
+	if err := ca.svc.CloseEnrollment(c, courseID); err != nil {
+		statusError(c, err)
+		return
+	}
+
+	response.New(
+		http.StatusOK,
+		"close course enrollment successfully",
+	).OK(c)
+}
+
+func (ca *courseAPI) Archive(c *gin.Context) {
+	courseID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, domainerrors.ErrInvalidURLPattern)
+		return
+	}
+
+	version, ok := response.IfMatch(c)
+	if !ok {
+		response.PreconditionFailed(c, repository.ErrVersionMismatch)
+		return
+	}
+
+	if err := ca.svc.Archive(c, courseID, c.Query("force") == "true", version); err != nil {
+		statusError(c, err)
+		return
+	}
+
+	response.New(
+		http.StatusOK,
+		"archive course successfully",
+	).OK(c)
+}
+
+// statusError writes err with the status code matching the course error.
+func statusError(c *gin.Context, err error) {
+	r := response.New(http.StatusInternalServerError, err.Error())
+	switch {
+	case errors.Is(err, repository.ErrCourseNotFound),
+		errors.Is(err, repository.ErrEventNotFound),
+		errors.Is(err, repository.ErrEnrollmentNotFound):
+		r.Code = http.StatusNotFound
+	case errors.Is(err, repository.ErrVersionMismatch):
+		r.Code = http.StatusPreconditionFailed
+	case errors.Is(err, repository.ErrCourseNotPublished),
+		errors.Is(err, repository.ErrCourseStatusChanged),
+		errors.Is(err, domainerrors.ErrInvalidStatusTransition),
+		errors.Is(err, repository.ErrCourseHasUnpaidInvoices),
+		errors.Is(err, repository.ErrInvoiceNotPayable):
+		r.Code = http.StatusConflict
+	}
+
+	r.Error(c)
+}
+
 func (ca *courseAPI) DeleteEvent(c *gin.Context) {
 	courseID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
@@ -283,7 +389,7 @@ func (ca *courseAPI) CreateEnrollment(c *gin.Context) {
 
 	enrollmentID, err := ca.svc.CreateEnrollment(c, courseID, req.UserID)
 	if err != nil {
-		response.InternalError(c, err)
+		statusError(c, err)
 		return
 	}
 
@@ -327,3 +433,83 @@ func (ca *courseAPI) List(c *gin.Context) {
 		}),
 	).OK(c)
 }
+
+type IssueInvoiceRequest struct {
+	UserID int64    `json:"user_id" validate:"required"`
+	Amount *float64 `json:"amount,omitempty" validate:"omitempty,min=0"`
+}
+
+func (ca *courseAPI) IssueInvoice(c *gin.Context) {
+	courseID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, domainerrors.ErrInvalidURLPattern)
+		return
+	}
+
+	var req IssueInvoiceRequest
+	if err := c.BindJSON(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	if validateErrors := validator.StructValidate(req); validateErrors != nil {
+		response.BadRequest(c, validateErrors)
+		return
+	}
+
+	invoiceID, err := ca.svc.IssueInvoice(c, courseID, req.UserID, req.Amount)
+	if err != nil {
+		statusError(c, err)
+		return
+	}
+
+	response.New(
+		http.StatusOK,
+		"issue invoice successfully",
+		response.WithValues(map[string]any{"invoice_id": invoiceID}),
+	).OK(c)
+}
+
+func (ca *courseAPI) PayInvoice(c *gin.Context) {
+	courseID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, domainerrors.ErrInvalidURLPattern)
+		return
+	}
+
+	invoiceID, err := strconv.ParseInt(c.Param("invoice-id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, domainerrors.ErrInvalidURLPattern)
+		return
+	}
+
+	if err := ca.svc.PayInvoice(c, courseID, invoiceID); err != nil {
+		statusError(c, err)
+		return
+	}
+
+	response.New(
+		http.StatusOK,
+		"pay invoice successfully",
+	).OK(c)
+}
+
+func (ca *courseAPI) Invoices(c *gin.Context) {
+	courseID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, domainerrors.ErrInvalidURLPattern)
+		return
+	}
+
+	invoices, err := ca.svc.Invoices(c, courseID)
+	if err != nil {
+		statusError(c, err)
+		return
+	}
+
+	response.New(
+		http.StatusOK,
+		"get course invoices successfully",
+		response.WithValues(map[string]any{"invoices": invoices}),
+	).OK(c)
+}
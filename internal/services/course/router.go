@@ -15,10 +15,16 @@ type Api interface {
 	AddEmployees(c *gin.Context)
 	Update(c *gin.Context)
 	Delete(c *gin.Context)
+	Publish(c *gin.Context)
+	CloseEnrollment(c *gin.Context)
+	Archive(c *gin.Context)
 	DeleteEvent(c *gin.Context)
 	DeleteEmployee(c *gin.Context)
 	DeleteEnrollment(c *gin.Context)
 	List(c *gin.Context)
+	IssueInvoice(c *gin.Context)
+	PayInvoice(c *gin.Context)
+	Invoices(c *gin.Context)
 }
 
 func InitRoutes(
@@ -26,6 +32,7 @@ func InitRoutes(
 	api Api,
 	roleManager rbac.RoleManager,
 	secretKey string,
+	idempotent gin.HandlerFunc,
 ) {
 	//rolegen:routes
 	var routes = []models.Route{
@@ -54,13 +61,31 @@ func InitRoutes(
 		{
 			Method:   "POST",
 			Path:     "courses/:id/enrollments",
-			Handlers: []gin.HandlerFunc{api.CreateEnrollment},
+			Handlers: []gin.HandlerFunc{idempotent, api.CreateEnrollment},
 		},
 		{
 			Method:   "PATCH",
 			Path:     "courses/:id",
 			Handlers: []gin.HandlerFunc{api.Update},
 		},
+		{
+			Method:   "POST",
+			Path:     "courses/:id/publish",
+			Role:     "employee",
+			Handlers: []gin.HandlerFunc{api.Publish},
+		},
+		{
+			Method:   "POST",
+			Path:     "courses/:id/close-enrollment",
+			Role:     "employee",
+			Handlers: []gin.HandlerFunc{api.CloseEnrollment},
+		},
+		{
+			Method:   "POST",
+			Path:     "courses/:id/archive",
+			Role:     "admin",
+			Handlers: []gin.HandlerFunc{api.Archive},
+		},
 		{
 			Method:   "DELETE",
 			Path:     "courses/:id",
@@ -86,6 +111,24 @@ func InitRoutes(
 			Path:     "courses",
 			Handlers: []gin.HandlerFunc{api.List},
 		},
+		{
+			Method:   "GET",
+			Path:     "courses/:id/invoices",
+			Role:     "admin",
+			Handlers: []gin.HandlerFunc{api.Invoices},
+		},
+		{
+			Method:   "POST",
+			Path:     "courses/:id/invoices",
+			Role:     "admin",
+			Handlers: []gin.HandlerFunc{idempotent, api.IssueInvoice},
+		},
+		{
+			Method:   "POST",
+			Path:     "courses/:id/invoices/:invoice-id/pay",
+			Role:     "admin",
+			Handlers: []gin.HandlerFunc{api.PayInvoice},
+		},
 	}
 
 	for _, r := range routes {
@@ -2,9 +2,10 @@ package service
 
 import (
 	"context"
-	"dussh/internal/broker/rabbit/publisher"
 	"dussh/internal/cache/redis"
+	domainerrors "dussh/internal/domain/errors"
 	"dussh/internal/domain/models"
+	"dussh/internal/services/audit"
 	coursev1 "dussh/internal/services/course/api/v1"
 	"errors"
 	"go.uber.org/zap"
@@ -16,25 +17,30 @@ type Repository interface {
 	SaveEvents(ctx context.Context, courseID int64, events []*models.Event) error
 	SaveEmployees(ctx context.Context, courseID int64, employees []int64) error
 	SaveEnrollment(ctx context.Context, courseID, userID int64) (int64, error)
-	UpdateCourse(ctx context.Context, id int64, crs *models.Course) error
-	DeleteCourse(ctx context.Context, id int64) error
+	GetEnrollment(ctx context.Context, enrollmentID int64) (*models.Enrollment, error)
+	UpdateCourse(ctx context.Context, id int64, crs *models.Course, version int64) (int64, error)
+	GetCourseStatus(ctx context.Context, id int64) (models.CourseStatus, error)
+	UpdateCourseStatus(ctx context.Context, id int64, from, next models.CourseStatus, version int64, force bool) error
 	DeleteEvent(ctx context.Context, courseID, eventID int64) error
 	DeleteEmployee(ctx context.Context, courseID, employeeID int64) error
 	DeleteEnrollment(ctx context.Context, enrollmentID int64) error
 	CheckCountEvents(ctx context.Context, courseID int64) (int, error)
 	CheckCountEmployees(ctx context.Context, courseID int64) (int, error)
 	GetCourses(ctx context.Context) ([]*models.Course, error)
+	SaveInvoice(ctx context.Context, inv *models.Invoice) (int64, error)
+	PayInvoice(ctx context.Context, courseID, id int64) error
+	GetInvoices(ctx context.Context, courseID int64) ([]*models.Invoice, error)
 }
 
 func NewCourseService(
 	repository Repository,
-	enrollmentBroker publisher.Publisher[models.EnrollmentEvent],
+	auditor audit.Recorder,
 	log *zap.Logger,
 ) coursev1.Service {
 	return &courseService{
-		repo:             repository,
-		enrollmentBroker: enrollmentBroker,
-		log:              log.Named("course.service"),
+		repo:  repository,
+		audit: auditor,
+		log:   log.Named("course.service"),
 	}
 }
 
@@ -46,8 +52,7 @@ var (
 type courseService struct {
 	repo  Repository
 	cache redis.Cache
-	// TODO добавить отправку в очередь событий
-	enrollmentBroker publisher.Publisher[models.EnrollmentEvent]
+	audit audit.Recorder
 
 	log *zap.Logger
 }
@@ -57,39 +62,125 @@ func (c *courseService) Get(ctx context.Context, courseID int64) (*models.Course
 }
 
 func (c *courseService) Create(ctx context.Context, crs *models.Course) (int64, error) {
-	return c.repo.SaveCourse(ctx, crs)
+	id, err := c.repo.SaveCourse(ctx, crs)
+	if err != nil {
+		return 0, err
+	}
+
+	crs.ID, crs.Status = id, models.Draft
+	c.audit.Record(ctx, models.AuditCreate, models.EntityCourse, id, nil, crs)
+
+	return id, nil
 }
 
 func (c *courseService) CreateEnrollment(ctx context.Context, courseID, userID int64) (int64, error) {
 	enrollmentID, err := c.repo.SaveEnrollment(ctx, courseID, userID)
-	if err == nil && enrollmentID != 0 {
-		err := c.enrollmentBroker.Publish(ctx, "notification", models.EnrollmentEvent{
-			CourseID: courseID,
-			UserID:   userID,
-		})
-		if err != nil {
-			c.log.Error("failed to publish enrollment event to notification exchange")
-			return 0, err
-		}
+	if err != nil {
+		return 0, err
 	}
 
+	c.audit.Record(ctx, models.AuditCreate, models.EntityEnrollment, enrollmentID, nil, &models.Enrollment{
+		ID:       enrollmentID,
+		CourseID: courseID,
+		UserID:   userID,
+	})
+
 	return enrollmentID, nil
 }
 
 func (c *courseService) AddEvents(ctx context.Context, courseID int64, events []*models.Event) error {
-	return c.repo.SaveEvents(ctx, courseID, events)
+	if err := c.repo.SaveEvents(ctx, courseID, events); err != nil {
+		return err
+	}
+
+	c.audit.Record(ctx, models.AuditUpdate, models.EntityCourse, courseID, nil, map[string]any{
+		"added_events": events,
+	})
+	return nil
 }
 
 func (c *courseService) AddEmployees(ctx context.Context, courseID int64, employees []int64) error {
-	return c.repo.SaveEmployees(ctx, courseID, employees)
+	if err := c.repo.SaveEmployees(ctx, courseID, employees); err != nil {
+		return err
+	}
+
+	c.audit.Record(ctx, models.AuditUpdate, models.EntityCourse, courseID, nil, map[string]any{
+		"added_employees": employees,
+	})
+	return nil
+}
+
+func (c *courseService) Update(ctx context.Context, id int64, crs *models.Course, version int64) (int64, error) {
+	before, err := c.repo.GetCourse(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	newVersion, err := c.repo.UpdateCourse(ctx, id, crs, version)
+	if err != nil {
+		return 0, err
+	}
+
+	after, err := c.repo.GetCourse(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	c.audit.Record(ctx, models.AuditUpdate, models.EntityCourse, id, before, after)
+	return newVersion, nil
 }
 
-func (c *courseService) Update(ctx context.Context, id int64, crs *models.Course) error {
-	return c.repo.UpdateCourse(ctx, id, crs)
+// Delete archives the course instead of removing it, so its events and
+// enrollments stay available for reports.
+func (c *courseService) Delete(ctx context.Context, id int64, force bool, version int64) error {
+	return c.Archive(ctx, id, force, version)
 }
 
-func (c *courseService) Delete(ctx context.Context, id int64) error {
-	return c.repo.DeleteCourse(ctx, id)
+func (c *courseService) Publish(ctx context.Context, id int64) error {
+	return c.changeStatus(ctx, id, models.Published, 0, false)
+}
+
+func (c *courseService) CloseEnrollment(ctx context.Context, id int64) error {
+	return c.changeStatus(ctx, id, models.EnrollmentClosed, 0, false)
+}
+
+// Archive moves the course to the archived status. A course that still has
+// unpaid invoices is archived only when force is set. A non-zero version must
+// match the current course version.
+func (c *courseService) Archive(ctx context.Context, id int64, force bool, version int64) error {
+	return c.changeStatus(ctx, id, models.Archived, version, force)
+}
+
+func (c *courseService) changeStatus(
+	ctx context.Context,
+	id int64,
+	next models.CourseStatus,
+	version int64,
+	force bool,
+) error {
+	current, err := c.repo.GetCourseStatus(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !current.CanTransitionTo(next) {
+		return domainerrors.ErrInvalidStatusTransition
+	}
+
+	if err := c.repo.UpdateCourseStatus(ctx, id, current, next, version, force); err != nil {
+		return err
+	}
+
+	action := models.AuditUpdate
+	if next == models.Archived {
+		action = models.AuditDelete
+	}
+	c.audit.Record(ctx, action, models.EntityCourse, id,
+		map[string]any{"status": current},
+		map[string]any{"status": next},
+	)
+
+	return nil
 }
 
 func (c *courseService) DeleteEvent(ctx context.Context, courseID, eventID int64) error {
@@ -102,7 +193,14 @@ func (c *courseService) DeleteEvent(ctx context.Context, courseID, eventID int64
 		return ErrMustBeAtLeastOneEvent
 	}
 
-	return c.repo.DeleteEvent(ctx, courseID, eventID)
+	if err := c.repo.DeleteEvent(ctx, courseID, eventID); err != nil {
+		return err
+	}
+
+	c.audit.Record(ctx, models.AuditUpdate, models.EntityCourse, courseID, map[string]any{
+		"removed_event_id": eventID,
+	}, nil)
+	return nil
 }
 
 func (c *courseService) DeleteEmployee(ctx context.Context, courseID, employeeID int64) error {
@@ -115,13 +213,70 @@ func (c *courseService) DeleteEmployee(ctx context.Context, courseID, employeeID
 		return ErrMustBeAtLeastOneBindEmployee
 	}
 
-	return c.repo.DeleteEmployee(ctx, courseID, employeeID)
+	if err := c.repo.DeleteEmployee(ctx, courseID, employeeID); err != nil {
+		return err
+	}
+
+	c.audit.Record(ctx, models.AuditUpdate, models.EntityCourse, courseID, map[string]any{
+		"removed_employee_id": employeeID,
+	}, nil)
+	return nil
 }
 
 func (c *courseService) DeleteEnrollment(ctx context.Context, enrollmentID int64) error {
-	return c.repo.DeleteEnrollment(ctx, enrollmentID)
+	before, err := c.repo.GetEnrollment(ctx, enrollmentID)
+	if err != nil {
+		return err
+	}
+
+	if err := c.repo.DeleteEnrollment(ctx, enrollmentID); err != nil {
+		return err
+	}
+
+	c.audit.Record(ctx, models.AuditDelete, models.EntityEnrollment, enrollmentID, before, nil)
+	return nil
 }
 
 func (c *courseService) List(ctx context.Context) ([]*models.Course, error) {
 	return c.repo.GetCourses(ctx)
 }
+
+// IssueInvoice issues an invoice to the user enrolled in the course, the
+// amount defaults to the monthly subscription cost of the course.
+func (c *courseService) IssueInvoice(ctx context.Context, courseID, userID int64, amount *float64) (int64, error) {
+	if amount == nil {
+		crs, err := c.repo.GetCourse(ctx, courseID)
+		if err != nil {
+			return 0, err
+		}
+		amount = crs.MonthlySubscriptionCost
+	}
+
+	inv := &models.Invoice{CourseID: courseID, UserID: userID}
+	if amount != nil {
+		inv.Amount = *amount
+	}
+
+	id, err := c.repo.SaveInvoice(ctx, inv)
+	if err != nil {
+		return 0, err
+	}
+
+	inv.ID = id
+	c.audit.Record(ctx, models.AuditCreate, models.EntityInvoice, id, nil, inv)
+
+	return id, nil
+}
+
+func (c *courseService) PayInvoice(ctx context.Context, courseID, invoiceID int64) error {
+	if err := c.repo.PayInvoice(ctx, courseID, invoiceID); err != nil {
+		return err
+	}
+
+	c.audit.Record(ctx, models.AuditUpdate, models.EntityInvoice, invoiceID, nil, map[string]any{"paid": true})
+	return nil
+}
+
+func (c *courseService) Invoices(ctx context.Context, courseID int64) ([]*models.Invoice, error) {
+	return c.repo.GetInvoices(ctx, courseID)
+}
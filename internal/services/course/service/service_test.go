@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"dussh/internal/domain/models"
+	"dussh/internal/repository"
+	"errors"
+	"go.uber.org/zap"
+	"testing"
+)
+
+// fakeRepo archives courses the way the pgsql repository does, a course with
+// unpaid invoices is archived only when forced.
+type fakeRepo struct {
+	Repository
+	status   models.CourseStatus
+	cost     float64
+	invoices map[int64]*models.Invoice
+}
+
+func (r *fakeRepo) GetCourse(_ context.Context, id int64) (*models.Course, error) {
+	return &models.Course{ID: id, MonthlySubscriptionCost: &r.cost, Status: r.status}, nil
+}
+
+func (r *fakeRepo) GetCourseStatus(context.Context, int64) (models.CourseStatus, error) {
+	return r.status, nil
+}
+
+func (r *fakeRepo) UpdateCourseStatus(
+	_ context.Context,
+	_ int64,
+	from, next models.CourseStatus,
+	_ int64,
+	force bool,
+) error {
+	if r.status != from {
+		return repository.ErrCourseStatusChanged
+	}
+
+	if next == models.Archived && !force {
+		for _, inv := range r.invoices {
+			if inv.PaidAt == nil {
+				return repository.ErrCourseHasUnpaidInvoices
+			}
+		}
+	}
+
+	r.status = next
+	return nil
+}
+
+func (r *fakeRepo) SaveInvoice(_ context.Context, inv *models.Invoice) (int64, error) {
+	id := int64(len(r.invoices) + 1)
+	r.invoices[id] = inv
+	return id, nil
+}
+
+func (r *fakeRepo) PayInvoice(_ context.Context, _ int64, id int64) error {
+	inv, ok := r.invoices[id]
+	if !ok || inv.PaidAt != nil {
+		return repository.ErrInvoiceNotPayable
+	}
+
+	paidAt := inv.IssuedAt
+	inv.PaidAt = &paidAt
+	return nil
+}
+
+type fakeAuditor struct {
+	entries []models.AuditAction
+}
+
+func (a *fakeAuditor) Record(_ context.Context, action models.AuditAction, _ string, _ int64, _, _ any) {
+	a.entries = append(a.entries, action)
+}
+
+func newTestService(status models.CourseStatus) (*courseService, *fakeRepo, *fakeAuditor) {
+	repo := &fakeRepo{status: status, cost: 3000, invoices: make(map[int64]*models.Invoice)}
+	auditor := &fakeAuditor{}
+
+	return NewCourseService(repo, auditor, zap.NewNop()).(*courseService), repo, auditor
+}
+
+func TestArchiveRefusesUnpaidInvoices(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, auditor := newTestService(models.Published)
+
+	invoiceID, err := svc.IssueInvoice(ctx, 1, 7, nil)
+	if err != nil {
+		t.Fatalf("failed to issue invoice: %v", err)
+	}
+	if got := repo.invoices[invoiceID].Amount; got != 3000 {
+		t.Errorf("invoice amount = %v, want the monthly subscription cost", got)
+	}
+
+	audited := len(auditor.entries)
+	if err := svc.Archive(ctx, 1, false, 0); !errors.Is(err, repository.ErrCourseHasUnpaidInvoices) {
+		t.Fatalf("archive with an unpaid invoice = %v, want %v", err, repository.ErrCourseHasUnpaidInvoices)
+	}
+	if repo.status != models.Published || len(auditor.entries) != audited {
+		t.Errorf("refused archive changed the course: status %s, audit %v", repo.status, auditor.entries)
+	}
+
+	if err := svc.PayInvoice(ctx, 1, invoiceID); err != nil {
+		t.Fatalf("failed to pay invoice: %v", err)
+	}
+	if err := svc.Archive(ctx, 1, false, 0); err != nil {
+		t.Fatalf("failed to archive course with paid invoices: %v", err)
+	}
+	if repo.status != models.Archived {
+		t.Errorf("status = %s, want archived", repo.status)
+	}
+}
+
+func TestArchiveForcedWithUnpaidInvoices(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, auditor := newTestService(models.Published)
+
+	amount := 1500.0
+	if _, err := svc.IssueInvoice(ctx, 1, 7, &amount); err != nil {
+		t.Fatalf("failed to issue invoice: %v", err)
+	}
+
+	if err := svc.Archive(ctx, 1, true, 0); err != nil {
+		t.Fatalf("failed to force archive: %v", err)
+	}
+	if repo.status != models.Archived {
+		t.Errorf("status = %s, want archived", repo.status)
+	}
+	if last := auditor.entries[len(auditor.entries)-1]; last != models.AuditDelete {
+		t.Errorf("last audit action = %s, want %s", last, models.AuditDelete)
+	}
+}
@@ -0,0 +1,95 @@
+package v1
+
+import (
+	"context"
+	domainerrors "dussh/internal/domain/errors"
+	"dussh/internal/domain/models"
+	"dussh/internal/domain/response"
+	"dussh/internal/services/deadletter"
+	"dussh/pkg/validator"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+const defaultListLimit = 50
+
+type Service interface {
+	List(ctx context.Context, limit int) ([]*models.DeadLetter, error)
+	Replay(ctx context.Context, id string) error
+	Discard(ctx context.Context, id string) error
+}
+
+func NewDeadLetterAPI(service Service, log *zap.Logger) deadletter.Api {
+	return &deadLetterAPI{
+		svc: service,
+		log: log.Named("deadletter.api"),
+	}
+}
+
+type deadLetterAPI struct {
+	svc Service
+
+	log *zap.Logger
+}
+
+type ListRequest struct {
+	Limit int `form:"limit" validate:"omitempty,min=1,max=500"`
+}
+
+func (a *deadLetterAPI) List(c *gin.Context) {
+	var req ListRequest
+	if err := c.BindQuery(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	if validateErrors := validator.StructValidate(req); validateErrors != nil {
+		response.BadRequest(c, validateErrors)
+		return
+	}
+
+	if req.Limit == 0 {
+		req.Limit = defaultListLimit
+	}
+
+	letters, err := a.svc.List(c, req.Limit)
+	if err != nil {
+		response.InternalError(c, err)
+		return
+	}
+
+	response.New(
+		http.StatusOK,
+		"dead-lettered messages received successfully",
+		response.WithValues(map[string]any{"messages": letters}),
+	).OK(c)
+}
+
+func (a *deadLetterAPI) Replay(c *gin.Context) {
+	if err := a.svc.Replay(c, c.Param("id")); err != nil {
+		statusError(c, err)
+		return
+	}
+
+	response.New(http.StatusOK, "message replayed successfully").OK(c)
+}
+
+func (a *deadLetterAPI) Discard(c *gin.Context) {
+	if err := a.svc.Discard(c, c.Param("id")); err != nil {
+		statusError(c, err)
+		return
+	}
+
+	response.New(http.StatusOK, "message discarded successfully").OK(c)
+}
+
+func statusError(c *gin.Context, err error) {
+	if errors.Is(err, domainerrors.ErrDeadLetterNotFound) {
+		response.New(http.StatusNotFound, err.Error()).Error(c)
+		return
+	}
+
+	response.InternalError(c, err)
+}
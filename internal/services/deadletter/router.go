@@ -0,0 +1,57 @@
+//go:generate go run /home/dmitry/dussh/pkg/rbac/rolegen
+package deadletter
+
+import (
+	"dussh/internal/domain/models"
+	rbacmiddleware "dussh/internal/role/middleware"
+	"dussh/pkg/rbac"
+	"github.com/gin-gonic/gin"
+)
+
+type Api interface {
+	List(c *gin.Context)
+	Replay(c *gin.Context)
+	Discard(c *gin.Context)
+}
+
+func InitRoutes(
+	routeGroup *gin.RouterGroup,
+	api Api,
+	roleManager rbac.RoleManager,
+	secretKey string,
+) {
+	//rolegen:routes
+	var routes = []models.Route{
+		{
+			Method: "GET",
+			Path:   "dead-letters",
+			Role:   "admin",
+			Handlers: []gin.HandlerFunc{
+				rbacmiddleware.RoleAccess(roleManager, secretKey),
+				api.List,
+			},
+		},
+		{
+			Method: "POST",
+			Path:   "dead-letters/:id/replay",
+			Role:   "admin",
+			Handlers: []gin.HandlerFunc{
+				rbacmiddleware.RoleAccess(roleManager, secretKey),
+				api.Replay,
+			},
+		},
+		{
+			Method: "DELETE",
+			Path:   "dead-letters/:id",
+			Role:   "admin",
+			Handlers: []gin.HandlerFunc{
+				rbacmiddleware.RoleAccess(roleManager, secretKey),
+				api.Discard,
+			},
+		},
+	}
+
+	for _, r := range routes {
+		routeGroup.Handle(r.Method, r.Path, r.Handlers...)
+	}
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"dussh/internal/domain/models"
+	deadletterv1 "dussh/internal/services/deadletter/api/v1"
+	"go.uber.org/zap"
+)
+
+type Store interface {
+	List(ctx context.Context, limit int) ([]*models.DeadLetter, error)
+	Replay(ctx context.Context, id string) error
+	Discard(ctx context.Context, id string) error
+}
+
+func NewDeadLetterService(store Store, log *zap.Logger) deadletterv1.Service {
+	return &deadLetterService{
+		store: store,
+		log:   log.Named("deadletter.service"),
+	}
+}
+
+type deadLetterService struct {
+	store Store
+
+	log *zap.Logger
+}
+
+func (s *deadLetterService) List(ctx context.Context, limit int) ([]*models.DeadLetter, error) {
+	return s.store.List(ctx, limit)
+}
+
+func (s *deadLetterService) Replay(ctx context.Context, id string) error {
+	if err := s.store.Replay(ctx, id); err != nil {
+		s.log.Error("failed to replay dead-lettered message", zap.String("id", id), zap.Error(err))
+		return err
+	}
+
+	s.log.Info("dead-lettered message replayed", zap.String("id", id))
+	return nil
+}
+
+func (s *deadLetterService) Discard(ctx context.Context, id string) error {
+	if err := s.store.Discard(ctx, id); err != nil {
+		s.log.Error("failed to discard dead-lettered message", zap.String("id", id), zap.Error(err))
+		return err
+	}
+
+	s.log.Info("dead-lettered message discarded", zap.String("id", id))
+	return nil
+}
@@ -0,0 +1,35 @@
+//go:generate go run /home/dmitry/dussh/pkg/rbac/rolegen
+package debug
+
+import (
+	"dussh/internal/domain/models"
+	rbacmiddleware "dussh/internal/role/middleware"
+	"dussh/pkg/rbac"
+	"expvar"
+	"github.com/gin-gonic/gin"
+)
+
+// InitRoutes registers the expvar metrics, they expose memory stats, the
+// command line and internal counters, so they are served to admins only.
+func InitRoutes(
+	routeGroup *gin.RouterGroup,
+	roleManager rbac.RoleManager,
+	secretKey string,
+) {
+	//rolegen:routes
+	var routes = []models.Route{
+		{
+			Method: "GET",
+			Path:   "debug/vars",
+			Role:   "admin",
+			Handlers: []gin.HandlerFunc{
+				rbacmiddleware.RoleAccess(roleManager, secretKey),
+				gin.WrapH(expvar.Handler()),
+			},
+		},
+	}
+
+	for _, r := range routes {
+		routeGroup.Handle(r.Method, r.Path, r.Handlers...)
+	}
+}
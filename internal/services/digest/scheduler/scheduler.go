@@ -0,0 +1,155 @@
+package scheduler
+
+import (
+	"context"
+	"dussh/internal/cache/redis"
+	"dussh/internal/config"
+	"dussh/internal/domain/models"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+var (
+	ErrSchedulerClosed = errors.New("digest scheduler closed")
+	ErrInvalidSchedule = errors.New("invalid digest schedule")
+)
+
+const lockKey = "digest:scheduler:lock"
+
+type Repository interface {
+	QueueNotificationDigests(ctx context.Context, mode models.DeliveryMode, before time.Time) (int, error)
+}
+
+// Scheduler queues the digests of the collected notifications once they are
+// due. An item is queued once, so overlapping scans are harmless.
+type Scheduler struct {
+	repo     Repository
+	cache    redis.Cache
+	cfg      config.Digest
+	schedule Schedule
+
+	log *zap.Logger
+}
+
+func New(repo Repository, cache redis.Cache, cfg config.Digest, log *zap.Logger) (*Scheduler, error) {
+	schedule, err := ParseSchedule(cfg.Hour, cfg.Weekday)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Scheduler{
+		repo:     repo,
+		cache:    cache,
+		cfg:      cfg,
+		schedule: schedule,
+		log:      log.Named("digest.scheduler"),
+	}, nil
+}
+
+// Run queues the due digests every interval until the context is canceled.
+func (s *Scheduler) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.scan(ctx); err != nil && ctx.Err() == nil {
+			s.log.Error("failed to queue digests", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ErrSchedulerClosed
+		case <-ticker.C:
+		}
+	}
+}
+
+// scan queues the digests under the lock, instances that don't get the lock
+// skip the scan.
+func (s *Scheduler) scan(ctx context.Context) error {
+	token, ok, err := s.cache.TryLock(ctx, lockKey, s.cfg.LockTTL)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		s.log.Debug("digests are queued by another instance")
+		return nil
+	}
+	defer func() {
+		if err := s.cache.Unlock(context.WithoutCancel(ctx), lockKey, token); err != nil {
+			s.log.Error("failed to release digest lock", zap.Error(err))
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.LockTTL)
+	defer cancel()
+
+	n, err := s.Queue(ctx, wallClockNow())
+	if n > 0 {
+		s.log.Info("digests queued", zap.Int("count", n))
+	}
+	return err
+}
+
+// Queue queues the digests of the items collected before the last due time
+// of each mode and returns how many were queued. now is the wall clock time
+// in UTC, as the items are stored.
+func (s *Scheduler) Queue(ctx context.Context, now time.Time) (int, error) {
+	var queued int
+	for _, mode := range []models.DeliveryMode{models.DeliveryDaily, models.DeliveryWeekly} {
+		n, err := s.repo.QueueNotificationDigests(ctx, mode, s.schedule.Last(mode, now))
+		queued += n
+		if err != nil {
+			return queued, err
+		}
+	}
+
+	return queued, nil
+}
+
+// Schedule is the time digests are due: every day at Hour for daily digests,
+// and on Weekday at Hour for weekly ones.
+type Schedule struct {
+	Hour    int
+	Weekday time.Weekday
+}
+
+// ParseSchedule checks the hour and parses the english name of the weekday.
+func ParseSchedule(hour int, weekday string) (Schedule, error) {
+	if hour < 0 || hour > 23 {
+		return Schedule{}, fmt.Errorf("%w: hour %d", ErrInvalidSchedule, hour)
+	}
+
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(d.String(), weekday) {
+			return Schedule{Hour: hour, Weekday: d}, nil
+		}
+	}
+
+	return Schedule{}, fmt.Errorf("%w: weekday %q", ErrInvalidSchedule, weekday)
+}
+
+// Last returns the latest time the digest of the mode was due at or before now.
+func (s Schedule) Last(mode models.DeliveryMode, now time.Time) time.Time {
+	at := time.Date(now.Year(), now.Month(), now.Day(), s.Hour, 0, 0, 0, now.Location())
+	if at.After(now) {
+		at = at.AddDate(0, 0, -1)
+	}
+
+	if mode == models.DeliveryWeekly {
+		days := (int(at.Weekday()) - int(s.Weekday) + 7) % 7
+		at = at.AddDate(0, 0, -days)
+	}
+
+	return at
+}
+
+// wallClockNow returns the local wall clock time in UTC, the items are stored
+// with the local time without a time zone and are read as UTC.
+func wallClockNow() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute(), now.Second(), 0, time.UTC)
+}
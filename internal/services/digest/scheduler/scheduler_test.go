@@ -0,0 +1,50 @@
+package scheduler
+
+import (
+	"dussh/internal/domain/models"
+	"testing"
+	"time"
+)
+
+func TestScheduleLast(t *testing.T) {
+	// 2024-03-04 is a Monday
+	schedule := Schedule{Hour: 8, Weekday: time.Monday}
+	at := func(day, hour int) time.Time {
+		return time.Date(2024, 3, day, hour, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		mode models.DeliveryMode
+		now  time.Time
+		want time.Time
+	}{
+		{models.DeliveryDaily, at(4, 8), at(4, 8)},
+		{models.DeliveryDaily, at(4, 7), at(3, 8)},
+		{models.DeliveryDaily, at(6, 23), at(6, 8)},
+		{models.DeliveryWeekly, at(4, 9), at(4, 8)},
+		{models.DeliveryWeekly, at(4, 7), time.Date(2024, 2, 26, 8, 0, 0, 0, time.UTC)},
+		{models.DeliveryWeekly, at(10, 12), at(4, 8)},
+	}
+
+	for _, tt := range tests {
+		if got := schedule.Last(tt.mode, tt.now); !got.Equal(tt.want) {
+			t.Errorf("Last(%s, %s) = %s, want %s", tt.mode, tt.now, got, tt.want)
+		}
+	}
+}
+
+func TestParseSchedule(t *testing.T) {
+	schedule, err := ParseSchedule(8, "Friday")
+	if err != nil || schedule.Weekday != time.Friday {
+		t.Errorf("ParseSchedule(8, Friday) = %+v, %v", schedule, err)
+	}
+
+	for _, tt := range []struct {
+		hour    int
+		weekday string
+	}{{24, "monday"}, {8, "mon"}} {
+		if _, err := ParseSchedule(tt.hour, tt.weekday); err == nil {
+			t.Errorf("ParseSchedule(%d, %q) succeeded", tt.hour, tt.weekday)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package v1
+
+import (
+	"context"
+	domainerrors "dussh/internal/domain/errors"
+	"dussh/internal/domain/models"
+	"dussh/internal/domain/response"
+	"dussh/internal/repository"
+	"dussh/internal/services/inbox"
+	"dussh/pkg/jwt"
+	"dussh/pkg/validator"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultInboxLimit = 20
+	// keepAliveInterval keeps proxies from closing idle streams.
+	keepAliveInterval = 30 * time.Second
+)
+
+type Service interface {
+	// List returns a page of the inbox of the user with the number of unread items.
+	List(ctx context.Context, userID int64, filter models.InboxFilter) ([]*models.InboxItem, int64, error)
+	MarkRead(ctx context.Context, userID, id int64) error
+	MarkAllRead(ctx context.Context, userID int64) error
+	// Subscribe returns the items delivered to the user from now on, on any
+	// instance. The channel is closed once ctx is canceled or on shutdown.
+	Subscribe(ctx context.Context, userID int64) (<-chan *models.InboxItem, error)
+}
+
+func NewInboxAPI(service Service, log *zap.Logger) inbox.Api {
+	return &inboxAPI{
+		svc: service,
+		log: log.Named("inbox.api"),
+	}
+}
+
+type inboxAPI struct {
+	svc Service
+
+	log *zap.Logger
+}
+
+type ListRequest struct {
+	Unread   bool  `form:"unread"`
+	BeforeID int64 `form:"before_id" validate:"omitempty,min=1"`
+	Limit    int64 `form:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+func (a *inboxAPI) List(c *gin.Context) {
+	claims, ok := jwt.UserClaimsFromContext(c)
+	if !ok {
+		response.New(http.StatusUnauthorized, domainerrors.ErrUnauthenticated.Error()).Error(c)
+		return
+	}
+
+	var req ListRequest
+	if err := c.BindQuery(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	if validateErrors := validator.StructValidate(req); validateErrors != nil {
+		response.BadRequest(c, validateErrors)
+		return
+	}
+
+	if req.Limit == 0 {
+		req.Limit = defaultInboxLimit
+	}
+
+	items, unread, err := a.svc.List(c, claims.ID, models.InboxFilter{
+		UnreadOnly: req.Unread,
+		BeforeID:   req.BeforeID,
+		Limit:      req.Limit,
+	})
+	if err != nil {
+		response.InternalError(c, err)
+		return
+	}
+
+	response.New(
+		http.StatusOK,
+		"inbox received successfully",
+		response.WithValues(map[string]any{"items": items, "unread": unread}),
+	).OK(c)
+}
+
+func (a *inboxAPI) MarkRead(c *gin.Context) {
+	claims, ok := jwt.UserClaimsFromContext(c)
+	if !ok {
+		response.New(http.StatusUnauthorized, domainerrors.ErrUnauthenticated.Error()).Error(c)
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, domainerrors.ErrInvalidURLPattern)
+		return
+	}
+
+	if err := a.svc.MarkRead(c, claims.ID, id); err != nil {
+		statusError(c, err)
+		return
+	}
+
+	response.New(http.StatusOK, "inbox notification marked read").OK(c)
+}
+
+func (a *inboxAPI) MarkAllRead(c *gin.Context) {
+	claims, ok := jwt.UserClaimsFromContext(c)
+	if !ok {
+		response.New(http.StatusUnauthorized, domainerrors.ErrUnauthenticated.Error()).Error(c)
+		return
+	}
+
+	if err := a.svc.MarkAllRead(c, claims.ID); err != nil {
+		response.InternalError(c, err)
+		return
+	}
+
+	response.New(http.StatusOK, "inbox notifications marked read").OK(c)
+}
+
+// Stream pushes the items delivered to the user as "notification" events
+// until the client disconnects.
+func (a *inboxAPI) Stream(c *gin.Context) {
+	claims, ok := jwt.UserClaimsFromContext(c)
+	if !ok {
+		response.New(http.StatusUnauthorized, domainerrors.ErrUnauthenticated.Error()).Error(c)
+		return
+	}
+
+	ctx := c.Request.Context()
+	items, err := a.svc.Subscribe(ctx, claims.ID)
+	if err != nil {
+		statusError(c, err)
+		return
+	}
+
+	// the write timeout of the server would end the stream
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		a.log.Warn("failed to clear write deadline of the stream", zap.Error(err))
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case item, ok := <-items:
+			if !ok {
+				return false
+			}
+			c.SSEvent("notification", item)
+			return true
+		case <-keepAlive.C:
+			_, err := io.WriteString(w, ": keep-alive\n\n")
+			return err == nil
+		case <-ctx.Done():
+			return false
+		}
+	})
+}
+
+func statusError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrInboxItemNotFound):
+		response.New(http.StatusNotFound, err.Error()).Error(c)
+	case errors.Is(err, domainerrors.ErrInboxClosed):
+		response.New(http.StatusServiceUnavailable, err.Error()).Error(c)
+	default:
+		response.InternalError(c, err)
+	}
+}
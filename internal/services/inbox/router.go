@@ -0,0 +1,59 @@
+package inbox
+
+import (
+	"dussh/internal/domain/models"
+	"dussh/internal/services/auth"
+	"github.com/gin-gonic/gin"
+)
+
+type Api interface {
+	List(c *gin.Context)
+	MarkRead(c *gin.Context)
+	MarkAllRead(c *gin.Context)
+	Stream(c *gin.Context)
+}
+
+func InitRoutes(
+	routeGroup *gin.RouterGroup,
+	api Api,
+	secretKey string,
+) {
+	var routes = []models.Route{
+		{
+			Method: "GET",
+			Path:   "notifications/inbox",
+			Handlers: []gin.HandlerFunc{
+				auth.JWTAuth(secretKey),
+				api.List,
+			},
+		},
+		{
+			Method: "POST",
+			Path:   "notifications/inbox/read",
+			Handlers: []gin.HandlerFunc{
+				auth.JWTAuth(secretKey),
+				api.MarkAllRead,
+			},
+		},
+		{
+			Method: "POST",
+			Path:   "notifications/inbox/:id/read",
+			Handlers: []gin.HandlerFunc{
+				auth.JWTAuth(secretKey),
+				api.MarkRead,
+			},
+		},
+		{
+			Method: "GET",
+			Path:   "notifications/stream",
+			Handlers: []gin.HandlerFunc{
+				auth.JWTAuth(secretKey),
+				api.Stream,
+			},
+		},
+	}
+
+	for _, r := range routes {
+		routeGroup.Handle(r.Method, r.Path, r.Handlers...)
+	}
+}
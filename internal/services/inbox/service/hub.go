@@ -0,0 +1,86 @@
+package service
+
+import (
+	"dussh/internal/domain/models"
+	"sync"
+)
+
+// subscriberBuffer items are kept for a slow stream, further items are
+// dropped for it and show up when the client lists the inbox.
+const subscriberBuffer = 16
+
+// hub fans items out to the streams open on this instance.
+type hub struct {
+	mu          sync.Mutex
+	closed      bool
+	subscribers map[int64]map[chan *models.InboxItem]struct{}
+}
+
+func newHub() *hub {
+	return &hub{subscribers: make(map[int64]map[chan *models.InboxItem]struct{})}
+}
+
+// subscribe registers a stream of the user, it fails once the hub is closed.
+func (h *hub) subscribe(userID int64) (chan *models.InboxItem, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return nil, false
+	}
+
+	ch := make(chan *models.InboxItem, subscriberBuffer)
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = make(map[chan *models.InboxItem]struct{})
+	}
+	h.subscribers[userID][ch] = struct{}{}
+
+	return ch, true
+}
+
+// unsubscribe removes the stream and closes its channel.
+func (h *hub) unsubscribe(userID int64, ch chan *models.InboxItem) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.subscribers[userID][ch]; !ok {
+		return
+	}
+	delete(h.subscribers[userID], ch)
+	if len(h.subscribers[userID]) == 0 {
+		delete(h.subscribers, userID)
+	}
+	close(ch)
+}
+
+// broadcast sends the item to every stream of the user, it doesn't wait for
+// slow streams.
+func (h *hub) broadcast(userID int64, item *models.InboxItem) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var dropped int
+	for ch := range h.subscribers[userID] {
+		select {
+		case ch <- item:
+		default:
+			dropped++
+		}
+	}
+
+	return dropped
+}
+
+// close ends every stream and refuses new ones.
+func (h *hub) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for userID, channels := range h.subscribers {
+		for ch := range channels {
+			close(ch)
+		}
+		delete(h.subscribers, userID)
+	}
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"dussh/internal/domain/models"
+	"testing"
+)
+
+func TestHub(t *testing.T) {
+	h := newHub()
+
+	first, ok := h.subscribe(1)
+	if !ok {
+		t.Fatal("failed to subscribe")
+	}
+	second, _ := h.subscribe(1)
+	other, _ := h.subscribe(2)
+
+	if dropped := h.broadcast(1, &models.InboxItem{ID: 10}); dropped != 0 {
+		t.Fatalf("dropped %d items", dropped)
+	}
+	for _, ch := range []chan *models.InboxItem{first, second} {
+		if item := <-ch; item.ID != 10 {
+			t.Errorf("received item %d, want 10", item.ID)
+		}
+	}
+	if len(other) != 0 {
+		t.Error("item of another user was received")
+	}
+
+	// a full stream drops items instead of blocking the others
+	for i := 0; i < subscriberBuffer; i++ {
+		h.broadcast(2, &models.InboxItem{ID: int64(i)})
+	}
+	if dropped := h.broadcast(2, &models.InboxItem{}); dropped != 1 {
+		t.Errorf("dropped %d items, want 1", dropped)
+	}
+
+	h.unsubscribe(1, first)
+	if _, ok := <-first; ok {
+		t.Error("unsubscribed channel is open")
+	}
+	h.unsubscribe(1, first)
+
+	h.close()
+	if _, ok := <-second; ok {
+		t.Error("channel is open after close")
+	}
+	if _, ok := h.subscribe(1); ok {
+		t.Error("subscribed after close")
+	}
+	// unsubscribing after close must not close the channel twice
+	h.unsubscribe(1, second)
+}
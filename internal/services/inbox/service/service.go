@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"dussh/internal/cache/redis"
+	domainerrors "dussh/internal/domain/errors"
+	"dussh/internal/domain/models"
+	inboxv1 "dussh/internal/services/inbox/api/v1"
+	"dussh/pkg/notify/provider/inapp"
+	"encoding/json"
+	"go.uber.org/zap"
+)
+
+// inboxChannel carries new items to the instances holding streams of the user.
+const inboxChannel = "inbox:notifications"
+
+type Repository interface {
+	CreateInboxItem(ctx context.Context, item *models.InboxItem) (*models.InboxItem, error)
+	GetInboxItems(ctx context.Context, userID int64, filter models.InboxFilter) ([]*models.InboxItem, error)
+	CountUnreadInboxItems(ctx context.Context, userID int64) (int64, error)
+	MarkInboxItemRead(ctx context.Context, userID, id int64) error
+	MarkAllInboxItemsRead(ctx context.Context, userID int64) (int64, error)
+}
+
+// Service stores in-app notifications and streams them to the user.
+type Service interface {
+	inboxv1.Service
+	inapp.Inbox
+	// Listen pushes the items published by every instance to the streams
+	// open on this one until ctx is canceled.
+	Listen(ctx context.Context)
+	// Close ends the open streams.
+	Close()
+}
+
+func NewInboxService(repo Repository, cache redis.Cache, log *zap.Logger) Service {
+	return &inboxService{
+		repo:  repo,
+		cache: cache,
+		hub:   newHub(),
+		log:   log.Named("inbox.service"),
+	}
+}
+
+type inboxService struct {
+	repo  Repository
+	cache redis.Cache
+	hub   *hub
+
+	log *zap.Logger
+}
+
+// published is an item sent to the other instances.
+type published struct {
+	UserID int64             `json:"user_id"`
+	Item   *models.InboxItem `json:"item"`
+}
+
+func (s *inboxService) Deliver(ctx context.Context, userID int64, msg inapp.Message) error {
+	item, err := s.repo.CreateInboxItem(ctx, &models.InboxItem{
+		UserID:      userID,
+		Subject:     msg.Subject,
+		Body:        msg.Body,
+		ContentType: string(msg.ContentType),
+	})
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(published{UserID: userID, Item: item})
+	if err != nil {
+		return err
+	}
+
+	// the item is stored, a client that misses the push sees it in the list
+	if err := s.cache.Publish(ctx, inboxChannel, payload); err != nil {
+		s.log.Error("failed to publish inbox notification", zap.Int64("id", item.ID), zap.Error(err))
+	}
+
+	return nil
+}
+
+func (s *inboxService) List(
+	ctx context.Context,
+	userID int64,
+	filter models.InboxFilter,
+) ([]*models.InboxItem, int64, error) {
+	items, err := s.repo.GetInboxItems(ctx, userID, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	unread, err := s.repo.CountUnreadInboxItems(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return items, unread, nil
+}
+
+func (s *inboxService) MarkRead(ctx context.Context, userID, id int64) error {
+	return s.repo.MarkInboxItemRead(ctx, userID, id)
+}
+
+func (s *inboxService) MarkAllRead(ctx context.Context, userID int64) error {
+	_, err := s.repo.MarkAllInboxItemsRead(ctx, userID)
+	return err
+}
+
+func (s *inboxService) Subscribe(ctx context.Context, userID int64) (<-chan *models.InboxItem, error) {
+	ch, ok := s.hub.subscribe(userID)
+	if !ok {
+		return nil, domainerrors.ErrInboxClosed
+	}
+
+	go func() {
+		<-ctx.Done()
+		s.hub.unsubscribe(userID, ch)
+	}()
+
+	return ch, nil
+}
+
+func (s *inboxService) Listen(ctx context.Context) {
+	for payload := range s.cache.Subscribe(ctx, inboxChannel) {
+		var p published
+		if err := json.Unmarshal([]byte(payload), &p); err != nil {
+			s.log.Error("failed to decode inbox notification", zap.Error(err))
+			continue
+		}
+
+		if dropped := s.hub.broadcast(p.UserID, p.Item); dropped > 0 {
+			s.log.Warn("inbox notification dropped for slow streams",
+				zap.Int64("user_id", p.UserID),
+				zap.Int("streams", dropped),
+			)
+		}
+	}
+}
+
+func (s *inboxService) Close() {
+	s.hub.close()
+}
@@ -0,0 +1,102 @@
+package v1
+
+import (
+	"bytes"
+	"dussh/internal/domain/response"
+	"dussh/internal/services/mailsink"
+	sink "dussh/pkg/notify/provider/mailsink"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"slices"
+)
+
+type Service interface {
+	// Messages returns the captured messages, newest first.
+	Messages() []*sink.Message
+	Message(id string) (*sink.Message, error)
+	Clear()
+}
+
+func NewMailSinkAPI(service Service, log *zap.Logger) mailsink.Api {
+	return &mailSinkAPI{
+		svc: service,
+		log: log.Named("mailsink.api"),
+	}
+}
+
+type mailSinkAPI struct {
+	svc Service
+
+	log *zap.Logger
+}
+
+// Page renders the captured messages for a browser.
+func (a *mailSinkAPI) Page(c *gin.Context) {
+	var b bytes.Buffer
+	if err := page.Execute(&b, a.svc.Messages()); err != nil {
+		response.InternalError(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, "text/html; charset=utf-8", b.Bytes())
+}
+
+// List returns the captured messages, the to query parameter keeps the
+// messages sent to the address only.
+func (a *mailSinkAPI) List(c *gin.Context) {
+	messages := a.svc.Messages()
+	if to := c.Query("to"); to != "" {
+		messages = slices.DeleteFunc(messages, func(msg *sink.Message) bool {
+			return !slices.Contains(msg.To, to)
+		})
+	}
+
+	response.New(
+		http.StatusOK,
+		"captured messages received successfully",
+		response.WithValues(map[string]any{"messages": messages}),
+	).OK(c)
+}
+
+func (a *mailSinkAPI) Get(c *gin.Context) {
+	msg, err := a.svc.Message(c.Param("id"))
+	if err != nil {
+		statusError(c, err)
+		return
+	}
+
+	response.New(
+		http.StatusOK,
+		"captured message received successfully",
+		response.WithValues(map[string]any{"message": msg}),
+	).OK(c)
+}
+
+// Raw downloads the message as an .eml file.
+func (a *mailSinkAPI) Raw(c *gin.Context) {
+	msg, err := a.svc.Message(c.Param("id"))
+	if err != nil {
+		statusError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="`+msg.ID+`.eml"`)
+	c.Data(http.StatusOK, "message/rfc822", msg.Raw)
+}
+
+func (a *mailSinkAPI) Clear(c *gin.Context) {
+	a.svc.Clear()
+
+	response.New(http.StatusOK, "captured messages cleared").OK(c)
+}
+
+func statusError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, sink.ErrMessageNotFound):
+		response.New(http.StatusNotFound, err.Error()).Error(c)
+	default:
+		response.InternalError(c, err)
+	}
+}
@@ -0,0 +1,35 @@
+package v1
+
+import "html/template"
+
+// page lists the captured messages, HTML bodies are shown in sandboxed frames.
+var page = template.Must(template.New("mail").Parse(`<!doctype html>
+<html lang="en">
+<head>
+    <meta charset="utf-8">
+    <title>Captured mail</title>
+    <style>
+        body { font-family: Helvetica, sans-serif; margin: 24px; color: #222; }
+        details { border: 1px solid #ddd; border-radius: 4px; margin-bottom: 8px; padding: 8px 12px; }
+        summary { cursor: pointer; }
+        .meta { color: #666; font-size: 14px; }
+        pre { white-space: pre-wrap; background: #f6f6f6; padding: 8px; }
+        iframe { width: 100%; height: 480px; border: 1px solid #ddd; }
+    </style>
+</head>
+<body>
+<h1>Captured mail</h1>
+{{range .}}
+<details>
+    <summary><b>{{.Subject}}</b> <span class="meta">to {{range $i, $to := .To}}{{if $i}}, {{end}}{{$to}}{{end}} at {{.CapturedAt.Format "02.01.2006 15:04:05"}}</span></summary>
+    <p class="meta">From {{.From}}, {{.Size}} bytes{{if .Attachments}}, attachments: {{range $i, $name := .Attachments}}{{if $i}}, {{end}}{{$name}}{{end}}{{end}}
+        — <a href="mail/messages/{{.ID}}/raw">download .eml</a></p>
+    {{if .Text}}<pre>{{.Text}}</pre>{{end}}
+    {{if .HTML}}<iframe sandbox srcdoc="{{.HTML}}"></iframe>{{end}}
+</details>
+{{else}}
+<p>No messages captured yet.</p>
+{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,54 @@
+package mailsink
+
+import (
+	"dussh/internal/domain/models"
+	"github.com/gin-gonic/gin"
+)
+
+type Api interface {
+	Page(c *gin.Context)
+	List(c *gin.Context)
+	Get(c *gin.Context)
+	Raw(c *gin.Context)
+	Clear(c *gin.Context)
+}
+
+// InitRoutes registers the pages of the captured emails. They are not
+// authenticated and are registered only when the mail sink captures emails
+// and sink_ui is set, which is refused outside dev.
+func InitRoutes(
+	routeGroup *gin.RouterGroup,
+	api Api,
+) {
+	var routes = []models.Route{
+		{
+			Method:   "GET",
+			Path:     "dev/mail",
+			Handlers: []gin.HandlerFunc{api.Page},
+		},
+		{
+			Method:   "GET",
+			Path:     "dev/mail/messages",
+			Handlers: []gin.HandlerFunc{api.List},
+		},
+		{
+			Method:   "DELETE",
+			Path:     "dev/mail/messages",
+			Handlers: []gin.HandlerFunc{api.Clear},
+		},
+		{
+			Method:   "GET",
+			Path:     "dev/mail/messages/:id",
+			Handlers: []gin.HandlerFunc{api.Get},
+		},
+		{
+			Method:   "GET",
+			Path:     "dev/mail/messages/:id/raw",
+			Handlers: []gin.HandlerFunc{api.Raw},
+		},
+	}
+
+	for _, r := range routes {
+		routeGroup.Handle(r.Method, r.Path, r.Handlers...)
+	}
+}
@@ -0,0 +1,183 @@
+package v1
+
+import (
+	"context"
+	domainerrors "dussh/internal/domain/errors"
+	"dussh/internal/domain/models"
+	"dussh/internal/domain/response"
+	"dussh/internal/repository"
+	"dussh/internal/services/notification"
+	"dussh/pkg/jwt"
+	"dussh/pkg/validator"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+)
+
+const defaultDeliveriesLimit = 50
+
+type Service interface {
+	Deliveries(ctx context.Context, filter models.NotificationDeliveryFilter) ([]*models.NotificationDelivery, error)
+	Delivery(ctx context.Context, id int64) (*models.NotificationDelivery, error)
+	// Resend sends a failed delivery again and returns it with the outcome.
+	Resend(ctx context.Context, id int64) (*models.NotificationDelivery, error)
+	SendPhoneVerification(ctx context.Context, userID int64) error
+	VerifyPhone(ctx context.Context, userID int64, code string) error
+}
+
+func NewNotificationAPI(service Service, log *zap.Logger) notification.Api {
+	return &notificationAPI{
+		svc: service,
+		log: log.Named("notification.api"),
+	}
+}
+
+type notificationAPI struct {
+	svc Service
+
+	log *zap.Logger
+}
+
+type DeliveriesRequest struct {
+	UserID    int64  `form:"user_id" validate:"omitempty,min=1"`
+	EventType string `form:"event_type"`
+	Status    string `form:"status" validate:"omitempty,oneof=queued sent failed"`
+	Limit     int64  `form:"limit" validate:"omitempty,min=1,max=500"`
+}
+
+func (a *notificationAPI) Deliveries(c *gin.Context) {
+	var req DeliveriesRequest
+	if err := c.BindQuery(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	if validateErrors := validator.StructValidate(req); validateErrors != nil {
+		response.BadRequest(c, validateErrors)
+		return
+	}
+
+	if req.Limit == 0 {
+		req.Limit = defaultDeliveriesLimit
+	}
+
+	deliveries, err := a.svc.Deliveries(c, models.NotificationDeliveryFilter{
+		UserID:    req.UserID,
+		EventType: req.EventType,
+		Status:    models.NotificationStatus(req.Status),
+		Limit:     req.Limit,
+	})
+	if err != nil {
+		response.InternalError(c, err)
+		return
+	}
+
+	response.New(
+		http.StatusOK,
+		"notification deliveries received successfully",
+		response.WithValues(map[string]any{"deliveries": deliveries}),
+	).OK(c)
+}
+
+func (a *notificationAPI) Delivery(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, domainerrors.ErrInvalidURLPattern)
+		return
+	}
+
+	delivery, err := a.svc.Delivery(c, id)
+	if err != nil {
+		statusError(c, err)
+		return
+	}
+
+	response.New(
+		http.StatusOK,
+		"notification delivery received successfully",
+		response.WithValues(map[string]any{"delivery": delivery}),
+	).OK(c)
+}
+
+func (a *notificationAPI) Resend(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, domainerrors.ErrInvalidURLPattern)
+		return
+	}
+
+	delivery, err := a.svc.Resend(c, id)
+	if err != nil {
+		statusError(c, err)
+		return
+	}
+
+	response.New(
+		http.StatusOK,
+		"notification resent successfully",
+		response.WithValues(map[string]any{"delivery": delivery}),
+	).OK(c)
+}
+
+// SendPhoneVerification sends a one-time code to the phone of the user.
+func (a *notificationAPI) SendPhoneVerification(c *gin.Context) {
+	claims, ok := jwt.UserClaimsFromContext(c)
+	if !ok {
+		response.New(http.StatusUnauthorized, domainerrors.ErrUnauthenticated.Error()).Error(c)
+		return
+	}
+
+	if err := a.svc.SendPhoneVerification(c, claims.ID); err != nil {
+		statusError(c, err)
+		return
+	}
+
+	response.New(http.StatusAccepted, "verification code sent successfully").OK(c)
+}
+
+type VerifyPhoneRequest struct {
+	Code string `json:"code" validate:"required,numeric,len=6"`
+}
+
+func (a *notificationAPI) VerifyPhone(c *gin.Context) {
+	claims, ok := jwt.UserClaimsFromContext(c)
+	if !ok {
+		response.New(http.StatusUnauthorized, domainerrors.ErrUnauthenticated.Error()).Error(c)
+		return
+	}
+
+	var req VerifyPhoneRequest
+	if err := c.BindJSON(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	if validateErrors := validator.StructValidate(req); validateErrors != nil {
+		response.BadRequest(c, validateErrors)
+		return
+	}
+
+	if err := a.svc.VerifyPhone(c, claims.ID, req.Code); err != nil {
+		statusError(c, err)
+		return
+	}
+
+	response.New(http.StatusOK, "phone verified successfully").OK(c)
+}
+
+func statusError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrDeliveryNotFound), errors.Is(err, repository.ErrUserNotFound):
+		response.New(http.StatusNotFound, err.Error()).Error(c)
+	case errors.Is(err, domainerrors.ErrNotificationNotResendable), errors.Is(err, domainerrors.ErrOTPInvalid):
+		response.New(http.StatusBadRequest, err.Error()).Error(c)
+	case errors.Is(err, domainerrors.ErrPhoneAlreadyVerified):
+		response.New(http.StatusConflict, err.Error()).Error(c)
+	case errors.Is(err, domainerrors.ErrOTPResendTooSoon):
+		response.New(http.StatusTooManyRequests, err.Error()).Error(c)
+	default:
+		response.InternalError(c, err)
+	}
+}
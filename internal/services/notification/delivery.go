@@ -0,0 +1,119 @@
+package notification
+
+import (
+	"context"
+	domainerrors "dussh/internal/domain/errors"
+	"dussh/internal/domain/models"
+	"dussh/pkg/notify/notification"
+	"errors"
+	"time"
+)
+
+// redeliveryDelay is the delay before a failed delivery is sent again, the
+// redelivery is retried by the broker like any deferred notification.
+const redeliveryDelay = time.Minute
+
+// sendError is returned by deliver if the provider failed to send and the
+// attempt is recorded, the delivery can be sent again by its id.
+type sendError struct {
+	err error
+}
+
+func (e *sendError) Error() string { return e.err.Error() }
+
+func (e *sendError) Unwrap() error { return e.err }
+
+// deliver sends the notification and records the attempt in the delivery log,
+// the error of a failed send is kept as the response of the provider.
+func (s *service) deliver(ctx context.Context, id int64, n *notification.Notification) error {
+	sendErr := s.Notify(ctx, n)
+
+	var (
+		status   = models.NotificationSent
+		response *string
+	)
+	if sendErr != nil {
+		status = models.NotificationFailed
+		msg := sendErr.Error()
+		response = &msg
+	}
+
+	if err := s.repo.RecordNotificationAttempt(ctx, id, status, response); err != nil {
+		return errors.Join(sendErr, err)
+	}
+	if sendErr != nil {
+		return &sendError{err: sendErr}
+	}
+
+	return nil
+}
+
+func (s *service) Deliveries(
+	ctx context.Context,
+	filter models.NotificationDeliveryFilter,
+) ([]*models.NotificationDelivery, error) {
+	return s.repo.GetNotificationDeliveries(ctx, filter)
+}
+
+func (s *service) Delivery(ctx context.Context, id int64) (*models.NotificationDelivery, error) {
+	return s.repo.GetNotificationDelivery(ctx, id)
+}
+
+// Resend claims the failed delivery before sending it, concurrent resends of
+// the same delivery send it once.
+func (s *service) Resend(ctx context.Context, id int64) (*models.NotificationDelivery, error) {
+	d, err := s.repo.ClaimFailedNotificationDelivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if d == nil {
+		if _, err := s.repo.GetNotificationDelivery(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, domainerrors.ErrNotificationNotResendable
+	}
+
+	if err := s.deliver(ctx, d.ID, &notification.Notification{
+		Type:        notification.Type(d.Channel),
+		ContentType: notification.ContentType(d.ContentType),
+		To:          d.Recipients,
+		Subject:     d.Subject,
+		Body:        d.Body,
+		AltBody:     d.AltBody,
+		Attachments: fromAttachments(d.Attachments),
+	}); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetNotificationDelivery(ctx, id)
+}
+
+// toAttachments converts the attachments of a notification to be kept in the
+// delivery log and in deferred notifications.
+func toAttachments(attachments []notification.Attachment) []models.NotificationAttachment {
+	var result []models.NotificationAttachment
+	for _, a := range attachments {
+		result = append(result, models.NotificationAttachment{
+			Name:        a.Name,
+			ContentType: a.ContentType,
+			Data:        a.Data,
+			Inline:      a.Inline,
+		})
+	}
+
+	return result
+}
+
+func fromAttachments(attachments []models.NotificationAttachment) []notification.Attachment {
+	var result []notification.Attachment
+	for _, a := range attachments {
+		result = append(result, notification.Attachment{
+			Name:        a.Name,
+			ContentType: a.ContentType,
+			Data:        a.Data,
+			Inline:      a.Inline,
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,162 @@
+package notification
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"dussh/internal/cache/redis"
+	domainerrors "dussh/internal/domain/errors"
+	"dussh/internal/domain/models"
+	"dussh/internal/repository"
+	"dussh/pkg/notify/notification"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+const (
+	otpKeyPrefix     = "otp:"
+	otpSentKeyPrefix = "otp:sent:"
+	otpDigits        = 6
+	otpText          = "Код подтверждения: %s. Никому не сообщайте его."
+)
+
+// otpState is stored by phone, only the hash of the code is kept.
+type otpState struct {
+	Hash      string    `json:"hash"`
+	Attempts  int       `json:"attempts"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (s *service) SendOTP(ctx context.Context, phone string) error {
+	sent, err := s.cache.SetNX(ctx, otpSentKeyPrefix+phone, 1, s.otpCfg.OTPResendInterval)
+	if err != nil {
+		return err
+	}
+	if !sent {
+		return domainerrors.ErrOTPResendTooSoon
+	}
+
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return err
+	}
+	code := fmt.Sprintf("%0*d", otpDigits, n.Int64())
+
+	state, err := json.Marshal(otpState{
+		Hash:      hashOTP(code),
+		ExpiresAt: time.Now().Add(s.otpCfg.OTPTTL),
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.cache.Set(ctx, otpKeyPrefix+phone, state, s.otpCfg.OTPTTL); err != nil {
+		return err
+	}
+
+	sms := &notification.Notification{
+		Type:        notification.TypeSMS,
+		ContentType: notification.ContentTypePlain,
+		To:          []string{phone},
+		Body:        fmt.Sprintf(otpText, code),
+	}
+
+	// the code is masked in the delivery log
+	id, err := s.repo.CreateNotificationDelivery(ctx, &models.NotificationDelivery{
+		EventType:   models.EventTypeOTP,
+		Channel:     models.ChannelSMS,
+		Recipients:  sms.To,
+		ContentType: string(sms.ContentType),
+		Body:        fmt.Sprintf(otpText, strings.Repeat("*", otpDigits)),
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.deliver(ctx, id, sms)
+}
+
+func (s *service) VerifyOTP(ctx context.Context, phone, code string) error {
+	key := otpKeyPrefix + phone
+
+	value, err := s.cache.Get(ctx, key)
+	if errors.Is(err, redis.ErrNotFound) {
+		return domainerrors.ErrOTPInvalid
+	}
+	if err != nil {
+		return err
+	}
+
+	var state otpState
+	if err := json.Unmarshal([]byte(value), &state); err != nil {
+		return err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(state.Hash), []byte(hashOTP(code))) == 1 {
+		return s.cache.Delete(ctx, key)
+	}
+
+	// a code is dropped after too many wrong guesses
+	state.Attempts++
+	ttl := time.Until(state.ExpiresAt)
+	if state.Attempts >= s.otpCfg.OTPMaxAttempts || ttl <= 0 {
+		if err := s.cache.Delete(ctx, key); err != nil {
+			return err
+		}
+		return domainerrors.ErrOTPInvalid
+	}
+
+	updated, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	if err := s.cache.Set(ctx, key, updated, ttl); err != nil {
+		return err
+	}
+
+	return domainerrors.ErrOTPInvalid
+}
+
+func (s *service) SendPhoneVerification(ctx context.Context, userID int64) error {
+	user, err := s.userSvc.Get(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.PhoneVerifiedAt != nil {
+		return domainerrors.ErrPhoneAlreadyVerified
+	}
+
+	return s.SendOTP(ctx, user.Phone)
+}
+
+// VerifyPhone checks the code against the current phone of the user, a code
+// sent to a phone the user replaced since is not accepted.
+func (s *service) VerifyPhone(ctx context.Context, userID int64, code string) error {
+	user, err := s.userSvc.Get(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.VerifyOTP(ctx, user.Phone, code); err != nil {
+		return err
+	}
+
+	if err := s.repo.VerifyUserPhone(ctx, userID, user.Phone); err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return domainerrors.ErrOTPInvalid
+		}
+		return err
+	}
+
+	return nil
+}
+
+func hashOTP(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,76 @@
+//go:generate go run /home/dmitry/dussh/pkg/rbac/rolegen
+package notification
+
+import (
+	"dussh/internal/domain/models"
+	rbacmiddleware "dussh/internal/role/middleware"
+	"dussh/internal/services/auth"
+	"dussh/pkg/rbac"
+	"github.com/gin-gonic/gin"
+)
+
+type Api interface {
+	Deliveries(c *gin.Context)
+	Delivery(c *gin.Context)
+	Resend(c *gin.Context)
+	SendPhoneVerification(c *gin.Context)
+	VerifyPhone(c *gin.Context)
+}
+
+func InitRoutes(
+	routeGroup *gin.RouterGroup,
+	api Api,
+	roleManager rbac.RoleManager,
+	secretKey string,
+) {
+	//rolegen:routes
+	var routes = []models.Route{
+		{
+			Method: "GET",
+			Path:   "notifications/deliveries",
+			Role:   "admin",
+			Handlers: []gin.HandlerFunc{
+				rbacmiddleware.RoleAccess(roleManager, secretKey),
+				api.Deliveries,
+			},
+		},
+		{
+			Method: "GET",
+			Path:   "notifications/deliveries/:id",
+			Role:   "admin",
+			Handlers: []gin.HandlerFunc{
+				rbacmiddleware.RoleAccess(roleManager, secretKey),
+				api.Delivery,
+			},
+		},
+		{
+			Method: "POST",
+			Path:   "notifications/deliveries/:id/resend",
+			Role:   "admin",
+			Handlers: []gin.HandlerFunc{
+				rbacmiddleware.RoleAccess(roleManager, secretKey),
+				api.Resend,
+			},
+		},
+		{
+			Method: "POST",
+			Path:   "phone/verification",
+			Handlers: []gin.HandlerFunc{
+				auth.JWTAuth(secretKey),
+				api.SendPhoneVerification,
+			},
+		},
+		{
+			Method: "POST",
+			Path:   "phone/verification/confirm",
+			Handlers: []gin.HandlerFunc{
+				auth.JWTAuth(secretKey),
+				api.VerifyPhone,
+			},
+		},
+	}
+
+	for _, r := range routes {
+		routeGroup.Handle(r.Method, r.Path, r.Handlers...)
+	}
+}
@@ -1,97 +1,564 @@
 package notification
 
 import (
-	"bytes"
 	"context"
+	"dussh/internal/cache/redis"
+	"dussh/internal/config"
 	"dussh/internal/domain/models"
+	"dussh/internal/repository"
 	coursev1 "dussh/internal/services/course/api/v1"
+	preferencev1 "dussh/internal/services/preference/api/v1"
+	templatev1 "dussh/internal/services/template/api/v1"
 	userv1 "dussh/internal/services/user/api/v1"
 	"dussh/pkg/notify"
 	"dussh/pkg/notify/notification"
 	"errors"
-	"html/template"
+	"fmt"
+	"go.uber.org/zap"
+	"strconv"
 	"strings"
+	"time"
 )
 
 var ErrNotificationConfigIsInvalid = errors.New("notification config is invalid")
 
 type Service interface {
 	Notify(context.Context, *notification.Notification) error
-	CreateNotificationByEnrollmentEvent(context.Context, models.EnrollmentEvent) (*notification.Notification, error)
+	// NotifyUser renders the template of the event type in the locale of the
+	// user and sends it to the channels the user chose for the category.
+	// Intrusive channels are deferred until the quiet hours end. Categories
+	// the user gets digests of are collected for the next digest instead.
+	NotifyUser(
+		ctx context.Context,
+		userID int64,
+		category models.NotificationCategory,
+		eventType string,
+		data any,
+	) error
+	// NotifyEnrollment notifies the user and the trainers of the course about
+	// a new enrollment.
+	NotifyEnrollment(context.Context, models.EnrollmentEvent) error
+	// NotifyCancellation notifies the user and the trainers of the course about
+	// a cancelled enrollment.
+	NotifyCancellation(context.Context, models.EnrollmentCancelledEvent) error
+	// NotifyScheduleChange notifies the enrolled users and the trainers of the
+	// course that its schedule changed.
+	NotifyScheduleChange(context.Context, models.ScheduleChangedEvent) error
+	// NotifyDigest emails the user the notifications collected for the digest.
+	NotifyDigest(context.Context, models.NotificationDigestEvent) error
+	// NotifyReminder reminds the user of an upcoming session.
+	NotifyReminder(context.Context, models.SessionReminderEvent) error
+	// NotifyMedicalCertificateExpiring notifies the user and the trainers of
+	// the courses the user is enrolled in that the certificate expires soon.
+	NotifyMedicalCertificateExpiring(context.Context, models.MedicalCertificateExpiringEvent) error
+	// NotifyDeferred sends a notification held back by quiet hours.
+	NotifyDeferred(context.Context, models.DeferredNotificationEvent) error
+	// SendOTP sends a one-time code to the phone by sms.
+	SendOTP(ctx context.Context, phone string) error
+	// VerifyOTP checks the code sent to the phone, a code is accepted once.
+	VerifyOTP(ctx context.Context, phone, code string) error
+	// SendPhoneVerification sends a one-time code to the phone of the user.
+	SendPhoneVerification(ctx context.Context, userID int64) error
+	// VerifyPhone marks the phone of the user as verified if the code matches.
+	VerifyPhone(ctx context.Context, userID int64, code string) error
+	Deliveries(ctx context.Context, filter models.NotificationDeliveryFilter) ([]*models.NotificationDelivery, error)
+	Delivery(ctx context.Context, id int64) (*models.NotificationDelivery, error)
+	// Resend sends a failed delivery again and returns it with the outcome.
+	Resend(ctx context.Context, id int64) (*models.NotificationDelivery, error)
+}
+
+type Repository interface {
+	GetTelegramLink(ctx context.Context, userID int64) (*models.TelegramLink, error)
+	SaveDeferredNotification(ctx context.Context, e models.DeferredNotificationEvent, delay time.Duration) error
+	CreateNotificationDelivery(ctx context.Context, d *models.NotificationDelivery) (int64, error)
+	RecordNotificationAttempt(
+		ctx context.Context,
+		id int64,
+		status models.NotificationStatus,
+		response *string,
+	) error
+	GetNotificationDeliveries(
+		ctx context.Context,
+		filter models.NotificationDeliveryFilter,
+	) ([]*models.NotificationDelivery, error)
+	GetNotificationDelivery(ctx context.Context, id int64) (*models.NotificationDelivery, error)
+	ClaimFailedNotificationDelivery(ctx context.Context, id int64) (*models.NotificationDelivery, error)
+	GetCourseUserIDs(ctx context.Context, courseID int64) ([]int64, error)
+	GetCourseTrainerIDs(ctx context.Context, courseID int64) ([]int64, error)
+	GetUserCourseIDs(ctx context.Context, userID int64) ([]int64, error)
+	SaveDigestItem(ctx context.Context, item *models.DigestItem) error
+	GetDigestItems(ctx context.Context, userID int64, ids []int64) ([]*models.DigestItem, error)
+	VerifyUserPhone(ctx context.Context, id int64, phone string) error
 }
 
 func NewService(
 	cfg notify.Config,
+	otpCfg config.SMSProvider,
 	courseSvc coursev1.Service,
 	userSvc userv1.Service,
+	preferenceSvc preferencev1.Service,
+	templateSvc templatev1.Service,
+	repo Repository,
+	cache redis.Cache,
+	log *zap.Logger,
 ) Service {
 	return &service{
-		cfg:       cfg,
-		courseSvc: courseSvc,
-		userSvc:   userSvc,
+		cfg:           cfg,
+		otpCfg:        otpCfg,
+		courseSvc:     courseSvc,
+		userSvc:       userSvc,
+		preferenceSvc: preferenceSvc,
+		templateSvc:   templateSvc,
+		repo:          repo,
+		cache:         cache,
+		log:           log.Named("notification.service"),
 	}
 }
 
 type service struct {
-	cfg       notify.Config
-	courseSvc coursev1.Service
-	userSvc   userv1.Service
+	cfg           notify.Config
+	otpCfg        config.SMSProvider
+	courseSvc     coursev1.Service
+	userSvc       userv1.Service
+	preferenceSvc preferencev1.Service
+	templateSvc   templatev1.Service
+	repo          Repository
+	cache         redis.Cache
+
+	log *zap.Logger
+}
+
+func (s *service) NotifyEnrollment(ctx context.Context, e models.EnrollmentEvent) error {
+	course, err := s.courseSvc.Get(ctx, e.CourseID)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.userSvc.Get(ctx, e.UserID)
+	if err != nil {
+		return err
+	}
+
+	data := models.EnrollmentTemplateData{User: fullName(user), CourseName: course.Name}
+
+	delivered, err := s.notifyUser(ctx, e.UserID, models.CategoryEnrollment, e.EventType(), data)
+	n, trainersErr := s.notifyTrainers(ctx, course.ID, models.CategoryEnrollment,
+		models.TemplateTrainerEnrollmentCreated, data)
+
+	return s.partial(delivered+n, errors.Join(err, trainersErr))
 }
 
-const enrollmentSubject = "Новая запсь на курс"
+func (s *service) NotifyCancellation(ctx context.Context, e models.EnrollmentCancelledEvent) error {
+	course, err := s.courseSvc.Get(ctx, e.CourseID)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.userSvc.Get(ctx, e.UserID)
+	if err != nil {
+		return err
+	}
+
+	data := models.EnrollmentTemplateData{User: fullName(user), CourseName: course.Name}
+
+	delivered, err := s.notifyUser(ctx, e.UserID, models.CategoryCancellation, e.EventType(), data)
+	n, trainersErr := s.notifyTrainers(ctx, course.ID, models.CategoryCancellation,
+		models.TemplateTrainerEnrollmentCancelled, data)
 
-type enrollmentInfo struct {
-	Subject    string
-	CourseName string
-	User       string
+	return s.partial(delivered+n, errors.Join(err, trainersErr))
 }
 
-func (s *service) CreateNotificationByEnrollmentEvent(
+func (s *service) NotifyScheduleChange(ctx context.Context, e models.ScheduleChangedEvent) error {
+	course, err := s.courseSvc.Get(ctx, e.CourseID)
+	if err != nil {
+		return err
+	}
+
+	userIDs, err := s.repo.GetCourseUserIDs(ctx, course.ID)
+	if err != nil {
+		return err
+	}
+
+	trainerIDs, err := s.repo.GetCourseTrainerIDs(ctx, course.ID)
+	if err != nil {
+		return err
+	}
+
+	var (
+		delivered int
+		errs      []error
+	)
+	for _, userID := range append(userIDs, trainerIDs...) {
+		user, err := s.userSvc.Get(ctx, userID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		n, err := s.notifyUser(ctx, userID, models.CategoryScheduleChange, e.EventType(),
+			models.ScheduleChangeTemplateData{User: fullName(user), CourseName: course.Name})
+		delivered += n
+		errs = append(errs, err)
+	}
+
+	return s.partial(delivered, errors.Join(errs...))
+}
+
+// notifyTrainers notifies every trainer of the course, data is rendered as is
+// into the template of each of them. It returns the number of deliveries.
+func (s *service) notifyTrainers(
 	ctx context.Context,
-	e models.EnrollmentEvent,
-) (*notification.Notification, error) {
+	courseID int64,
+	category models.NotificationCategory,
+	templateName string,
+	data any,
+) (int, error) {
+	trainerIDs, err := s.repo.GetCourseTrainerIDs(ctx, courseID)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		delivered int
+		errs      []error
+	)
+	for _, trainerID := range trainerIDs {
+		n, err := s.notifyUser(ctx, trainerID, category, templateName, data)
+		delivered += n
+		errs = append(errs, err)
+	}
+
+	return delivered, errors.Join(errs...)
+}
+
+// partial returns the error only if nothing was delivered. Otherwise a retry
+// of the whole event would send the delivered notifications again, so the
+// error is logged instead, failed sends are retried by their delivery id.
+func (s *service) partial(delivered int, err error) error {
+	if err == nil || delivered == 0 {
+		return err
+	}
+
+	s.log.Error("failed to deliver some notifications", zap.Int("delivered", delivered), zap.Error(err))
+	return nil
+}
+
+func (s *service) NotifyReminder(ctx context.Context, e models.SessionReminderEvent) error {
 	course, err := s.courseSvc.Get(ctx, e.CourseID)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	user, err := s.userSvc.Get(ctx, e.UserID)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	info := enrollmentInfo{
-		Subject:    enrollmentSubject,
+	data := models.SessionReminderTemplateData{
+		User:       fullName(user),
 		CourseName: course.Name,
-		User:       strings.Join([]string{user.Surname, user.FirstName, user.MiddleName}, " "),
+		StartsAt:   e.StartsAt.UTC().Format("02.01.2006 15:04"),
+	}
+	for _, event := range course.Events {
+		if event.ID == e.EventID {
+			data.Session = event.Description
+		}
+	}
+
+	return s.NotifyUser(ctx, e.UserID, models.CategoryReminder, e.EventType(), data)
+}
+
+func (s *service) NotifyMedicalCertificateExpiring(ctx context.Context, e models.MedicalCertificateExpiringEvent) error {
+	user, err := s.userSvc.Get(ctx, e.UserID)
+	if err != nil {
+		return err
+	}
+
+	courseIDs, err := s.repo.GetUserCourseIDs(ctx, e.UserID)
+	if err != nil {
+		return err
+	}
+
+	data := models.MedicalCertificateTemplateData{
+		User:      fullName(user),
+		ExpiresAt: e.ExpiresAt.UTC().Format("02.01.2006"),
 	}
 
-	t := template.New("email.html")
+	delivered, err := s.notifyUser(ctx, e.UserID, models.CategoryMedicalCertificate, e.EventType(), data)
+	errs := []error{err}
+	for _, courseID := range courseIDs {
+		course, err := s.courseSvc.Get(ctx, courseID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
 
-	t, err = t.ParseFiles("internal/domain/template/email.html")
+		data.CourseName = course.Name
+		n, err := s.notifyTrainers(ctx, course.ID, models.CategoryMedicalCertificate,
+			models.TemplateTrainerMedicalCertificate, data)
+		delivered += n
+		errs = append(errs, err)
+	}
+
+	return s.partial(delivered, errors.Join(errs...))
+}
+
+func (s *service) NotifyUser(
+	ctx context.Context,
+	userID int64,
+	category models.NotificationCategory,
+	eventType string,
+	data any,
+) error {
+	return s.partial(s.notifyUser(ctx, userID, category, eventType, data))
+}
+
+// notifyUser is NotifyUser that returns the number of deliveries, a digest
+// item counts as one.
+func (s *service) notifyUser(
+	ctx context.Context,
+	userID int64,
+	category models.NotificationCategory,
+	eventType string,
+	data any,
+) (int, error) {
+	user, err := s.userSvc.Get(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	prefs, err := s.preferenceSvc.Get(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	msg, err := s.templateSvc.Render(ctx, eventType, prefs.Locale, data)
+	if err != nil {
+		return 0, err
+	}
+
+	channels := prefs.ChannelsOf(category)
+	if mode := prefs.DeliveryOf(category); mode.Digest() && len(channels) > 0 {
+		err := s.repo.SaveDigestItem(ctx, &models.DigestItem{
+			UserID:    userID,
+			Mode:      mode,
+			Category:  category,
+			EventType: eventType,
+			Subject:   msg.Subject,
+			Body:      msg.Text,
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		return 1, nil
+	}
+
+	return s.send(ctx, user, prefs, eventType, msg, channels)
+}
+
+func (s *service) NotifyDigest(ctx context.Context, e models.NotificationDigestEvent) error {
+	items, err := s.repo.GetDigestItems(ctx, e.UserID, e.ItemIDs)
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	user, err := s.userSvc.Get(ctx, e.UserID)
+	if err != nil {
+		return err
+	}
+
+	prefs, err := s.preferenceSvc.Get(ctx, e.UserID)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	var tpl bytes.Buffer
-	if err := t.Execute(&tpl, info); err != nil {
-		return nil, err
+	data := models.DigestTemplateData{User: fullName(user), Weekly: e.Mode == models.DeliveryWeekly}
+	for _, item := range items {
+		data.Items = append(data.Items, models.DigestTemplateItem{
+			Subject: item.Subject,
+			Text:    item.Body,
+			At:      item.CreatedAt.Format("02.01.2006 15:04"),
+		})
 	}
 
+	msg, err := s.templateSvc.Render(ctx, e.EventType(), prefs.Locale, data)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.send(ctx, user, prefs, e.EventType(), msg, []models.NotificationChannel{models.ChannelEmail})
+	return err
+}
+
+// send sends the message to the channels and logs every delivery, intrusive
+// channels are deferred until the quiet hours of the user end. A failed send
+// is redelivered by its delivery id, so it counts as delivered and isn't an
+// error. It returns the number of deliveries.
+func (s *service) send(
+	ctx context.Context,
+	user *models.User,
+	prefs *models.NotificationPreferences,
+	eventType string,
+	msg *models.RenderedTemplate,
+	channels []models.NotificationChannel,
+) (int, error) {
+	userID := user.ID
+	quiet := prefs.QuietHours.Remaining(time.Now())
+
+	var (
+		delivered int
+		errs      []error
+	)
+	for _, channel := range channels {
+		n, err := s.notificationFor(ctx, channel, user, msg)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if n == nil {
+			continue
+		}
+
+		id, err := s.repo.CreateNotificationDelivery(ctx, &models.NotificationDelivery{
+			UserID:          &userID,
+			EventType:       eventType,
+			Locale:          &msg.Locale,
+			TemplateVersion: msg.Version,
+			Channel:         channel,
+			Recipients:      n.To,
+			ContentType:     string(n.ContentType),
+			Subject:         n.Subject,
+			Body:            n.Body,
+			AltBody:         n.AltBody,
+			Attachments:     toAttachments(n.Attachments),
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", channel, err))
+			continue
+		}
+
+		if quiet > 0 && channel.Intrusive() {
+			err = s.deferDelivery(ctx, userID, id, n, quiet)
+		} else if err = s.deliver(ctx, id, n); errors.As(err, new(*sendError)) {
+			s.log.Warn("failed to send notification, redelivering",
+				zap.Int64("delivery_id", id),
+				zap.String("channel", string(channel)),
+				zap.Error(err),
+			)
+			err = s.deferDelivery(ctx, userID, id, n, redeliveryDelay)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", channel, err))
+			continue
+		}
+
+		delivered++
+	}
+
+	return delivered, errors.Join(errs...)
+}
+
+// deferDelivery sends the logged delivery after the delay.
+func (s *service) deferDelivery(
+	ctx context.Context,
+	userID int64,
+	id int64,
+	n *notification.Notification,
+	delay time.Duration,
+) error {
+	return s.repo.SaveDeferredNotification(ctx, models.DeferredNotificationEvent{
+		UserID:      userID,
+		Type:        string(n.Type),
+		ContentType: string(n.ContentType),
+		To:          n.To,
+		Subject:     n.Subject,
+		Body:        n.Body,
+		AltBody:     n.AltBody,
+		Attachments: toAttachments(n.Attachments),
+		DeliveryID:  id,
+	}, delay)
+}
+
+func fullName(user *models.User) string {
+	return strings.Join([]string{user.Surname, user.FirstName, user.MiddleName}, " ")
+}
+
+// notificationFor returns the notification of the message to the channel, or
+// nil if the channel is not configured or the user can't be reached by it.
+func (s *service) notificationFor(
+	ctx context.Context,
+	channel models.NotificationChannel,
+	user *models.User,
+	msg *models.RenderedTemplate,
+) (*notification.Notification, error) {
 	n := &notification.Notification{
-		Type:        notification.TypeEmail,
-		ContentType: notification.ContentTypeHTML,
-		To:          []string{user.Email},
-		Subject:     enrollmentSubject,
-		Body:        tpl.String(),
+		Type:        notification.Type(channel),
+		ContentType: notification.ContentTypePlain,
+		Subject:     msg.Subject,
+		Body:        msg.Text,
+	}
+
+	provider := s.cfg.GetNotificationProviderByType(n.Type)
+	if provider == nil || !provider.IsValid() {
+		return nil, nil
+	}
+
+	switch channel {
+	case models.ChannelEmail:
+		if user.Email == "" {
+			return nil, nil
+		}
+		n.To = []string{user.Email}
+		if msg.HTML != "" {
+			n.ContentType, n.Body, n.AltBody = notification.ContentTypeHTML, msg.HTML, msg.Text
+		}
+	case models.ChannelSMS:
+		if user.Phone == "" {
+			return nil, nil
+		}
+		n.To = []string{user.Phone}
+	case models.ChannelTelegram:
+		link, err := s.repo.GetTelegramLink(ctx, user.ID)
+		if errors.Is(err, repository.ErrTelegramNotLinked) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		n.To = []string{strconv.FormatInt(link.ChatID, 10)}
+	default:
+		n.To = []string{strconv.FormatInt(user.ID, 10)}
 	}
 
 	return n, nil
 }
 
+func (s *service) NotifyDeferred(ctx context.Context, e models.DeferredNotificationEvent) error {
+	n := &notification.Notification{
+		Type:        notification.Type(e.Type),
+		ContentType: notification.ContentType(e.ContentType),
+		To:          e.To,
+		Subject:     e.Subject,
+		Body:        e.Body,
+		AltBody:     e.AltBody,
+		Attachments: fromAttachments(e.Attachments),
+	}
+
+	// deferred before the delivery log was added
+	if e.DeliveryID == 0 {
+		return s.Notify(ctx, n)
+	}
+
+	return s.deliver(ctx, e.DeliveryID, n)
+}
+
 func (s *service) Notify(ctx context.Context, n *notification.Notification) error {
 	provider := s.cfg.GetNotificationProviderByType(n.Type)
-	if !provider.IsValid() {
+	if provider == nil || !provider.IsValid() {
 		return ErrNotificationConfigIsInvalid
 	}
 
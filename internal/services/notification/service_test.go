@@ -0,0 +1,351 @@
+package notification
+
+import (
+	"context"
+	"dussh/internal/cache/redis"
+	"dussh/internal/config"
+	domainerrors "dussh/internal/domain/errors"
+	"dussh/internal/domain/models"
+	"dussh/internal/repository"
+	coursev1 "dussh/internal/services/course/api/v1"
+	preferencev1 "dussh/internal/services/preference/api/v1"
+	templatev1 "dussh/internal/services/template/api/v1"
+	userv1 "dussh/internal/services/user/api/v1"
+	"dussh/pkg/notify"
+	"dussh/pkg/notify/notification"
+	"dussh/pkg/notify/provider/mailsink"
+	"dussh/pkg/notify/provider/sms"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type fakeCourses struct{ coursev1.Service }
+
+func (fakeCourses) Get(_ context.Context, id int64) (*models.Course, error) {
+	return &models.Course{ID: id, Name: "Yoga"}, nil
+}
+
+type fakeUsers struct{ userv1.Service }
+
+func (fakeUsers) Get(_ context.Context, id int64) (*models.User, error) {
+	return &models.User{ID: id, Phone: phoneOf(id)}, nil
+}
+
+// fakePreferences sends by sms, the user 1 gets medical certificate notices
+// in the daily digest.
+type fakePreferences struct{ preferencev1.Service }
+
+func (fakePreferences) Get(_ context.Context, userID int64) (*models.NotificationPreferences, error) {
+	prefs := &models.NotificationPreferences{
+		UserID: userID,
+		Channels: map[models.NotificationCategory][]models.NotificationChannel{
+			models.CategoryScheduleChange:     {models.ChannelSMS},
+			models.CategoryMedicalCertificate: {models.ChannelSMS},
+		},
+	}
+	if userID == 1 {
+		prefs.Delivery = map[models.NotificationCategory]models.DeliveryMode{
+			models.CategoryMedicalCertificate: models.DeliveryDaily,
+		}
+	}
+
+	return prefs, nil
+}
+
+type fakeTemplates struct{ templatev1.Service }
+
+func (fakeTemplates) Render(context.Context, string, string, any) (*models.RenderedTemplate, error) {
+	return &models.RenderedTemplate{Text: "the schedule changed"}, nil
+}
+
+type deferred struct {
+	event models.DeferredNotificationEvent
+	delay time.Duration
+}
+
+type fakeRepo struct {
+	Repository
+	userIDs    []int64
+	trainerIDs []int64
+	courseIDs  []int64
+	createErr  error
+
+	deliveries int64
+	attempts   map[int64]models.NotificationStatus
+	deferred   []deferred
+	verified   []int64
+	digest     []*models.DigestItem
+}
+
+func (r *fakeRepo) GetCourseUserIDs(context.Context, int64) ([]int64, error) {
+	return r.userIDs, nil
+}
+
+func (r *fakeRepo) GetCourseTrainerIDs(context.Context, int64) ([]int64, error) {
+	return r.trainerIDs, nil
+}
+
+func (r *fakeRepo) GetUserCourseIDs(context.Context, int64) ([]int64, error) {
+	return r.courseIDs, nil
+}
+
+func (r *fakeRepo) SaveDigestItem(_ context.Context, item *models.DigestItem) error {
+	r.digest = append(r.digest, item)
+	return nil
+}
+
+func (r *fakeRepo) CreateNotificationDelivery(context.Context, *models.NotificationDelivery) (int64, error) {
+	if r.createErr != nil {
+		return 0, r.createErr
+	}
+
+	r.deliveries++
+	return r.deliveries, nil
+}
+
+func (r *fakeRepo) RecordNotificationAttempt(_ context.Context, id int64, status models.NotificationStatus, _ *string) error {
+	r.attempts[id] = status
+	return nil
+}
+
+func (r *fakeRepo) VerifyUserPhone(_ context.Context, id int64, phone string) error {
+	if phone != phoneOf(id) {
+		return repository.ErrUserNotFound
+	}
+
+	r.verified = append(r.verified, id)
+	return nil
+}
+
+func (r *fakeRepo) SaveDeferredNotification(_ context.Context, e models.DeferredNotificationEvent, delay time.Duration) error {
+	r.deferred = append(r.deferred, deferred{e, delay})
+	return nil
+}
+
+// failingGateway fails to send to the phone.
+type failingGateway struct {
+	phone string
+}
+
+func (g failingGateway) Send(_ context.Context, msg sms.Message) error {
+	if msg.To == g.phone {
+		return errors.New("gateway is down")
+	}
+	return nil
+}
+
+type fakeCache struct {
+	redis.Cache
+	values map[string]string
+}
+
+func (c *fakeCache) Get(_ context.Context, key string) (string, error) {
+	v, ok := c.values[key]
+	if !ok {
+		return "", redis.ErrNotFound
+	}
+	return v, nil
+}
+
+func (c *fakeCache) Set(_ context.Context, key string, value any, _ time.Duration) error {
+	if b, ok := value.([]byte); ok {
+		value = string(b)
+	}
+	c.values[key] = fmt.Sprint(value)
+	return nil
+}
+
+func (c *fakeCache) SetNX(ctx context.Context, key string, value any, ttl time.Duration) (bool, error) {
+	if _, ok := c.values[key]; ok {
+		return false, nil
+	}
+	return true, c.Set(ctx, key, value, ttl)
+}
+
+func (c *fakeCache) Delete(_ context.Context, key string) error {
+	delete(c.values, key)
+	return nil
+}
+
+// recordingGateway keeps the sent messages.
+type recordingGateway struct {
+	sent []sms.Message
+}
+
+func (g *recordingGateway) Send(_ context.Context, msg sms.Message) error {
+	g.sent = append(g.sent, msg)
+	return nil
+}
+
+func phoneOf(userID int64) string {
+	return "+7900000000" + strconv.FormatInt(userID, 10)
+}
+
+func newTestService(repo *fakeRepo, gateway sms.Gateway) Service {
+	repo.attempts = make(map[int64]models.NotificationStatus)
+
+	return NewService(
+		notify.Config{SMS: &sms.NotificationProvider{From: "DUSSH", Gateway: gateway}},
+		config.SMSProvider{},
+		fakeCourses{},
+		fakeUsers{},
+		fakePreferences{},
+		fakeTemplates{},
+		repo,
+		nil,
+		zap.NewNop(),
+	)
+}
+
+func TestScheduleChangeRedeliversFailedSends(t *testing.T) {
+	repo := &fakeRepo{userIDs: []int64{1, 2}}
+	svc := newTestService(repo, failingGateway{phone: phoneOf(2)})
+
+	// the first user got the sms, retrying the event would send it again
+	if err := svc.NotifyScheduleChange(context.Background(), models.ScheduleChangedEvent{CourseID: 1}); err != nil {
+		t.Fatalf("failed to notify about partially delivered schedule change: %v", err)
+	}
+
+	if repo.attempts[1] != models.NotificationSent || repo.attempts[2] != models.NotificationFailed {
+		t.Errorf("attempts = %v, want 1 sent and 2 failed", repo.attempts)
+	}
+
+	if len(repo.deferred) != 1 {
+		t.Fatalf("deferred %d deliveries, want the failed one", len(repo.deferred))
+	}
+	got := repo.deferred[0]
+	if got.event.DeliveryID != 2 || got.event.UserID != 2 || got.delay != redeliveryDelay {
+		t.Errorf("deferred delivery %d of user %d in %s, want delivery 2 of user 2 in %s",
+			got.event.DeliveryID, got.event.UserID, got.delay, redeliveryDelay)
+	}
+}
+
+func TestDeferredEmailKeepsAltBodyAndAttachments(t *testing.T) {
+	var (
+		ctx  = context.Background()
+		repo = &fakeRepo{attempts: make(map[int64]models.NotificationStatus)}
+		sink = &mailsink.NotificationProvider{From: "noreply@dussh.ru"}
+	)
+	svc := NewService(notify.Config{MailSink: sink}, config.SMSProvider{}, fakeCourses{}, fakeUsers{},
+		fakePreferences{}, fakeTemplates{}, repo, nil, zap.NewNop()).(*service)
+
+	n := &notification.Notification{
+		Type:        notification.TypeEmail,
+		ContentType: notification.ContentTypeHTML,
+		To:          []string{"user@dussh.ru"},
+		Subject:     "Invoice",
+		Body:        "<p>Your invoice</p>",
+		AltBody:     "Your invoice",
+		Attachments: []notification.Attachment{{Name: "invoice.pdf", ContentType: "application/pdf", Data: []byte("%PDF")}},
+	}
+	if err := svc.deferDelivery(ctx, 1, 1, n, redeliveryDelay); err != nil {
+		t.Fatalf("failed to defer delivery: %v", err)
+	}
+
+	// the event reaches NotifyDeferred through the broker as json
+	data, err := json.Marshal(repo.deferred[0].event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var e models.DeferredNotificationEvent
+	if err := json.Unmarshal(data, &e); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.NotifyDeferred(ctx, e); err != nil {
+		t.Fatalf("failed to send deferred notification: %v", err)
+	}
+
+	messages := sink.Messages()
+	if len(messages) != 1 {
+		t.Fatalf("sent %d emails, want 1", len(messages))
+	}
+	got := messages[0]
+	if got.Text != n.AltBody || got.HTML != n.Body {
+		t.Errorf("sent text %q and html %q, want %q and %q", got.Text, got.HTML, n.AltBody, n.Body)
+	}
+	if len(got.Attachments) != 1 || got.Attachments[0] != "invoice.pdf" {
+		t.Errorf("sent attachments %v, want invoice.pdf", got.Attachments)
+	}
+}
+
+func TestVerifyPhone(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		repo    = &fakeRepo{attempts: make(map[int64]models.NotificationStatus)}
+		gateway = &recordingGateway{}
+	)
+	svc := NewService(
+		notify.Config{SMS: &sms.NotificationProvider{From: "DUSSH", Gateway: gateway}},
+		config.SMSProvider{OTPTTL: time.Minute, OTPResendInterval: time.Minute, OTPMaxAttempts: 3},
+		fakeCourses{},
+		fakeUsers{},
+		fakePreferences{},
+		fakeTemplates{},
+		repo,
+		&fakeCache{values: make(map[string]string)},
+		zap.NewNop(),
+	)
+
+	if err := svc.SendPhoneVerification(ctx, 1); err != nil {
+		t.Fatalf("failed to send phone verification: %v", err)
+	}
+	if err := svc.SendPhoneVerification(ctx, 1); !errors.Is(err, domainerrors.ErrOTPResendTooSoon) {
+		t.Fatalf("resend = %v, want %v", err, domainerrors.ErrOTPResendTooSoon)
+	}
+	if len(gateway.sent) != 1 || gateway.sent[0].To != phoneOf(1) {
+		t.Fatalf("sent %+v, want one code to the phone of the user", gateway.sent)
+	}
+
+	code := regexp.MustCompile(`\d{6}`).FindString(gateway.sent[0].Text)
+	if err := svc.VerifyPhone(ctx, 1, "-"+code); !errors.Is(err, domainerrors.ErrOTPInvalid) {
+		t.Fatalf("verify with a wrong code = %v, want %v", err, domainerrors.ErrOTPInvalid)
+	}
+	if err := svc.VerifyPhone(ctx, 1, code); err != nil {
+		t.Fatalf("failed to verify phone: %v", err)
+	}
+	if len(repo.verified) != 1 || repo.verified[0] != 1 {
+		t.Errorf("verified %v, want the user", repo.verified)
+	}
+
+	// a code is accepted once
+	if err := svc.VerifyPhone(ctx, 1, code); !errors.Is(err, domainerrors.ErrOTPInvalid) {
+		t.Errorf("verify with a used code = %v, want %v", err, domainerrors.ErrOTPInvalid)
+	}
+}
+
+func TestScheduleChangeFailsIfNothingDelivered(t *testing.T) {
+	repo := &fakeRepo{userIDs: []int64{1, 2}, createErr: errors.New("database is down")}
+	svc := newTestService(repo, failingGateway{})
+
+	if err := svc.NotifyScheduleChange(context.Background(), models.ScheduleChangedEvent{CourseID: 1}); err == nil {
+		t.Fatal("notified without a single delivery, want the event to be retried")
+	}
+}
+
+func TestMedicalCertificateExpiring(t *testing.T) {
+	repo := &fakeRepo{trainerIDs: []int64{2}, courseIDs: []int64{10, 11}}
+	svc := newTestService(repo, failingGateway{})
+
+	e := models.MedicalCertificateExpiringEvent{UserID: 1, CertificateID: 5, ExpiresAt: time.Now().Add(24 * time.Hour)}
+	if err := svc.NotifyMedicalCertificateExpiring(context.Background(), e); err != nil {
+		t.Fatalf("failed to notify about expiring medical certificate: %v", err)
+	}
+
+	if len(repo.digest) != 1 {
+		t.Fatalf("collected %d digest items, want the notice of the user", len(repo.digest))
+	}
+	if got := repo.digest[0]; got.UserID != 1 || got.Category != models.CategoryMedicalCertificate {
+		t.Errorf("digest item of user %d in %q, want user 1 in %q", got.UserID, got.Category, models.CategoryMedicalCertificate)
+	}
+
+	// the trainer is notified once per course of the user
+	if repo.deliveries != 2 || repo.attempts[1] != models.NotificationSent || repo.attempts[2] != models.NotificationSent {
+		t.Errorf("deliveries = %d, attempts = %v, want 2 sent to the trainer", repo.deliveries, repo.attempts)
+	}
+}
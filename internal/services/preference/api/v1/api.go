@@ -0,0 +1,115 @@
+package v1
+
+import (
+	"context"
+	domainerrors "dussh/internal/domain/errors"
+	"dussh/internal/domain/models"
+	"dussh/internal/domain/response"
+	"dussh/internal/services/preference"
+	"dussh/pkg/jwt"
+	"dussh/pkg/validator"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type Service interface {
+	// Get returns the preferences of the user, the defaults if none are saved.
+	Get(ctx context.Context, userID int64) (*models.NotificationPreferences, error)
+	Update(ctx context.Context, prefs *models.NotificationPreferences) (*models.NotificationPreferences, error)
+}
+
+func NewPreferenceAPI(service Service, log *zap.Logger) preference.Api {
+	return &preferenceAPI{
+		svc: service,
+		log: log.Named("preference.api"),
+	}
+}
+
+type preferenceAPI struct {
+	svc Service
+
+	log *zap.Logger
+}
+
+// UpdateRequest replaces the preferences. A category left out gets the
+// default channels, an empty list of channels opts out of the category.
+// Notifications are rendered in the default locale if none is given, and
+// categories left out of delivery are sent instantly.
+type UpdateRequest struct {
+	Channels   map[models.NotificationCategory][]models.NotificationChannel `json:"channels"`
+	QuietHours *models.QuietHours                                           `json:"quiet_hours" validate:"omitempty"`
+	Locale     string                                                       `json:"locale"`
+	Delivery   map[models.NotificationCategory]models.DeliveryMode          `json:"delivery"`
+}
+
+func (a *preferenceAPI) Get(c *gin.Context) {
+	claims, ok := jwt.UserClaimsFromContext(c)
+	if !ok {
+		response.New(http.StatusUnauthorized, domainerrors.ErrUnauthenticated.Error()).Error(c)
+		return
+	}
+
+	prefs, err := a.svc.Get(c, claims.ID)
+	if err != nil {
+		response.InternalError(c, err)
+		return
+	}
+
+	response.New(
+		http.StatusOK,
+		"get notification preferences successfully",
+		response.WithValues(map[string]any{"preferences": prefs}),
+	).OK(c)
+}
+
+func (a *preferenceAPI) Update(c *gin.Context) {
+	claims, ok := jwt.UserClaimsFromContext(c)
+	if !ok {
+		response.New(http.StatusUnauthorized, domainerrors.ErrUnauthenticated.Error()).Error(c)
+		return
+	}
+
+	var req UpdateRequest
+	if err := c.BindJSON(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	if validateErrors := validator.StructValidate(req); validateErrors != nil {
+		response.BadRequest(c, validateErrors)
+		return
+	}
+
+	prefs, err := a.svc.Update(c, &models.NotificationPreferences{
+		UserID:     claims.ID,
+		Channels:   req.Channels,
+		QuietHours: req.QuietHours,
+		Locale:     req.Locale,
+		Delivery:   req.Delivery,
+	})
+	if err != nil {
+		statusError(c, err)
+		return
+	}
+
+	response.New(
+		http.StatusOK,
+		"notification preferences updated successfully",
+		response.WithValues(map[string]any{"preferences": prefs}),
+	).OK(c)
+}
+
+func statusError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domainerrors.ErrUnknownNotificationCategory),
+		errors.Is(err, domainerrors.ErrUnknownNotificationChannel),
+		errors.Is(err, domainerrors.ErrUnknownLocale),
+		errors.Is(err, domainerrors.ErrUnknownDeliveryMode),
+		errors.Is(err, models.ErrInvalidQuietHours):
+		response.New(http.StatusBadRequest, err.Error()).Error(c)
+	default:
+		response.InternalError(c, err)
+	}
+}
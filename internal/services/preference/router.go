@@ -0,0 +1,41 @@
+package preference
+
+import (
+	"dussh/internal/domain/models"
+	"dussh/internal/services/auth"
+	"github.com/gin-gonic/gin"
+)
+
+type Api interface {
+	Get(c *gin.Context)
+	Update(c *gin.Context)
+}
+
+func InitRoutes(
+	routeGroup *gin.RouterGroup,
+	api Api,
+	secretKey string,
+) {
+	var routes = []models.Route{
+		{
+			Method: "GET",
+			Path:   "notifications/preferences",
+			Handlers: []gin.HandlerFunc{
+				auth.JWTAuth(secretKey),
+				api.Get,
+			},
+		},
+		{
+			Method: "PUT",
+			Path:   "notifications/preferences",
+			Handlers: []gin.HandlerFunc{
+				auth.JWTAuth(secretKey),
+				api.Update,
+			},
+		},
+	}
+
+	for _, r := range routes {
+		routeGroup.Handle(r.Method, r.Path, r.Handlers...)
+	}
+}
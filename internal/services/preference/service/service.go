@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	domainerrors "dussh/internal/domain/errors"
+	"dussh/internal/domain/models"
+	"dussh/internal/repository"
+	preferencev1 "dussh/internal/services/preference/api/v1"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"slices"
+)
+
+type Repository interface {
+	GetNotificationPreferences(ctx context.Context, userID int64) (*models.NotificationPreferences, error)
+	SaveNotificationPreferences(ctx context.Context, prefs *models.NotificationPreferences) error
+}
+
+func NewPreferenceService(repo Repository, log *zap.Logger) preferencev1.Service {
+	return &preferenceService{
+		repo: repo,
+		log:  log.Named("preference.service"),
+	}
+}
+
+type preferenceService struct {
+	repo Repository
+
+	log *zap.Logger
+}
+
+func (s *preferenceService) Get(ctx context.Context, userID int64) (*models.NotificationPreferences, error) {
+	prefs, err := s.repo.GetNotificationPreferences(ctx, userID)
+	if errors.Is(err, repository.ErrPreferencesNotFound) {
+		return models.DefaultNotificationPreferences(userID), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// categories added after the preferences were saved get the defaults
+	defaults := models.DefaultNotificationPreferences(userID)
+	for category, channels := range defaults.Channels {
+		if _, ok := prefs.Channels[category]; !ok {
+			prefs.Channels[category] = channels
+		}
+	}
+
+	if prefs.Delivery == nil {
+		prefs.Delivery = defaults.Delivery
+	}
+
+	return prefs, nil
+}
+
+func (s *preferenceService) Update(
+	ctx context.Context,
+	prefs *models.NotificationPreferences,
+) (*models.NotificationPreferences, error) {
+	if err := validate(prefs); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SaveNotificationPreferences(ctx, prefs); err != nil {
+		return nil, err
+	}
+
+	s.log.Info("notification preferences updated", zap.Int64("user_id", prefs.UserID))
+	return s.Get(ctx, prefs.UserID)
+}
+
+func validate(prefs *models.NotificationPreferences) error {
+	if prefs.Channels == nil {
+		prefs.Channels = make(map[models.NotificationCategory][]models.NotificationChannel)
+	}
+
+	for category, channels := range prefs.Channels {
+		if !slices.Contains(models.NotificationCategories, category) {
+			return fmt.Errorf("%w %q", domainerrors.ErrUnknownNotificationCategory, category)
+		}
+		for _, channel := range channels {
+			if !slices.Contains(models.NotificationChannels, channel) {
+				return fmt.Errorf("%w %q", domainerrors.ErrUnknownNotificationChannel, channel)
+			}
+		}
+		// an empty list is kept to opt out of the category
+		channels = append([]models.NotificationChannel{}, channels...)
+		slices.Sort(channels)
+		prefs.Channels[category] = slices.Compact(channels)
+	}
+
+	if prefs.Delivery == nil {
+		prefs.Delivery = make(map[models.NotificationCategory]models.DeliveryMode)
+	}
+
+	for category, mode := range prefs.Delivery {
+		if !slices.Contains(models.NotificationCategories, category) {
+			return fmt.Errorf("%w %q", domainerrors.ErrUnknownNotificationCategory, category)
+		}
+		if !slices.Contains(models.DeliveryModes, mode) {
+			return fmt.Errorf("%w %q", domainerrors.ErrUnknownDeliveryMode, mode)
+		}
+	}
+
+	if prefs.Locale == "" {
+		prefs.Locale = models.DefaultLocale
+	}
+	if !slices.Contains(models.Locales, prefs.Locale) {
+		return fmt.Errorf("%w %q", domainerrors.ErrUnknownLocale, prefs.Locale)
+	}
+
+	if prefs.QuietHours != nil {
+		return prefs.QuietHours.Validate()
+	}
+	return nil
+}
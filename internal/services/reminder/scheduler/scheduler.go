@@ -0,0 +1,184 @@
+package scheduler
+
+import (
+	"context"
+	"dussh/internal/cache/redis"
+	"dussh/internal/config"
+	"dussh/internal/domain/models"
+	"errors"
+	"go.uber.org/zap"
+	"time"
+)
+
+var ErrSchedulerClosed = errors.New("reminder scheduler closed")
+
+const lockKey = "reminder:scheduler:lock"
+
+type Repository interface {
+	GetCourses(ctx context.Context) ([]*models.Course, error)
+	GetCourse(ctx context.Context, courseID int64) (*models.Course, error)
+	GetCourseUserIDs(ctx context.Context, courseID int64) ([]int64, error)
+	SaveSessionReminder(ctx context.Context, reminder *models.SessionReminder) (bool, error)
+	QueueExpiringMedicalCertificates(ctx context.Context, now, before time.Time) (int, error)
+}
+
+// Scheduler scans upcoming sessions and enqueues a reminder for every
+// enrolled user at each of the configured offsets before a session, and a
+// notice for every medical certificate about to expire. The repository
+// enqueues each of them once, so overlapping scans are harmless.
+type Scheduler struct {
+	repo  Repository
+	cache redis.Cache
+	cfg   config.Reminder
+
+	log *zap.Logger
+}
+
+func New(repo Repository, cache redis.Cache, cfg config.Reminder, log *zap.Logger) *Scheduler {
+	return &Scheduler{
+		repo:  repo,
+		cache: cache,
+		cfg:   cfg,
+		log:   log.Named("reminder.scheduler"),
+	}
+}
+
+// Run scans for due reminders every interval until the context is canceled.
+func (s *Scheduler) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.scan(ctx); err != nil && ctx.Err() == nil {
+			s.log.Error("failed to schedule reminders", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ErrSchedulerClosed
+		case <-ticker.C:
+		}
+	}
+}
+
+// scan schedules the reminders under the lock, instances that don't get the
+// lock skip the scan.
+func (s *Scheduler) scan(ctx context.Context) error {
+	token, ok, err := s.cache.TryLock(ctx, lockKey, s.cfg.LockTTL)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		s.log.Debug("reminders are scheduled by another instance")
+		return nil
+	}
+	defer func() {
+		if err := s.cache.Unlock(context.WithoutCancel(ctx), lockKey, token); err != nil {
+			s.log.Error("failed to release reminder lock", zap.Error(err))
+		}
+	}()
+
+	// the scan stops before the lock expires and another instance takes over
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.LockTTL)
+	defer cancel()
+
+	now := wallClockNow()
+
+	n, err := s.Schedule(ctx, now)
+	if n > 0 {
+		s.log.Info("reminders scheduled", zap.Int("count", n))
+	}
+	if err != nil {
+		return err
+	}
+
+	n, err = s.repo.QueueExpiringMedicalCertificates(ctx, now, now.Add(s.cfg.CertificateNotice))
+	if n > 0 {
+		s.log.Info("expiring medical certificates queued", zap.Int("count", n))
+	}
+	return err
+}
+
+// Schedule enqueues the reminders due at now and returns how many were
+// enqueued. now is the wall clock time in UTC, as session starts are stored.
+func (s *Scheduler) Schedule(ctx context.Context, now time.Time) (int, error) {
+	var maxOffset time.Duration
+	for _, offset := range s.cfg.Offsets {
+		maxOffset = max(maxOffset, offset)
+	}
+
+	courses, err := s.repo.GetCourses(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var scheduled int
+	for _, c := range courses {
+		if c.Status != models.Published && c.Status != models.EnrollmentClosed {
+			continue
+		}
+
+		course, err := s.repo.GetCourse(ctx, c.ID)
+		if err != nil {
+			return scheduled, err
+		}
+
+		var reminders []*models.SessionReminder
+		for _, event := range course.Events {
+			for _, at := range event.Occurrences(now, now.Add(maxOffset+time.Second)) {
+				for _, offset := range s.cfg.Offsets {
+					if !Due(at, now, offset, s.cfg.MaxDelay) {
+						continue
+					}
+					reminders = append(reminders, &models.SessionReminder{
+						CourseID: course.ID,
+						EventID:  event.ID,
+						StartsAt: at,
+						Offset:   offset,
+					})
+				}
+			}
+		}
+		if len(reminders) == 0 {
+			continue
+		}
+
+		userIDs, err := s.repo.GetCourseUserIDs(ctx, course.ID)
+		if err != nil {
+			return scheduled, err
+		}
+
+		for _, reminder := range reminders {
+			for _, userID := range userIDs {
+				r := *reminder
+				r.UserID = userID
+
+				saved, err := s.repo.SaveSessionReminder(ctx, &r)
+				if err != nil {
+					return scheduled, err
+				}
+				if saved {
+					scheduled++
+				}
+			}
+		}
+	}
+
+	return scheduled, nil
+}
+
+// Due reports whether the reminder of the session starting at is due at now:
+// from the offset before the session until maxDelay later, and never once the
+// session has started.
+func Due(at, now time.Time, offset, maxDelay time.Duration) bool {
+	remindAt := at.Add(-offset)
+
+	return !now.Before(remindAt) && now.Before(remindAt.Add(maxDelay)) && now.Before(at)
+}
+
+// wallClockNow returns the local wall clock time in UTC, event start dates are
+// stored without a time zone and are read as UTC.
+func wallClockNow() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute(), now.Second(), 0, time.UTC)
+}
@@ -0,0 +1,93 @@
+package scheduler
+
+import (
+	"context"
+	"dussh/internal/config"
+	"dussh/internal/domain/models"
+	"fmt"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+// fakeRepository serves a single course and remembers enqueued reminders.
+type fakeRepository struct {
+	course   *models.Course
+	userIDs  []int64
+	enqueued map[string]bool
+}
+
+func (r *fakeRepository) GetCourses(context.Context) ([]*models.Course, error) {
+	return []*models.Course{{ID: r.course.ID, Status: r.course.Status}}, nil
+}
+
+func (r *fakeRepository) GetCourse(context.Context, int64) (*models.Course, error) {
+	return r.course, nil
+}
+
+func (r *fakeRepository) GetCourseUserIDs(context.Context, int64) ([]int64, error) {
+	return r.userIDs, nil
+}
+
+func (r *fakeRepository) SaveSessionReminder(_ context.Context, reminder *models.SessionReminder) (bool, error) {
+	key := fmt.Sprint(reminder.EventID, reminder.UserID, reminder.StartsAt.Unix(), reminder.Offset)
+	if r.enqueued[key] {
+		return false, nil
+	}
+	r.enqueued[key] = true
+	return true, nil
+}
+
+func (r *fakeRepository) QueueExpiringMedicalCertificates(context.Context, time.Time, time.Time) (int, error) {
+	return 0, nil
+}
+
+func TestSchedule(t *testing.T) {
+	var (
+		start          = models.MyTime(time.Date(2025, 9, 1, 18, 0, 0, 0, time.UTC))
+		count, freq    = int64(10), int64(1)
+		period         = models.Week
+		ctx            = context.Background()
+		repo           = &fakeRepository{enqueued: map[string]bool{}, userIDs: []int64{1, 2}}
+		cfg            = config.Reminder{Offsets: []time.Duration{24 * time.Hour, 2 * time.Hour}, MaxDelay: 30 * time.Minute}
+		scheduler      = New(repo, nil, cfg, zap.NewNop())
+		secondSession  = time.Date(2025, 9, 8, 18, 0, 0, 0, time.UTC)
+		beforeReminder = secondSession.Add(-25 * time.Hour)
+	)
+	repo.course = &models.Course{
+		ID:     1,
+		Status: models.Published,
+		Events: []*models.Event{{
+			ID:             7,
+			StartDate:      &start,
+			RecurrentCount: &count,
+			PeriodFreq:     &freq,
+			PeriodType:     &period,
+			CourseID:       1,
+		}},
+	}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want int
+	}{
+		{"nothing due yet", beforeReminder, 0},
+		{"day before", secondSession.Add(-24 * time.Hour), 2},
+		{"enqueued once", secondSession.Add(-24*time.Hour + time.Minute), 0},
+		{"too late for the day before", secondSession.Add(-23 * time.Hour), 0},
+		{"two hours before", secondSession.Add(-2*time.Hour + 10*time.Minute), 2},
+		{"session started", secondSession, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := scheduler.Schedule(ctx, tt.now)
+			if err != nil {
+				t.Fatalf("failed to schedule: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("scheduled %d reminders, want %d", got, tt.want)
+			}
+		})
+	}
+}
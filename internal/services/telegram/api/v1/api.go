@@ -0,0 +1,101 @@
+package v1
+
+import (
+	"context"
+	domainerrors "dussh/internal/domain/errors"
+	"dussh/internal/domain/models"
+	"dussh/internal/domain/response"
+	"dussh/internal/repository"
+	"dussh/internal/services/telegram"
+	"dussh/pkg/jwt"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type Service interface {
+	CreateLinkCode(ctx context.Context, userID int64) (*models.TelegramLinkCode, error)
+	GetLink(ctx context.Context, userID int64) (*models.TelegramLink, error)
+	Unlink(ctx context.Context, userID int64) error
+}
+
+func NewTelegramAPI(service Service, log *zap.Logger) telegram.Api {
+	return &telegramAPI{
+		svc: service,
+		log: log.Named("telegram.api"),
+	}
+}
+
+type telegramAPI struct {
+	svc Service
+
+	log *zap.Logger
+}
+
+// CreateLinkCode returns a one-time code the user sends to the bot to link the chat.
+func (a *telegramAPI) CreateLinkCode(c *gin.Context) {
+	claims, ok := jwt.UserClaimsFromContext(c)
+	if !ok {
+		response.New(http.StatusUnauthorized, domainerrors.ErrUnauthenticated.Error()).Error(c)
+		return
+	}
+
+	code, err := a.svc.CreateLinkCode(c, claims.ID)
+	if err != nil {
+		statusError(c, err)
+		return
+	}
+
+	response.New(
+		http.StatusCreated,
+		"telegram link code created successfully",
+		response.WithValues(map[string]any{"link_code": code}),
+	).OK(c)
+}
+
+func (a *telegramAPI) GetLink(c *gin.Context) {
+	claims, ok := jwt.UserClaimsFromContext(c)
+	if !ok {
+		response.New(http.StatusUnauthorized, domainerrors.ErrUnauthenticated.Error()).Error(c)
+		return
+	}
+
+	link, err := a.svc.GetLink(c, claims.ID)
+	if err != nil {
+		statusError(c, err)
+		return
+	}
+
+	response.New(
+		http.StatusOK,
+		"get telegram link successfully",
+		response.WithValues(map[string]any{"link": link}),
+	).OK(c)
+}
+
+func (a *telegramAPI) Unlink(c *gin.Context) {
+	claims, ok := jwt.UserClaimsFromContext(c)
+	if !ok {
+		response.New(http.StatusUnauthorized, domainerrors.ErrUnauthenticated.Error()).Error(c)
+		return
+	}
+
+	if err := a.svc.Unlink(c, claims.ID); err != nil {
+		statusError(c, err)
+		return
+	}
+
+	response.New(http.StatusOK, "telegram unlinked successfully").OK(c)
+}
+
+func statusError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrTelegramNotLinked):
+		response.New(http.StatusNotFound, err.Error()).Error(c)
+	case errors.Is(err, domainerrors.ErrTelegramDisabled):
+		response.New(http.StatusServiceUnavailable, err.Error()).Error(c)
+	default:
+		response.InternalError(c, err)
+	}
+}
@@ -0,0 +1,50 @@
+package telegram
+
+import (
+	"dussh/internal/domain/models"
+	"dussh/internal/services/auth"
+	"github.com/gin-gonic/gin"
+)
+
+type Api interface {
+	CreateLinkCode(c *gin.Context)
+	GetLink(c *gin.Context)
+	Unlink(c *gin.Context)
+}
+
+func InitRoutes(
+	routeGroup *gin.RouterGroup,
+	api Api,
+	secretKey string,
+) {
+	var routes = []models.Route{
+		{
+			Method: "POST",
+			Path:   "telegram/link-code",
+			Handlers: []gin.HandlerFunc{
+				auth.JWTAuth(secretKey),
+				api.CreateLinkCode,
+			},
+		},
+		{
+			Method: "GET",
+			Path:   "telegram/link",
+			Handlers: []gin.HandlerFunc{
+				auth.JWTAuth(secretKey),
+				api.GetLink,
+			},
+		},
+		{
+			Method: "DELETE",
+			Path:   "telegram/link",
+			Handlers: []gin.HandlerFunc{
+				auth.JWTAuth(secretKey),
+				api.Unlink,
+			},
+		},
+	}
+
+	for _, r := range routes {
+		routeGroup.Handle(r.Method, r.Path, r.Handlers...)
+	}
+}
@@ -0,0 +1,401 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"dussh/internal/cache/redis"
+	"dussh/internal/config"
+	domainerrors "dussh/internal/domain/errors"
+	"dussh/internal/domain/models"
+	telegramv1 "dussh/internal/services/telegram/api/v1"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	linkCodeKeyPrefix     = "telegram:link:"
+	linkAttemptsKeyPrefix = "telegram:link-attempts:"
+	// linkCodeAlphabet leaves out characters that are easily confused,
+	// a code of linkCodeLength characters has 2^50 values.
+	linkCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	linkCodeLength   = 10
+	scheduleDays     = 7
+)
+
+// Replies of the bot.
+const (
+	replyHelp = "Я присылаю уведомления школы.\n\n" +
+		"Чтобы привязать аккаунт, получите код в профиле и отправьте его мне.\n\n" +
+		"/schedule — занятия на неделю\n" +
+		"/balance — стоимость занятий в месяц\n" +
+		"/unlink — отвязать этот чат"
+	replyNotLinked     = "Чат не привязан к аккаунту. Получите код в профиле и отправьте его мне."
+	replyInvalidCode   = "Код не найден или устарел. Получите новый код в профиле."
+	replyTooManyCodes  = "Слишком много неверных кодов. Попробуйте позже."
+	replyLinked        = "Аккаунт %s привязан, уведомления будут приходить в этот чат."
+	replyUnlinked      = "Чат отвязан, уведомления больше не будут приходить."
+	replyNoLessons     = "На ближайшей неделе занятий нет."
+	replyNoCourses     = "Нет записей на курсы."
+	replyInternalError = "Что-то пошло не так, попробуйте позже."
+)
+
+type Repository interface {
+	SaveTelegramLink(ctx context.Context, userID, chatID int64) error
+	DeleteTelegramLink(ctx context.Context, userID int64) error
+	GetTelegramLink(ctx context.Context, userID int64) (*models.TelegramLink, error)
+	GetTelegramLinksByChat(ctx context.Context, chatID int64) ([]*models.TelegramLink, error)
+	GetUserCourseIDs(ctx context.Context, userID int64) ([]int64, error)
+	GetCourse(ctx context.Context, courseID int64) (*models.Course, error)
+	GetUserByID(ctx context.Context, id int64) (*models.User, error)
+}
+
+// Service links accounts to telegram chats and answers bot commands.
+type Service interface {
+	telegramv1.Service
+	// HandleMessage returns the reply to a message sent to the bot.
+	HandleMessage(ctx context.Context, chatID int64, text string) string
+}
+
+func NewTelegramService(
+	repo Repository,
+	cache redis.Cache,
+	cfg config.TelegramProvider,
+	log *zap.Logger,
+) Service {
+	return &telegramService{
+		repo:  repo,
+		cache: cache,
+		cfg:   cfg,
+		log:   log.Named("telegram.service"),
+	}
+}
+
+type telegramService struct {
+	repo  Repository
+	cache redis.Cache
+	cfg   config.TelegramProvider
+
+	log *zap.Logger
+}
+
+func (s *telegramService) CreateLinkCode(ctx context.Context, userID int64) (*models.TelegramLinkCode, error) {
+	if s.cfg.Token == "" {
+		return nil, domainerrors.ErrTelegramDisabled
+	}
+
+	// retry on the unlikely collision with a pending code of another user
+	for attempt := 0; attempt < 3; attempt++ {
+		code, err := newLinkCode()
+		if err != nil {
+			return nil, err
+		}
+
+		stored, err := s.cache.SetNX(ctx, linkCodeKeyPrefix+code, userID, s.cfg.LinkCodeTTL)
+		if err != nil {
+			s.log.Error("failed to store telegram link code", zap.Error(err))
+			return nil, err
+		}
+		if !stored {
+			continue
+		}
+
+		linkCode := &models.TelegramLinkCode{
+			Code:      code,
+			ExpiresAt: time.Now().Add(s.cfg.LinkCodeTTL).UTC(),
+		}
+		if s.cfg.BotUsername != "" {
+			linkCode.BotURL = "https://t.me/" + s.cfg.BotUsername + "?start=" + code
+		}
+
+		return linkCode, nil
+	}
+
+	return nil, errors.New("failed to generate a unique telegram link code")
+}
+
+func (s *telegramService) GetLink(ctx context.Context, userID int64) (*models.TelegramLink, error) {
+	return s.repo.GetTelegramLink(ctx, userID)
+}
+
+func (s *telegramService) Unlink(ctx context.Context, userID int64) error {
+	if err := s.repo.DeleteTelegramLink(ctx, userID); err != nil {
+		return err
+	}
+
+	s.log.Info("telegram unlinked", zap.Int64("user_id", userID))
+	return nil
+}
+
+func (s *telegramService) HandleMessage(ctx context.Context, chatID int64, text string) string {
+	command, arg := parseCommand(text)
+
+	var (
+		reply string
+		err   error
+	)
+	switch command {
+	case "start", "link":
+		if arg == "" {
+			return replyHelp
+		}
+		reply, err = s.link(ctx, chatID, arg)
+	case "schedule":
+		reply, err = s.schedule(ctx, chatID)
+	case "balance":
+		reply, err = s.balance(ctx, chatID)
+	case "unlink":
+		reply, err = s.unlinkChat(ctx, chatID)
+	default:
+		return replyHelp
+	}
+
+	if err != nil {
+		s.log.Error("failed to handle telegram command",
+			zap.String("command", command),
+			zap.Int64("chat_id", chatID),
+			zap.Error(err),
+		)
+		return replyInternalError
+	}
+
+	return reply
+}
+
+// link consumes the one-time code and links the user it was issued to. A chat
+// that sent too many wrong codes is refused until the attempts expire, so
+// pending codes can't be guessed.
+func (s *telegramService) link(ctx context.Context, chatID int64, code string) (string, error) {
+	attemptsKey := linkAttemptsKeyPrefix + strconv.FormatInt(chatID, 10)
+	attempts, err := s.cache.Get(ctx, attemptsKey)
+	if err != nil && !errors.Is(err, redis.ErrNotFound) {
+		return "", err
+	}
+	if n, _ := strconv.Atoi(attempts); n >= s.cfg.LinkAttempts {
+		return replyTooManyCodes, nil
+	}
+
+	value, err := s.cache.GetDel(ctx, linkCodeKeyPrefix+strings.ToUpper(code))
+	if errors.Is(err, redis.ErrNotFound) {
+		if _, err := s.cache.Incr(ctx, attemptsKey, s.cfg.LinkCodeTTL); err != nil {
+			return "", err
+		}
+		return replyInvalidCode, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	userID, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.repo.SaveTelegramLink(ctx, userID, chatID); err != nil {
+		return "", err
+	}
+
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	s.log.Info("telegram linked", zap.Int64("user_id", userID), zap.Int64("chat_id", chatID))
+	return fmt.Sprintf(replyLinked, fullName(user)), nil
+}
+
+func (s *telegramService) unlinkChat(ctx context.Context, chatID int64) (string, error) {
+	links, err := s.repo.GetTelegramLinksByChat(ctx, chatID)
+	if err != nil {
+		return "", err
+	}
+	if len(links) == 0 {
+		return replyNotLinked, nil
+	}
+
+	for _, link := range links {
+		if err := s.Unlink(ctx, link.UserID); err != nil {
+			return "", err
+		}
+	}
+
+	return replyUnlinked, nil
+}
+
+type lesson struct {
+	at     time.Time
+	course string
+	name   string
+}
+
+// schedule lists the lessons of the linked users for the coming week.
+func (s *telegramService) schedule(ctx context.Context, chatID int64) (string, error) {
+	return s.perUser(ctx, chatID, func(user *models.User, courses []*models.Course) string {
+		from := wallClockNow()
+		to := from.AddDate(0, 0, scheduleDays)
+
+		var lessons []lesson
+		for _, course := range courses {
+			for _, event := range course.Events {
+				for _, at := range event.Occurrences(from, to) {
+					lessons = append(lessons, lesson{at: at, course: course.Name, name: event.Description})
+				}
+			}
+		}
+		if len(lessons) == 0 {
+			return replyNoLessons
+		}
+
+		sort.Slice(lessons, func(i, j int) bool { return lessons[i].at.Before(lessons[j].at) })
+
+		var b strings.Builder
+		for _, l := range lessons {
+			fmt.Fprintf(&b, "%s %s — %s: %s\n", weekdays[l.at.Weekday()], l.at.Format("02.01 15:04"), l.course, l.name)
+		}
+		return strings.TrimSuffix(b.String(), "\n")
+	})
+}
+
+// balance sums the monthly subscription costs of the courses of the linked users.
+func (s *telegramService) balance(ctx context.Context, chatID int64) (string, error) {
+	return s.perUser(ctx, chatID, func(user *models.User, courses []*models.Course) string {
+		if len(courses) == 0 {
+			return replyNoCourses
+		}
+
+		var (
+			b     strings.Builder
+			total float64
+		)
+		b.WriteString("Стоимость занятий в месяц:\n")
+		for _, course := range courses {
+			var cost float64
+			if course.MonthlySubscriptionCost != nil {
+				cost = *course.MonthlySubscriptionCost
+			}
+			total += cost
+			fmt.Fprintf(&b, "• %s — %.2f\n", course.Name, cost)
+		}
+		fmt.Fprintf(&b, "Итого: %.2f", total)
+
+		return b.String()
+	})
+}
+
+// perUser builds the reply for every user linked to the chat, the section of
+// each user is headed by the name when several users share the chat.
+func (s *telegramService) perUser(
+	ctx context.Context,
+	chatID int64,
+	section func(*models.User, []*models.Course) string,
+) (string, error) {
+	links, err := s.repo.GetTelegramLinksByChat(ctx, chatID)
+	if err != nil {
+		return "", err
+	}
+	if len(links) == 0 {
+		return replyNotLinked, nil
+	}
+
+	sections := make([]string, 0, len(links))
+	for _, link := range links {
+		user, err := s.repo.GetUserByID(ctx, link.UserID)
+		if err != nil {
+			return "", err
+		}
+
+		courses, err := s.userCourses(ctx, link.UserID)
+		if err != nil {
+			return "", err
+		}
+
+		text := section(user, courses)
+		if len(links) > 1 {
+			text = fullName(user) + ":\n" + text
+		}
+		sections = append(sections, text)
+	}
+
+	return strings.Join(sections, "\n\n"), nil
+}
+
+func (s *telegramService) userCourses(ctx context.Context, userID int64) ([]*models.Course, error) {
+	ids, err := s.repo.GetUserCourseIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	courses := make([]*models.Course, 0, len(ids))
+	for _, id := range ids {
+		course, err := s.repo.GetCourse(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if course.Status == models.Archived {
+			continue
+		}
+		courses = append(courses, course)
+	}
+
+	return courses, nil
+}
+
+// parseCommand splits "/command@bot argument" into its parts. A bare link
+// code is treated as the link command.
+func parseCommand(text string) (string, string) {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "/") {
+		if isLinkCode(text) {
+			return "link", text
+		}
+		return "", text
+	}
+
+	command, arg, _ := strings.Cut(strings.TrimPrefix(text, "/"), " ")
+	command, _, _ = strings.Cut(command, "@")
+
+	return strings.ToLower(command), strings.TrimSpace(arg)
+}
+
+func isLinkCode(text string) bool {
+	if len(text) != linkCodeLength {
+		return false
+	}
+	for _, r := range strings.ToUpper(text) {
+		if !strings.ContainsRune(linkCodeAlphabet, r) {
+			return false
+		}
+	}
+	return true
+}
+
+func newLinkCode() (string, error) {
+	b := make([]byte, linkCodeLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	// the alphabet has 32 characters, so every byte maps without bias
+	for i := range b {
+		b[i] = linkCodeAlphabet[int(b[i])%len(linkCodeAlphabet)]
+	}
+
+	return string(b), nil
+}
+
+// wallClockNow returns the local wall clock time in UTC, event start dates are
+// stored without a time zone and are read as UTC.
+func wallClockNow() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute(), now.Second(), 0, time.UTC)
+}
+
+func fullName(user *models.User) string {
+	return strings.Join([]string{user.Surname, user.FirstName, user.MiddleName}, " ")
+}
+
+var weekdays = [...]string{"Вс", "Пн", "Вт", "Ср", "Чт", "Пт", "Сб"}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"dussh/internal/cache/redis"
+	"dussh/internal/config"
+	"dussh/internal/domain/models"
+	"fmt"
+	"go.uber.org/zap"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type fakeCache struct {
+	redis.Cache
+
+	values map[string]string
+}
+
+func (c *fakeCache) Get(_ context.Context, key string) (string, error) {
+	value, ok := c.values[key]
+	if !ok {
+		return "", redis.ErrNotFound
+	}
+	return value, nil
+}
+
+func (c *fakeCache) SetNX(_ context.Context, key string, value any, _ time.Duration) (bool, error) {
+	if _, ok := c.values[key]; ok {
+		return false, nil
+	}
+	c.values[key] = fmt.Sprint(value)
+	return true, nil
+}
+
+func (c *fakeCache) GetDel(ctx context.Context, key string) (string, error) {
+	value, err := c.Get(ctx, key)
+	delete(c.values, key)
+	return value, err
+}
+
+func (c *fakeCache) Incr(_ context.Context, key string, _ time.Duration) (int64, error) {
+	n, _ := strconv.ParseInt(c.values[key], 10, 64)
+	n++
+	c.values[key] = strconv.FormatInt(n, 10)
+	return n, nil
+}
+
+type fakeRepo struct {
+	Repository
+
+	links map[int64]int64
+}
+
+func (r *fakeRepo) SaveTelegramLink(_ context.Context, userID, chatID int64) error {
+	r.links[userID] = chatID
+	return nil
+}
+
+func (r *fakeRepo) GetUserByID(_ context.Context, id int64) (*models.User, error) {
+	return &models.User{ID: id, Surname: "Иванов", FirstName: "Иван"}, nil
+}
+
+func TestLinkLimitsWrongCodes(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeRepo{links: make(map[int64]int64)}
+	cfg := config.TelegramProvider{Token: "token", LinkCodeTTL: time.Minute, LinkAttempts: 3}
+	svc := NewTelegramService(repo, &fakeCache{values: make(map[string]string)}, cfg, zap.NewNop())
+
+	code, err := svc.CreateLinkCode(ctx, 7)
+	if err != nil {
+		t.Fatalf("CreateLinkCode() error = %v", err)
+	}
+	if !isLinkCode(code.Code) {
+		t.Fatalf("code %q is not recognized as a link code", code.Code)
+	}
+
+	// a stranger's guesses are refused once the attempts are used up
+	for i := 0; i < cfg.LinkAttempts; i++ {
+		if reply := svc.HandleMessage(ctx, 100, "AAAAAAAAAA"); reply != replyInvalidCode {
+			t.Fatalf("guess %d reply = %q, want %q", i, reply, replyInvalidCode)
+		}
+	}
+	if reply := svc.HandleMessage(ctx, 100, code.Code); reply != replyTooManyCodes {
+		t.Fatalf("reply = %q, want %q", reply, replyTooManyCodes)
+	}
+	if _, ok := repo.links[7]; ok {
+		t.Fatal("user was linked to the guessing chat")
+	}
+
+	// the owner's chat links with the code, which works only once
+	if reply := svc.HandleMessage(ctx, 200, code.Code); reply == replyInvalidCode || reply == replyTooManyCodes {
+		t.Fatalf("link reply = %q", reply)
+	}
+	if repo.links[7] != 200 {
+		t.Fatalf("user linked to chat %d, want 200", repo.links[7])
+	}
+	if reply := svc.HandleMessage(ctx, 300, "/start "+code.Code); reply != replyInvalidCode {
+		t.Fatalf("reused code reply = %q, want %q", reply, replyInvalidCode)
+	}
+}
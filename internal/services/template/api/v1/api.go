@@ -0,0 +1,156 @@
+package v1
+
+import (
+	"context"
+	domainerrors "dussh/internal/domain/errors"
+	"dussh/internal/domain/models"
+	"dussh/internal/domain/response"
+	"dussh/internal/repository"
+	"dussh/internal/services/template"
+	"dussh/pkg/validator"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type Service interface {
+	// Render renders the template of the event type in the locale. The latest
+	// stored version is used, then the embedded default, and the default
+	// locale if the locale has neither.
+	Render(ctx context.Context, eventType, locale string, data any) (*models.RenderedTemplate, error)
+	List(ctx context.Context, eventType, locale string) ([]*models.NotificationTemplate, error)
+	// Create checks the template renders with the sample data of its event
+	// type and stores it as the next version.
+	Create(ctx context.Context, t *models.NotificationTemplate) (*models.NotificationTemplate, error)
+	// Preview renders the version of the template with sample data, the
+	// template used by Render if version is 0.
+	Preview(ctx context.Context, eventType, locale string, version int) (*models.RenderedTemplate, error)
+}
+
+func NewTemplateAPI(service Service, log *zap.Logger) template.Api {
+	return &templateAPI{
+		svc: service,
+		log: log.Named("template.api"),
+	}
+}
+
+type templateAPI struct {
+	svc Service
+
+	log *zap.Logger
+}
+
+type ListRequest struct {
+	EventType string `form:"event_type"`
+	Locale    string `form:"locale"`
+}
+
+// CreateRequest is a new version of the template, the html part is rendered
+// into the email layout if no layout is given.
+type CreateRequest struct {
+	EventType string `json:"event_type" validate:"required"`
+	Locale    string `json:"locale" validate:"required"`
+	Layout    string `json:"layout"`
+	Subject   string `json:"subject" validate:"required"`
+	Text      string `json:"text" validate:"required"`
+	HTML      string `json:"html"`
+}
+
+type PreviewRequest struct {
+	EventType string `form:"event_type" validate:"required"`
+	Locale    string `form:"locale" validate:"required"`
+	Version   int    `form:"version" validate:"omitempty,min=1"`
+}
+
+func (a *templateAPI) List(c *gin.Context) {
+	var req ListRequest
+	if err := c.BindQuery(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	templates, err := a.svc.List(c, req.EventType, req.Locale)
+	if err != nil {
+		response.InternalError(c, err)
+		return
+	}
+
+	response.New(
+		http.StatusOK,
+		"templates received successfully",
+		response.WithValues(map[string]any{"templates": templates}),
+	).OK(c)
+}
+
+func (a *templateAPI) Create(c *gin.Context) {
+	var req CreateRequest
+	if err := c.BindJSON(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	if validateErrors := validator.StructValidate(req); validateErrors != nil {
+		response.BadRequest(c, validateErrors)
+		return
+	}
+
+	t, err := a.svc.Create(c, &models.NotificationTemplate{
+		EventType: req.EventType,
+		Locale:    req.Locale,
+		Layout:    req.Layout,
+		Subject:   req.Subject,
+		Text:      req.Text,
+		HTML:      req.HTML,
+	})
+	if err != nil {
+		statusError(c, err)
+		return
+	}
+
+	response.New(
+		http.StatusOK,
+		"template created successfully",
+		response.WithValues(map[string]any{"template": t}),
+	).OK(c)
+}
+
+func (a *templateAPI) Preview(c *gin.Context) {
+	var req PreviewRequest
+	if err := c.BindQuery(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	if validateErrors := validator.StructValidate(req); validateErrors != nil {
+		response.BadRequest(c, validateErrors)
+		return
+	}
+
+	rendered, err := a.svc.Preview(c, req.EventType, req.Locale, req.Version)
+	if err != nil {
+		statusError(c, err)
+		return
+	}
+
+	response.New(
+		http.StatusOK,
+		"template rendered successfully",
+		response.WithValues(map[string]any{"preview": rendered}),
+	).OK(c)
+}
+
+func statusError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrTemplateNotFound),
+		errors.Is(err, domainerrors.ErrTemplateNotFound):
+		response.New(http.StatusNotFound, err.Error()).Error(c)
+	case errors.Is(err, domainerrors.ErrUnknownTemplateEvent),
+		errors.Is(err, domainerrors.ErrUnknownTemplateLayout),
+		errors.Is(err, domainerrors.ErrUnknownLocale),
+		errors.Is(err, domainerrors.ErrInvalidTemplate):
+		response.New(http.StatusBadRequest, err.Error()).Error(c)
+	default:
+		response.InternalError(c, err)
+	}
+}
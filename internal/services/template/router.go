@@ -0,0 +1,57 @@
+//go:generate go run /home/dmitry/dussh/pkg/rbac/rolegen
+package template
+
+import (
+	"dussh/internal/domain/models"
+	rbacmiddleware "dussh/internal/role/middleware"
+	"dussh/pkg/rbac"
+	"github.com/gin-gonic/gin"
+)
+
+type Api interface {
+	List(c *gin.Context)
+	Create(c *gin.Context)
+	Preview(c *gin.Context)
+}
+
+func InitRoutes(
+	routeGroup *gin.RouterGroup,
+	api Api,
+	roleManager rbac.RoleManager,
+	secretKey string,
+) {
+	//rolegen:routes
+	var routes = []models.Route{
+		{
+			Method: "GET",
+			Path:   "templates",
+			Role:   "admin",
+			Handlers: []gin.HandlerFunc{
+				rbacmiddleware.RoleAccess(roleManager, secretKey),
+				api.List,
+			},
+		},
+		{
+			Method: "POST",
+			Path:   "templates",
+			Role:   "admin",
+			Handlers: []gin.HandlerFunc{
+				rbacmiddleware.RoleAccess(roleManager, secretKey),
+				api.Create,
+			},
+		},
+		{
+			Method: "GET",
+			Path:   "templates/preview",
+			Role:   "admin",
+			Handlers: []gin.HandlerFunc{
+				rbacmiddleware.RoleAccess(roleManager, secretKey),
+				api.Preview,
+			},
+		},
+	}
+
+	for _, r := range routes {
+		routeGroup.Handle(r.Method, r.Path, r.Handlers...)
+	}
+}
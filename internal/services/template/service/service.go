@@ -0,0 +1,275 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	domainerrors "dussh/internal/domain/errors"
+	"dussh/internal/domain/models"
+	defaults "dussh/internal/domain/template"
+	"dussh/internal/repository"
+	templatev1 "dussh/internal/services/template/api/v1"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	htmltemplate "html/template"
+	"slices"
+	"strings"
+	texttemplate "text/template"
+)
+
+// samples are rendered into the templates of the event type on preview and
+// on create, only event types with samples can have templates.
+var samples = map[string]any{
+	models.EventTypeEnrollmentCreated: models.EnrollmentTemplateData{
+		User:       "Иванов Иван Иванович",
+		CourseName: "Плавание",
+	},
+	models.EventTypeEnrollmentCancelled: models.EnrollmentTemplateData{
+		User:       "Иванов Иван Иванович",
+		CourseName: "Плавание",
+	},
+	models.TemplateTrainerEnrollmentCreated: models.EnrollmentTemplateData{
+		User:       "Иванов Иван Иванович",
+		CourseName: "Плавание",
+	},
+	models.TemplateTrainerEnrollmentCancelled: models.EnrollmentTemplateData{
+		User:       "Иванов Иван Иванович",
+		CourseName: "Плавание",
+	},
+	models.EventTypeScheduleChanged: models.ScheduleChangeTemplateData{
+		User:       "Иванов Иван Иванович",
+		CourseName: "Плавание",
+	},
+	models.EventTypeSessionReminder: models.SessionReminderTemplateData{
+		User:       "Иванов Иван Иванович",
+		CourseName: "Плавание",
+		Session:    "Тренировка в малом бассейне",
+		StartsAt:   "01.09.2025 18:30",
+	},
+	models.EventTypeMedicalCertificateExpiring: models.MedicalCertificateTemplateData{
+		User:      "Иванов Иван Иванович",
+		ExpiresAt: "15.09.2025",
+	},
+	models.TemplateTrainerMedicalCertificate: models.MedicalCertificateTemplateData{
+		User:       "Иванов Иван Иванович",
+		CourseName: "Плавание",
+		ExpiresAt:  "15.09.2025",
+	},
+	models.EventTypeNotificationDigest: models.DigestTemplateData{
+		User:   "Петров Пётр Петрович",
+		Weekly: true,
+		Items: []models.DigestTemplateItem{
+			{Subject: "Новая запись на ваш курс", Text: "Иванов Иван Иванович записан на курс «Плавание».", At: "01.09.2025 10:15"},
+			{Subject: "Изменение расписания", Text: "Петров Пётр Петрович, расписание курса «Плавание» изменилось.", At: "02.09.2025 12:00"},
+		},
+	},
+}
+
+type Repository interface {
+	SaveNotificationTemplate(ctx context.Context, t *models.NotificationTemplate) (*models.NotificationTemplate, error)
+	GetNotificationTemplate(ctx context.Context, eventType, locale string, version int) (*models.NotificationTemplate, error)
+	GetNotificationTemplates(ctx context.Context, eventType, locale string) ([]*models.NotificationTemplate, error)
+}
+
+func NewTemplateService(repo Repository, log *zap.Logger) templatev1.Service {
+	return &templateService{
+		repo: repo,
+		log:  log.Named("template.service"),
+	}
+}
+
+type templateService struct {
+	repo Repository
+
+	log *zap.Logger
+}
+
+func (s *templateService) Render(
+	ctx context.Context,
+	eventType, locale string,
+	data any,
+) (*models.RenderedTemplate, error) {
+	t, err := s.lookup(ctx, eventType, locale)
+	if err != nil {
+		return nil, err
+	}
+
+	return render(t, data)
+}
+
+func (s *templateService) List(ctx context.Context, eventType, locale string) ([]*models.NotificationTemplate, error) {
+	return s.repo.GetNotificationTemplates(ctx, eventType, locale)
+}
+
+func (s *templateService) Create(
+	ctx context.Context,
+	t *models.NotificationTemplate,
+) (*models.NotificationTemplate, error) {
+	sample, ok := samples[t.EventType]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", domainerrors.ErrUnknownTemplateEvent, t.EventType)
+	}
+	if !slices.Contains(models.Locales, t.Locale) {
+		return nil, fmt.Errorf("%w %q", domainerrors.ErrUnknownLocale, t.Locale)
+	}
+	if t.Layout == "" {
+		t.Layout = models.DefaultLayout
+	}
+
+	if _, err := render(t, sample); err != nil {
+		return nil, err
+	}
+
+	saved, err := s.repo.SaveNotificationTemplate(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("notification template saved",
+		zap.String("event_type", saved.EventType),
+		zap.String("locale", saved.Locale),
+		zap.Int("version", saved.Version),
+	)
+	return saved, nil
+}
+
+func (s *templateService) Preview(
+	ctx context.Context,
+	eventType, locale string,
+	version int,
+) (*models.RenderedTemplate, error) {
+	sample, ok := samples[eventType]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", domainerrors.ErrUnknownTemplateEvent, eventType)
+	}
+
+	var (
+		t   *models.NotificationTemplate
+		err error
+	)
+	if version > 0 {
+		t, err = s.repo.GetNotificationTemplate(ctx, eventType, locale, version)
+	} else {
+		t, err = s.lookup(ctx, eventType, locale)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return render(t, sample)
+}
+
+// lookup returns the template used for the event type in the locale: the
+// latest stored version, else the embedded default, else the template of the
+// default locale.
+func (s *templateService) lookup(
+	ctx context.Context,
+	eventType, locale string,
+) (*models.NotificationTemplate, error) {
+	locales := []string{locale}
+	if locale != models.DefaultLocale {
+		locales = append(locales, models.DefaultLocale)
+	}
+
+	for _, locale := range locales {
+		t, err := s.repo.GetNotificationTemplate(ctx, eventType, locale, 0)
+		if err == nil {
+			return t, nil
+		}
+		if !errors.Is(err, repository.ErrTemplateNotFound) {
+			return nil, err
+		}
+
+		t, err = defaults.Default(eventType, locale)
+		if err == nil {
+			return t, nil
+		}
+		if !errors.Is(err, domainerrors.ErrTemplateNotFound) {
+			return nil, err
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", domainerrors.ErrTemplateNotFound, eventType)
+}
+
+// layoutData is rendered into the shared layouts.
+type layoutData struct {
+	Locale  string
+	Subject string
+	Content htmltemplate.HTML
+}
+
+func render(t *models.NotificationTemplate, data any) (*models.RenderedTemplate, error) {
+	subject, err := renderText("subject", t.Subject, data)
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := renderText("text", t.Text, data)
+	if err != nil {
+		return nil, err
+	}
+
+	rendered := &models.RenderedTemplate{
+		Locale:  t.Locale,
+		Version: t.Version,
+		Subject: strings.TrimSpace(subject),
+		Text:    strings.TrimSpace(text),
+	}
+	if t.HTML == "" {
+		return rendered, nil
+	}
+
+	content, err := renderHTML("html", t.HTML, data)
+	if err != nil {
+		return nil, err
+	}
+	if t.Layout == "" {
+		rendered.HTML = content
+		return rendered, nil
+	}
+
+	layout, err := defaults.Layout(t.Layout)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q", err, t.Layout)
+	}
+
+	rendered.HTML, err = renderHTML("layout", layout, layoutData{
+		Locale:  t.Locale,
+		Subject: rendered.Subject,
+		Content: htmltemplate.HTML(content),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rendered, nil
+}
+
+func renderText(name, text string, data any) (string, error) {
+	t, err := texttemplate.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", domainerrors.ErrInvalidTemplate, err)
+	}
+
+	var b bytes.Buffer
+	if err := t.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("%w: %v", domainerrors.ErrInvalidTemplate, err)
+	}
+
+	return b.String(), nil
+}
+
+func renderHTML(name, text string, data any) (string, error) {
+	t, err := htmltemplate.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", domainerrors.ErrInvalidTemplate, err)
+	}
+
+	var b bytes.Buffer
+	if err := t.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("%w: %v", domainerrors.ErrInvalidTemplate, err)
+	}
+
+	return b.String(), nil
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	domainerrors "dussh/internal/domain/errors"
+	"dussh/internal/domain/models"
+	"dussh/internal/repository"
+	"errors"
+	"go.uber.org/zap"
+	"strings"
+	"testing"
+)
+
+// fakeRepository stores the versions of every template in memory.
+type fakeRepository struct {
+	templates []*models.NotificationTemplate
+}
+
+func (r *fakeRepository) SaveNotificationTemplate(
+	_ context.Context,
+	t *models.NotificationTemplate,
+) (*models.NotificationTemplate, error) {
+	saved := *t
+	saved.Version = 1
+	for _, stored := range r.templates {
+		if stored.EventType == t.EventType && stored.Locale == t.Locale && stored.Version >= saved.Version {
+			saved.Version = stored.Version + 1
+		}
+	}
+	r.templates = append(r.templates, &saved)
+	return &saved, nil
+}
+
+func (r *fakeRepository) GetNotificationTemplate(
+	_ context.Context,
+	eventType, locale string,
+	version int,
+) (*models.NotificationTemplate, error) {
+	var latest *models.NotificationTemplate
+	for _, t := range r.templates {
+		if t.EventType != eventType || t.Locale != locale || (version > 0 && t.Version != version) {
+			continue
+		}
+		if latest == nil || t.Version > latest.Version {
+			latest = t
+		}
+	}
+	if latest == nil {
+		return nil, repository.ErrTemplateNotFound
+	}
+	return latest, nil
+}
+
+func (r *fakeRepository) GetNotificationTemplates(
+	_ context.Context,
+	_, _ string,
+) ([]*models.NotificationTemplate, error) {
+	return r.templates, nil
+}
+
+func TestRender(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeRepository{}
+	svc := NewTemplateService(repo, zap.NewNop())
+	data := models.EnrollmentTemplateData{User: "Smith <John>", CourseName: "Swimming"}
+
+	// the embedded default is used until a version is stored
+	rendered, err := svc.Render(ctx, models.EventTypeEnrollmentCreated, models.LocaleEN, data)
+	if err != nil {
+		t.Fatalf("failed to render default template: %v", err)
+	}
+	if rendered.Subject != "New course enrollment" {
+		t.Errorf("subject = %q", rendered.Subject)
+	}
+	if rendered.Text != `Smith <John>, you are enrolled in the course "Swimming".` {
+		t.Errorf("text = %q", rendered.Text)
+	}
+	for _, want := range []string{`<html lang="en">`, "Smith &lt;John&gt;", "<title>New course enrollment</title>"} {
+		if !strings.Contains(rendered.HTML, want) {
+			t.Errorf("html does not contain %q", want)
+		}
+	}
+
+	_, err = svc.Create(ctx, &models.NotificationTemplate{
+		EventType: models.EventTypeEnrollmentCreated,
+		Locale:    models.LocaleRU,
+		Subject:   "Запись: {{.CourseName}}",
+		Text:      "{{.Missing}}",
+	})
+	if !errors.Is(err, domainerrors.ErrInvalidTemplate) {
+		t.Fatalf("expected invalid template error, got %v", err)
+	}
+
+	saved, err := svc.Create(ctx, &models.NotificationTemplate{
+		EventType: models.EventTypeEnrollmentCreated,
+		Locale:    models.LocaleRU,
+		Subject:   "Запись: {{.CourseName}}",
+		Text:      "{{.User}}",
+	})
+	if err != nil {
+		t.Fatalf("failed to create template: %v", err)
+	}
+	if saved.Version != 1 || saved.Layout != models.DefaultLayout {
+		t.Errorf("unexpected saved template: %+v", saved)
+	}
+
+	// an unknown locale falls back to the stored template of the default locale
+	rendered, err = svc.Render(ctx, models.EventTypeEnrollmentCreated, "de", data)
+	if err != nil {
+		t.Fatalf("failed to render stored template: %v", err)
+	}
+	if rendered.Subject != "Запись: Swimming" || rendered.HTML != "" {
+		t.Errorf("unexpected rendered template: %+v", rendered)
+	}
+
+	if _, err := svc.Preview(ctx, "user.updated", models.LocaleRU, 0); !errors.Is(err, domainerrors.ErrUnknownTemplateEvent) {
+		t.Errorf("expected unknown event error, got %v", err)
+	}
+}
+
+func TestPreviewDefaults(t *testing.T) {
+	svc := NewTemplateService(&fakeRepository{}, zap.NewNop())
+
+	// every event type with a sample has an embedded default in every locale
+	for eventType := range samples {
+		for _, locale := range models.Locales {
+			rendered, err := svc.Preview(context.Background(), eventType, locale, 0)
+			if err != nil {
+				t.Errorf("failed to preview %s in %s: %v", eventType, locale, err)
+				continue
+			}
+			if rendered.Locale != locale || rendered.Subject == "" || rendered.Text == "" {
+				t.Errorf("unexpected preview of %s in %s: %+v", eventType, locale, rendered)
+			}
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	domainerrors "dussh/internal/domain/errors"
 	"dussh/internal/domain/models"
 	"dussh/internal/domain/response"
+	"dussh/internal/repository"
 	"dussh/internal/services/user"
 	"dussh/pkg/validator"
 	"errors"
@@ -12,6 +13,7 @@ import (
 	"go.uber.org/zap"
 	"net/http"
 	"strconv"
+	"time"
 )
 
 type Service interface {
@@ -19,9 +21,11 @@ type Service interface {
 	GetEmployeePosition(ctx context.Context, id int64) (*models.Position, error)
 	GetAllPositions(ctx context.Context) ([]*models.Position, error)
 	Create(ctx context.Context, user *models.User) (int64, error)
-	Update(ctx context.Context, id int64, user *models.User) error
-	Delete(ctx context.Context, id int64) error
+	Update(ctx context.Context, id int64, user *models.User, version int64) (int64, error)
+	Delete(ctx context.Context, id int64, version int64) error
 	List(ctx context.Context) ([]*models.User, error)
+	AddMedicalCertificate(ctx context.Context, userID int64, expiresAt time.Time) (*models.MedicalCertificate, error)
+	MedicalCertificates(ctx context.Context, userID int64) ([]*models.MedicalCertificate, error)
 }
 
 func NewUserAPI(service Service, log *zap.Logger) user.Api {
@@ -46,19 +50,11 @@ func (u *userAPI) Get(c *gin.Context) {
 
 	usr, err := u.svc.Get(c, userID)
 	if err != nil {
-		response.InternalError(c, err)
+		statusError(c, err)
 		return
 	}
 
-	userInfo := models.UserInfo{
-		ID:         usr.ID,
-		FirstName:  usr.FirstName,
-		MiddleName: usr.MiddleName,
-		Surname:    usr.Surname,
-		Email:      usr.Email,
-		Phone:      usr.Phone,
-		Role:       usr.Role,
-	}
+	userInfo := usr.Info()
 
 	if usr.Role == models.Employee {
 		position, err := u.svc.GetEmployeePosition(c, userID)
@@ -67,6 +63,7 @@ func (u *userAPI) Get(c *gin.Context) {
 		}
 	}
 
+	response.SetETag(c, usr.Version)
 	response.New(
 		http.StatusOK,
 		"get user successfully",
@@ -134,6 +131,12 @@ func (u *userAPI) Update(c *gin.Context) {
 		return
 	}
 
+	version, ok := response.IfMatch(c)
+	if !ok {
+		response.PreconditionFailed(c, repository.ErrVersionMismatch)
+		return
+	}
+
 	var req UpdateRequest
 	if err := c.BindJSON(&req); err != nil {
 		response.BadRequest(c, err)
@@ -153,11 +156,13 @@ func (u *userAPI) Update(c *gin.Context) {
 		Phone:      req.Phone,
 	}
 
-	if err := u.svc.Update(c, userID, usr); err != nil {
-		response.InternalError(c, err)
+	newVersion, err := u.svc.Update(c, userID, usr, version)
+	if err != nil {
+		statusError(c, err)
 		return
 	}
 
+	response.SetETag(c, newVersion)
 	response.New(
 		http.StatusOK,
 		"update user successfully",
@@ -171,8 +176,14 @@ func (u *userAPI) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := u.svc.Delete(c, userID); err != nil {
-		response.InternalError(c, err)
+	version, ok := response.IfMatch(c)
+	if !ok {
+		response.PreconditionFailed(c, repository.ErrVersionMismatch)
+		return
+	}
+
+	if err := u.svc.Delete(c, userID, version); err != nil {
+		statusError(c, err)
 		return
 	}
 
@@ -196,3 +207,71 @@ func (u *userAPI) List(c *gin.Context) {
 		}),
 	).OK(c)
 }
+
+type MedicalCertificateRequest struct {
+	ExpiresAt time.Time `json:"expires_at" validate:"required"`
+}
+
+func (u *userAPI) AddMedicalCertificate(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, domainerrors.ErrInvalidURLPattern)
+		return
+	}
+
+	var req MedicalCertificateRequest
+	if err := c.BindJSON(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	if validateErrors := validator.StructValidate(req); validateErrors != nil {
+		response.BadRequest(c, validateErrors)
+		return
+	}
+
+	certificate, err := u.svc.AddMedicalCertificate(c, userID, req.ExpiresAt)
+	if err != nil {
+		statusError(c, err)
+		return
+	}
+
+	response.New(
+		http.StatusOK,
+		"medical certificate added successfully",
+		response.WithValues(map[string]any{"certificate": certificate}),
+	).OK(c)
+}
+
+func (u *userAPI) MedicalCertificates(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, domainerrors.ErrInvalidURLPattern)
+		return
+	}
+
+	certificates, err := u.svc.MedicalCertificates(c, userID)
+	if err != nil {
+		statusError(c, err)
+		return
+	}
+
+	response.New(
+		http.StatusOK,
+		"get medical certificates successfully",
+		response.WithValues(map[string]any{"certificates": certificates}),
+	).OK(c)
+}
+
+// statusError writes err with the status code matching the user error.
+func statusError(c *gin.Context, err error) {
+	r := response.New(http.StatusInternalServerError, err.Error())
+	switch {
+	case errors.Is(err, repository.ErrUserNotFound):
+		r.Code = http.StatusNotFound
+	case errors.Is(err, repository.ErrVersionMismatch):
+		r.Code = http.StatusPreconditionFailed
+	}
+
+	r.Error(c)
+}
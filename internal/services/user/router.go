@@ -14,6 +14,8 @@ type Api interface {
 	Delete(c *gin.Context)
 	List(c *gin.Context)
 	GetAllPositions(c *gin.Context)
+	AddMedicalCertificate(c *gin.Context)
+	MedicalCertificates(c *gin.Context)
 }
 
 // TODO добавить auth middleware
@@ -23,6 +25,7 @@ func InitRoutes(
 	api Api,
 	roleManager rbac.RoleManager,
 	secretKey string,
+	idempotent gin.HandlerFunc,
 ) {
 	//rolegen:routes
 	var routes = []models.Route{
@@ -42,6 +45,7 @@ func InitRoutes(
 			Path:   "users/",
 			Handlers: []gin.HandlerFunc{
 				//rbacmiddleware.RoleAccess(roleManager, secretKey),
+				idempotent,
 				api.Create,
 			},
 		},
@@ -63,6 +67,21 @@ func InitRoutes(
 				api.Delete,
 			},
 		},
+		{
+			Method:   "GET",
+			Path:     "users/:id/medical-certificates",
+			Role:     "admin",
+			Handlers: []gin.HandlerFunc{api.MedicalCertificates},
+		},
+		{
+			Method: "POST",
+			Path:   "users/:id/medical-certificates",
+			Role:   "admin",
+			Handlers: []gin.HandlerFunc{
+				idempotent,
+				api.AddMedicalCertificate,
+			},
+		},
 		{
 			Method:   "PUT",
 			Path:     "users",
@@ -4,10 +4,12 @@ import (
 	"context"
 	"dussh/internal/cache/redis"
 	"dussh/internal/domain/models"
+	"dussh/internal/services/audit"
 	userv1 "dussh/internal/services/user/api/v1"
 	"dussh/internal/utils/bytesconv"
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
+	"time"
 )
 
 type Repository interface {
@@ -16,24 +18,29 @@ type Repository interface {
 	GetAllUserPositions(ctx context.Context) ([]*models.Position, error)
 	SaveUser(ctx context.Context, user *models.User) (int64, error)
 	CheckUserExists(ctx context.Context, email string) (bool, error)
-	UpdateUser(ctx context.Context, id int64, user *models.User) error
-	DeleteUser(ctx context.Context, id int64) error
+	UpdateUser(ctx context.Context, id int64, user *models.User, version int64) (int64, error)
+	DeleteUser(ctx context.Context, id int64, version int64) error
 	GetUsers(ctx context.Context) ([]*models.User, error)
+	SaveMedicalCertificate(ctx context.Context, c *models.MedicalCertificate) (int64, error)
+	GetMedicalCertificates(ctx context.Context, userID int64) ([]*models.MedicalCertificate, error)
 }
 
 func NewUserService(
 	repository Repository,
+	auditor audit.Recorder,
 	log *zap.Logger,
 ) userv1.Service {
 	return &userService{
-		repo: repository,
-		log:  log.Named("user.service"),
+		repo:  repository,
+		audit: auditor,
+		log:   log.Named("user.service"),
 	}
 }
 
 type userService struct {
 	repo  Repository
 	cache redis.Cache
+	audit audit.Recorder
 
 	log *zap.Logger
 }
@@ -66,15 +73,44 @@ func (u *userService) Create(ctx context.Context, user *models.User) (int64, err
 		return 0, err
 	}
 
+	user.ID = id
+	u.audit.Record(ctx, models.AuditCreate, models.EntityUser, id, nil, user.Info())
+
 	return id, nil
 }
 
-func (u *userService) Update(ctx context.Context, id int64, user *models.User) error {
-	return u.repo.UpdateUser(ctx, id, user)
+func (u *userService) Update(ctx context.Context, id int64, user *models.User, version int64) (int64, error) {
+	before, err := u.repo.GetUserByID(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	newVersion, err := u.repo.UpdateUser(ctx, id, user, version)
+	if err != nil {
+		return 0, err
+	}
+
+	after, err := u.repo.GetUserByID(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	u.audit.Record(ctx, models.AuditUpdate, models.EntityUser, id, before.Info(), after.Info())
+	return newVersion, nil
 }
 
-func (u *userService) Delete(ctx context.Context, id int64) error {
-	return u.repo.DeleteUser(ctx, id)
+func (u *userService) Delete(ctx context.Context, id int64, version int64) error {
+	before, err := u.repo.GetUserByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := u.repo.DeleteUser(ctx, id, version); err != nil {
+		return err
+	}
+
+	u.audit.Record(ctx, models.AuditDelete, models.EntityUser, id, before.Info(), nil)
+	return nil
 }
 
 func (u *userService) GetAllPositions(ctx context.Context) ([]*models.Position, error) {
@@ -84,3 +120,31 @@ func (u *userService) GetAllPositions(ctx context.Context) ([]*models.Position,
 func (u *userService) List(ctx context.Context) ([]*models.User, error) {
 	return u.repo.GetUsers(ctx)
 }
+
+// AddMedicalCertificate records a medical certificate of the user valid until
+// expiresAt, the user is notified shortly before it expires.
+func (u *userService) AddMedicalCertificate(
+	ctx context.Context,
+	userID int64,
+	expiresAt time.Time,
+) (*models.MedicalCertificate, error) {
+	certificate := &models.MedicalCertificate{UserID: userID, ExpiresAt: expiresAt}
+
+	id, err := u.repo.SaveMedicalCertificate(ctx, certificate)
+	if err != nil {
+		return nil, err
+	}
+
+	certificate.ID = id
+	u.audit.Record(ctx, models.AuditCreate, models.EntityMedicalCertificate, id, nil, certificate)
+
+	return certificate, nil
+}
+
+func (u *userService) MedicalCertificates(ctx context.Context, userID int64) ([]*models.MedicalCertificate, error) {
+	if _, err := u.repo.GetUserByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return u.repo.GetMedicalCertificates(ctx, userID)
+}
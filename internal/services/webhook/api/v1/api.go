@@ -0,0 +1,272 @@
+package v1
+
+import (
+	"context"
+	domainerrors "dussh/internal/domain/errors"
+	"dussh/internal/domain/models"
+	"dussh/internal/domain/response"
+	"dussh/internal/repository"
+	"dussh/internal/services/webhook"
+	"dussh/pkg/validator"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+)
+
+const defaultDeliveriesLimit = 50
+
+type Service interface {
+	List(ctx context.Context) ([]*models.WebhookSubscription, error)
+	// Create stores the subscription, a secret is generated if none is given.
+	Create(ctx context.Context, sub *models.WebhookSubscription) (*models.WebhookSubscription, error)
+	Get(ctx context.Context, id int64) (*models.WebhookSubscription, error)
+	Update(ctx context.Context, id int64, sub *models.WebhookSubscription) (*models.WebhookSubscription, error)
+	Delete(ctx context.Context, id int64) error
+	Deliveries(
+		ctx context.Context,
+		subscriptionID int64,
+		status models.WebhookDeliveryStatus,
+		limit int64,
+	) ([]*models.WebhookDelivery, error)
+	Delivery(ctx context.Context, subscriptionID, id int64) (*models.WebhookDelivery, error)
+	Redeliver(ctx context.Context, subscriptionID, id int64) error
+}
+
+func NewWebhookAPI(service Service, log *zap.Logger) webhook.Api {
+	return &webhookAPI{
+		svc: service,
+		log: log.Named("webhook.api"),
+	}
+}
+
+type webhookAPI struct {
+	svc Service
+
+	log *zap.Logger
+}
+
+type SubscriptionRequest struct {
+	URL        string   `json:"url" validate:"required,url"`
+	EventTypes []string `json:"event_types" validate:"required,min=1,dive,required"`
+	Secret     string   `json:"secret" validate:"omitempty,min=16"`
+	Active     *bool    `json:"active"`
+}
+
+func (r SubscriptionRequest) subscription() *models.WebhookSubscription {
+	active := r.Active == nil || *r.Active
+	return &models.WebhookSubscription{
+		URL:        r.URL,
+		EventTypes: r.EventTypes,
+		Secret:     r.Secret,
+		Active:     active,
+	}
+}
+
+type DeliveriesRequest struct {
+	Status string `form:"status" validate:"omitempty,oneof=pending succeeded failed"`
+	Limit  int64  `form:"limit" validate:"omitempty,min=1,max=500"`
+}
+
+func (a *webhookAPI) List(c *gin.Context) {
+	subs, err := a.svc.List(c)
+	if err != nil {
+		response.InternalError(c, err)
+		return
+	}
+
+	response.New(
+		http.StatusOK,
+		"webhook subscriptions received successfully",
+		response.WithValues(map[string]any{"subscriptions": subs}),
+	).OK(c)
+}
+
+func (a *webhookAPI) Create(c *gin.Context) {
+	var req SubscriptionRequest
+	if err := c.BindJSON(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	if validateErrors := validator.StructValidate(req); validateErrors != nil {
+		response.BadRequest(c, validateErrors)
+		return
+	}
+
+	sub, err := a.svc.Create(c, req.subscription())
+	if err != nil {
+		statusError(c, err)
+		return
+	}
+
+	// the secret is not returned after the subscription is created
+	response.New(
+		http.StatusOK,
+		"webhook subscription created successfully",
+		response.WithValues(map[string]any{"subscription": sub, "secret": sub.Secret}),
+	).OK(c)
+}
+
+func (a *webhookAPI) Get(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, domainerrors.ErrInvalidURLPattern)
+		return
+	}
+
+	sub, err := a.svc.Get(c, id)
+	if err != nil {
+		statusError(c, err)
+		return
+	}
+
+	response.New(
+		http.StatusOK,
+		"webhook subscription received successfully",
+		response.WithValues(map[string]any{"subscription": sub}),
+	).OK(c)
+}
+
+func (a *webhookAPI) Update(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, domainerrors.ErrInvalidURLPattern)
+		return
+	}
+
+	var req SubscriptionRequest
+	if err := c.BindJSON(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	if validateErrors := validator.StructValidate(req); validateErrors != nil {
+		response.BadRequest(c, validateErrors)
+		return
+	}
+
+	sub, err := a.svc.Update(c, id, req.subscription())
+	if err != nil {
+		statusError(c, err)
+		return
+	}
+
+	response.New(
+		http.StatusOK,
+		"webhook subscription updated successfully",
+		response.WithValues(map[string]any{"subscription": sub}),
+	).OK(c)
+}
+
+func (a *webhookAPI) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, domainerrors.ErrInvalidURLPattern)
+		return
+	}
+
+	if err := a.svc.Delete(c, id); err != nil {
+		statusError(c, err)
+		return
+	}
+
+	response.New(http.StatusOK, "webhook subscription deleted successfully").OK(c)
+}
+
+func (a *webhookAPI) Deliveries(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, domainerrors.ErrInvalidURLPattern)
+		return
+	}
+
+	var req DeliveriesRequest
+	if err := c.BindQuery(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	if validateErrors := validator.StructValidate(req); validateErrors != nil {
+		response.BadRequest(c, validateErrors)
+		return
+	}
+
+	if req.Limit == 0 {
+		req.Limit = defaultDeliveriesLimit
+	}
+
+	deliveries, err := a.svc.Deliveries(c, id, models.WebhookDeliveryStatus(req.Status), req.Limit)
+	if err != nil {
+		statusError(c, err)
+		return
+	}
+
+	response.New(
+		http.StatusOK,
+		"webhook deliveries received successfully",
+		response.WithValues(map[string]any{"deliveries": deliveries}),
+	).OK(c)
+}
+
+func (a *webhookAPI) Delivery(c *gin.Context) {
+	id, deliveryID, ok := deliveryParams(c)
+	if !ok {
+		return
+	}
+
+	delivery, err := a.svc.Delivery(c, id, deliveryID)
+	if err != nil {
+		statusError(c, err)
+		return
+	}
+
+	response.New(
+		http.StatusOK,
+		"webhook delivery received successfully",
+		response.WithValues(map[string]any{"delivery": delivery}),
+	).OK(c)
+}
+
+func (a *webhookAPI) Redeliver(c *gin.Context) {
+	id, deliveryID, ok := deliveryParams(c)
+	if !ok {
+		return
+	}
+
+	if err := a.svc.Redeliver(c, id, deliveryID); err != nil {
+		statusError(c, err)
+		return
+	}
+
+	response.New(http.StatusOK, "webhook delivery scheduled successfully").OK(c)
+}
+
+func deliveryParams(c *gin.Context) (int64, int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, domainerrors.ErrInvalidURLPattern)
+		return 0, 0, false
+	}
+
+	deliveryID, err := strconv.ParseInt(c.Param("deliveryID"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, domainerrors.ErrInvalidURLPattern)
+		return 0, 0, false
+	}
+
+	return id, deliveryID, true
+}
+
+func statusError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrWebhookNotFound),
+		errors.Is(err, repository.ErrWebhookDeliveryNotFound):
+		response.New(http.StatusNotFound, err.Error()).Error(c)
+	case errors.Is(err, domainerrors.ErrUnknownWebhookEvent):
+		response.New(http.StatusBadRequest, err.Error()).Error(c)
+	default:
+		response.InternalError(c, err)
+	}
+}
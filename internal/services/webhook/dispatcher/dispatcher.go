@@ -0,0 +1,247 @@
+package dispatcher
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"dussh/internal/broker"
+	"dussh/internal/config"
+	"dussh/internal/domain/models"
+	"encoding/hex"
+	"errors"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var ErrDispatcherClosed = errors.New("webhook dispatcher closed")
+
+// Headers of a webhook request.
+const (
+	HeaderEvent     = "X-Dussh-Event"
+	HeaderDelivery  = "X-Dussh-Delivery"
+	HeaderTimestamp = "X-Dussh-Timestamp"
+	HeaderSignature = "X-Dussh-Signature"
+)
+
+// responseBodyLimit bounds the response body kept in the delivery log.
+const responseBodyLimit = 1024
+
+type Repository interface {
+	ClaimWebhookDeliveries(ctx context.Context, limit int64, lease time.Duration) ([]*models.WebhookDelivery, error)
+	GetWebhookSubscription(ctx context.Context, id int64) (*models.WebhookSubscription, error)
+	RecordWebhookAttempt(
+		ctx context.Context,
+		attempt *models.WebhookAttempt,
+		status models.WebhookDeliveryStatus,
+		retryIn time.Duration,
+	) error
+}
+
+// Dispatcher POSTs pending deliveries to the subscribed URLs. Failed
+// deliveries are retried with exponential backoff until MaxAttempts.
+type Dispatcher struct {
+	repo   Repository
+	client *http.Client
+	cfg    config.Webhook
+	// batch is the number of deliveries claimed at once
+	batch int64
+
+	log *zap.Logger
+}
+
+func New(repo Repository, cfg config.Webhook, log *zap.Logger) *Dispatcher {
+	cfg.Workers = max(cfg.Workers, 1)
+
+	return &Dispatcher{
+		repo:   repo,
+		client: &http.Client{Timeout: cfg.Timeout},
+		cfg:    cfg,
+		batch:  batchSize(cfg),
+		log:    log.Named("webhook.dispatcher"),
+	}
+}
+
+// batchSize bounds the batch by the deliveries the workers can send before
+// the lease expires, otherwise the rest of the batch is claimed again by
+// another instance and sent twice.
+func batchSize(cfg config.Webhook) int64 {
+	perWorker := int64(1)
+	if cfg.Timeout > 0 {
+		perWorker = max(int64(cfg.Lease/cfg.Timeout), 1)
+	}
+
+	return min(cfg.BatchSize, int64(cfg.Workers)*perWorker)
+}
+
+// Sign returns the signature of the body sent at the timestamp, the receiver
+// computes the same HMAC-SHA256 of "<timestamp>.<body>" with the secret.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Run polls for pending deliveries until the context is canceled.
+func (d *Dispatcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			n, err := d.dispatch(ctx)
+			if err != nil && ctx.Err() == nil {
+				d.log.Error("failed to dispatch webhook deliveries", zap.Error(err))
+			}
+			if err != nil || int64(n) < d.batch {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ErrDispatcherClosed
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context) (int, error) {
+	deliveries, err := d.repo.ClaimWebhookDeliveries(ctx, d.batch, d.cfg.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	subs := make(map[int64]*models.WebhookSubscription)
+	for _, delivery := range deliveries {
+		if _, ok := subs[delivery.SubscriptionID]; ok {
+			continue
+		}
+
+		sub, err := d.repo.GetWebhookSubscription(ctx, delivery.SubscriptionID)
+		if err != nil {
+			return 0, err
+		}
+		subs[delivery.SubscriptionID] = sub
+	}
+
+	var (
+		mu   sync.Mutex
+		errs []error
+	)
+	pool := broker.NewWorkerPool(d.cfg.Workers, func(delivery *models.WebhookDelivery) {
+		if err := d.deliver(ctx, subs[delivery.SubscriptionID], delivery); err != nil {
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+		}
+	})
+	for _, delivery := range deliveries {
+		pool.Dispatch("", delivery)
+	}
+	pool.Drain()
+
+	if err := errors.Join(errs...); err != nil {
+		return 0, err
+	}
+
+	return len(deliveries), nil
+}
+
+// deliver sends the delivery and records the attempt.
+func (d *Dispatcher) deliver(ctx context.Context, sub *models.WebhookSubscription, delivery *models.WebhookDelivery) error {
+	attempt := &models.WebhookAttempt{DeliveryID: delivery.ID}
+
+	if sub.Active {
+		d.send(ctx, sub, delivery, attempt)
+	} else {
+		reason := "subscription is inactive"
+		attempt.Error = &reason
+	}
+
+	status, retryIn := models.WebhookSucceeded, time.Duration(0)
+	switch {
+	case attempt.Error == nil:
+	case !sub.Active || delivery.Attempts >= d.cfg.MaxAttempts:
+		status = models.WebhookFailed
+	default:
+		status, retryIn = models.WebhookPending, d.backoff(delivery.Attempts)
+	}
+
+	if status != models.WebhookSucceeded {
+		d.log.Warn("failed to deliver webhook",
+			zap.Int64("delivery_id", delivery.ID),
+			zap.Int64("subscription_id", sub.ID),
+			zap.String("event_type", delivery.EventType),
+			zap.Int("attempts", delivery.Attempts),
+			zap.String("status", string(status)),
+			zap.Duration("retry_in", retryIn),
+			zap.String("error", *attempt.Error),
+		)
+	}
+
+	// the attempt is recorded even if the dispatcher is stopping
+	return d.repo.RecordWebhookAttempt(context.WithoutCancel(ctx), attempt, status, retryIn)
+}
+
+// send posts the payload and fills the attempt with the outcome, a response
+// other than 2xx is an error.
+func (d *Dispatcher) send(
+	ctx context.Context,
+	sub *models.WebhookSubscription,
+	delivery *models.WebhookDelivery,
+	attempt *models.WebhookAttempt,
+) {
+	fail := func(err error) {
+		reason := err.Error()
+		attempt.Error = &reason
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "dussh-webhook/1")
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, timestamp, delivery.Payload))
+
+	start := time.Now()
+	resp, err := d.client.Do(req)
+	attempt.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		fail(err)
+		return
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, responseBodyLimit))
+	code, text := resp.StatusCode, string(body)
+	attempt.ResponseCode, attempt.ResponseBody = &code, &text
+
+	if code < 200 || code >= 300 {
+		fail(errors.New("unexpected response status " + resp.Status))
+	}
+}
+
+// backoff returns the delay before the next delivery attempt.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.cfg.RetryInterval
+	for i := 1; i < attempts && delay < d.cfg.MaxRetryInterval; i++ {
+		delay *= 2
+	}
+
+	return min(delay, d.cfg.MaxRetryInterval)
+}
@@ -0,0 +1,156 @@
+package dispatcher
+
+import (
+	"context"
+	"dussh/internal/config"
+	"dussh/internal/domain/models"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordedAttempt struct {
+	attempt *models.WebhookAttempt
+	status  models.WebhookDeliveryStatus
+	retryIn time.Duration
+}
+
+type fakeRepo struct {
+	sub        *models.WebhookSubscription
+	deliveries []*models.WebhookDelivery
+	limit      int64
+
+	mu       sync.Mutex
+	attempts []recordedAttempt
+}
+
+func (r *fakeRepo) ClaimWebhookDeliveries(_ context.Context, limit int64, _ time.Duration) ([]*models.WebhookDelivery, error) {
+	r.limit = limit
+	claimed := r.deliveries[:min(int64(len(r.deliveries)), limit)]
+	r.deliveries = r.deliveries[len(claimed):]
+	return claimed, nil
+}
+
+func (r *fakeRepo) GetWebhookSubscription(context.Context, int64) (*models.WebhookSubscription, error) {
+	return r.sub, nil
+}
+
+func (r *fakeRepo) RecordWebhookAttempt(
+	_ context.Context,
+	attempt *models.WebhookAttempt,
+	status models.WebhookDeliveryStatus,
+	retryIn time.Duration,
+) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.attempts = append(r.attempts, recordedAttempt{attempt, status, retryIn})
+	return nil
+}
+
+func TestDispatch(t *testing.T) {
+	const secret = "0123456789abcdef"
+	payload := []byte(`{"type":"user.updated","payload":{"user_id":1}}`)
+
+	fail := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		want := Sign(secret, r.Header.Get(HeaderTimestamp), body)
+		if got := r.Header.Get(HeaderSignature); got != want {
+			t.Errorf("signature = %q, want %q", got, want)
+		}
+		if r.Header.Get(HeaderEvent) != "user.updated" || r.Header.Get(HeaderDelivery) != "7" {
+			t.Errorf("unexpected headers: %v", r.Header)
+		}
+
+		if fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("busy"))
+		}
+	}))
+	defer server.Close()
+
+	repo := &fakeRepo{sub: &models.WebhookSubscription{ID: 1, URL: server.URL, Secret: secret, Active: true}}
+	d := New(repo, config.Webhook{
+		BatchSize:        10,
+		Lease:            time.Minute,
+		Timeout:          time.Second,
+		MaxAttempts:      3,
+		RetryInterval:    time.Second,
+		MaxRetryInterval: 3 * time.Second,
+	}, zap.NewNop())
+
+	dispatch := func(attempts int) recordedAttempt {
+		repo.deliveries = []*models.WebhookDelivery{{
+			ID: 7, SubscriptionID: 1, EventType: "user.updated", Payload: payload, Attempts: attempts,
+		}}
+		if _, err := d.dispatch(context.Background()); err != nil {
+			t.Fatalf("failed to dispatch: %v", err)
+		}
+		return repo.attempts[len(repo.attempts)-1]
+	}
+
+	got := dispatch(2)
+	if got.status != models.WebhookPending || got.retryIn != 2*time.Second {
+		t.Errorf("status = %s, retry in %s, want pending retry in 2s", got.status, got.retryIn)
+	}
+	if got.attempt.ResponseCode == nil || *got.attempt.ResponseCode != http.StatusServiceUnavailable ||
+		*got.attempt.ResponseBody != "busy" {
+		t.Errorf("unexpected attempt: %+v", got.attempt)
+	}
+
+	if got := dispatch(3); got.status != models.WebhookFailed {
+		t.Errorf("status = %s after max attempts, want failed", got.status)
+	}
+
+	fail = false
+	if got := dispatch(1); got.status != models.WebhookSucceeded || got.attempt.Error != nil {
+		t.Errorf("status = %s, error %v, want succeeded", got.status, got.attempt.Error)
+	}
+}
+
+func TestDispatchSendsBatchWithinLease(t *testing.T) {
+	const workers = 3
+
+	// every request waits until the whole batch is in flight
+	var arrived sync.WaitGroup
+	arrived.Add(workers)
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		arrived.Done()
+		arrived.Wait()
+	}))
+	defer server.Close()
+
+	repo := &fakeRepo{sub: &models.WebhookSubscription{ID: 1, URL: server.URL, Active: true}}
+	for i := 0; i < 2*workers; i++ {
+		repo.deliveries = append(repo.deliveries, &models.WebhookDelivery{
+			ID: int64(i + 1), SubscriptionID: 1, EventType: "user.updated", Payload: []byte(`{}`),
+		})
+	}
+
+	d := New(repo, config.Webhook{
+		BatchSize:   50,
+		Workers:     workers,
+		Lease:       1500 * time.Millisecond,
+		Timeout:     time.Second,
+		MaxAttempts: 3,
+	}, zap.NewNop())
+
+	n, err := d.dispatch(context.Background())
+	if err != nil {
+		t.Fatalf("failed to dispatch: %v", err)
+	}
+	if n != workers || repo.limit != workers {
+		t.Fatalf("claimed %d with limit %d, want %d: a worker has time for one request per lease", n, repo.limit, workers)
+	}
+
+	for _, got := range repo.attempts {
+		if got.status != models.WebhookSucceeded {
+			t.Errorf("delivery %d status = %s, want succeeded", got.attempt.DeliveryID, got.status)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+//go:generate go run /home/dmitry/dussh/pkg/rbac/rolegen
+package webhook
+
+import (
+	"dussh/internal/domain/models"
+	rbacmiddleware "dussh/internal/role/middleware"
+	"dussh/pkg/rbac"
+	"github.com/gin-gonic/gin"
+)
+
+type Api interface {
+	List(c *gin.Context)
+	Create(c *gin.Context)
+	Get(c *gin.Context)
+	Update(c *gin.Context)
+	Delete(c *gin.Context)
+	Deliveries(c *gin.Context)
+	Delivery(c *gin.Context)
+	Redeliver(c *gin.Context)
+}
+
+func InitRoutes(
+	routeGroup *gin.RouterGroup,
+	api Api,
+	roleManager rbac.RoleManager,
+	secretKey string,
+) {
+	//rolegen:routes
+	var routes = []models.Route{
+		{
+			Method: "GET",
+			Path:   "webhooks",
+			Role:   "admin",
+			Handlers: []gin.HandlerFunc{
+				rbacmiddleware.RoleAccess(roleManager, secretKey),
+				api.List,
+			},
+		},
+		{
+			Method: "POST",
+			Path:   "webhooks",
+			Role:   "admin",
+			Handlers: []gin.HandlerFunc{
+				rbacmiddleware.RoleAccess(roleManager, secretKey),
+				api.Create,
+			},
+		},
+		{
+			Method: "GET",
+			Path:   "webhooks/:id",
+			Role:   "admin",
+			Handlers: []gin.HandlerFunc{
+				rbacmiddleware.RoleAccess(roleManager, secretKey),
+				api.Get,
+			},
+		},
+		{
+			Method: "PUT",
+			Path:   "webhooks/:id",
+			Role:   "admin",
+			Handlers: []gin.HandlerFunc{
+				rbacmiddleware.RoleAccess(roleManager, secretKey),
+				api.Update,
+			},
+		},
+		{
+			Method: "DELETE",
+			Path:   "webhooks/:id",
+			Role:   "admin",
+			Handlers: []gin.HandlerFunc{
+				rbacmiddleware.RoleAccess(roleManager, secretKey),
+				api.Delete,
+			},
+		},
+		{
+			Method: "GET",
+			Path:   "webhooks/:id/deliveries",
+			Role:   "admin",
+			Handlers: []gin.HandlerFunc{
+				rbacmiddleware.RoleAccess(roleManager, secretKey),
+				api.Deliveries,
+			},
+		},
+		{
+			Method: "GET",
+			Path:   "webhooks/:id/deliveries/:deliveryID",
+			Role:   "admin",
+			Handlers: []gin.HandlerFunc{
+				rbacmiddleware.RoleAccess(roleManager, secretKey),
+				api.Delivery,
+			},
+		},
+		{
+			Method: "POST",
+			Path:   "webhooks/:id/deliveries/:deliveryID/redeliver",
+			Role:   "admin",
+			Handlers: []gin.HandlerFunc{
+				rbacmiddleware.RoleAccess(roleManager, secretKey),
+				api.Redeliver,
+			},
+		},
+	}
+
+	for _, r := range routes {
+		routeGroup.Handle(r.Method, r.Path, r.Handlers...)
+	}
+}
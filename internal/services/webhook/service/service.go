@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	domainerrors "dussh/internal/domain/errors"
+	"dussh/internal/domain/models"
+	webhookv1 "dussh/internal/services/webhook/api/v1"
+	"encoding/hex"
+	"fmt"
+	"go.uber.org/zap"
+	"slices"
+)
+
+type Repository interface {
+	CreateWebhookSubscription(ctx context.Context, sub *models.WebhookSubscription) (int64, error)
+	GetWebhookSubscriptions(ctx context.Context) ([]*models.WebhookSubscription, error)
+	GetWebhookSubscription(ctx context.Context, id int64) (*models.WebhookSubscription, error)
+	UpdateWebhookSubscription(ctx context.Context, id int64, sub *models.WebhookSubscription) error
+	DeleteWebhookSubscription(ctx context.Context, id int64) error
+	GetWebhookDeliveries(
+		ctx context.Context,
+		subscriptionID int64,
+		status models.WebhookDeliveryStatus,
+		limit int64,
+	) ([]*models.WebhookDelivery, error)
+	GetWebhookDelivery(ctx context.Context, subscriptionID, id int64) (*models.WebhookDelivery, error)
+	RedeliverWebhookDelivery(ctx context.Context, subscriptionID, id int64) error
+}
+
+func NewWebhookService(repo Repository, log *zap.Logger) webhookv1.Service {
+	return &webhookService{
+		repo: repo,
+		log:  log.Named("webhook.service"),
+	}
+}
+
+type webhookService struct {
+	repo Repository
+
+	log *zap.Logger
+}
+
+func (s *webhookService) List(ctx context.Context) ([]*models.WebhookSubscription, error) {
+	return s.repo.GetWebhookSubscriptions(ctx)
+}
+
+func (s *webhookService) Create(
+	ctx context.Context,
+	sub *models.WebhookSubscription,
+) (*models.WebhookSubscription, error) {
+	if err := validateEventTypes(sub.EventTypes); err != nil {
+		return nil, err
+	}
+
+	if sub.Secret == "" {
+		secret, err := newSecret()
+		if err != nil {
+			return nil, err
+		}
+		sub.Secret = secret
+	}
+
+	id, err := s.repo.CreateWebhookSubscription(ctx, sub)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.repo.GetWebhookSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("webhook subscription created", zap.Int64("id", id), zap.String("url", sub.URL))
+	return created, nil
+}
+
+func (s *webhookService) Get(ctx context.Context, id int64) (*models.WebhookSubscription, error) {
+	return s.repo.GetWebhookSubscription(ctx, id)
+}
+
+func (s *webhookService) Update(
+	ctx context.Context,
+	id int64,
+	sub *models.WebhookSubscription,
+) (*models.WebhookSubscription, error) {
+	if err := validateEventTypes(sub.EventTypes); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateWebhookSubscription(ctx, id, sub); err != nil {
+		return nil, err
+	}
+
+	s.log.Info("webhook subscription updated", zap.Int64("id", id))
+	return s.repo.GetWebhookSubscription(ctx, id)
+}
+
+func (s *webhookService) Delete(ctx context.Context, id int64) error {
+	if err := s.repo.DeleteWebhookSubscription(ctx, id); err != nil {
+		return err
+	}
+
+	s.log.Info("webhook subscription deleted", zap.Int64("id", id))
+	return nil
+}
+
+func (s *webhookService) Deliveries(
+	ctx context.Context,
+	subscriptionID int64,
+	status models.WebhookDeliveryStatus,
+	limit int64,
+) ([]*models.WebhookDelivery, error) {
+	if _, err := s.repo.GetWebhookSubscription(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetWebhookDeliveries(ctx, subscriptionID, status, limit)
+}
+
+func (s *webhookService) Delivery(ctx context.Context, subscriptionID, id int64) (*models.WebhookDelivery, error) {
+	return s.repo.GetWebhookDelivery(ctx, subscriptionID, id)
+}
+
+func (s *webhookService) Redeliver(ctx context.Context, subscriptionID, id int64) error {
+	if err := s.repo.RedeliverWebhookDelivery(ctx, subscriptionID, id); err != nil {
+		return err
+	}
+
+	s.log.Info("webhook delivery scheduled for redelivery",
+		zap.Int64("subscription_id", subscriptionID),
+		zap.Int64("delivery_id", id),
+	)
+	return nil
+}
+
+func validateEventTypes(eventTypes []string) error {
+	for _, t := range eventTypes {
+		if !slices.Contains(models.WebhookEventTypes, t) {
+			return fmt.Errorf("%w %q", domainerrors.ErrUnknownWebhookEvent, t)
+		}
+	}
+	return nil
+}
+
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
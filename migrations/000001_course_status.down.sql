@@ -0,0 +1,3 @@
+ALTER TABLE courses
+    DROP COLUMN archived_at,
+    DROP COLUMN status;
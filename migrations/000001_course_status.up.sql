@@ -0,0 +1,7 @@
+ALTER TABLE courses
+    ADD COLUMN status      VARCHAR(32) NOT NULL DEFAULT 'draft'
+        CHECK (status IN ('draft', 'published', 'enrollment_closed', 'archived')),
+    ADD COLUMN archived_at TIMESTAMP;
+
+-- courses created before lifecycle states existed were already open for enrollment
+UPDATE courses SET status = 'published';
@@ -0,0 +1,18 @@
+CREATE TABLE audit_log
+(
+    id          BIGSERIAL PRIMARY KEY,
+    actor_id    BIGINT,
+    actor_email VARCHAR(255) NOT NULL DEFAULT '',
+    action      VARCHAR(16)  NOT NULL,
+    entity_type VARCHAR(64)  NOT NULL,
+    entity_id   BIGINT       NOT NULL,
+    before      JSONB,
+    after       JSONB,
+    diff        JSONB,
+    request_id  VARCHAR(64)  NOT NULL DEFAULT '',
+    created_at  TIMESTAMP    NOT NULL DEFAULT LOCALTIMESTAMP
+);
+
+CREATE INDEX audit_log_actor_id_idx ON audit_log (actor_id);
+CREATE INDEX audit_log_entity_idx ON audit_log (entity_type, entity_id);
+CREATE INDEX audit_log_created_at_idx ON audit_log (created_at);
@@ -0,0 +1,8 @@
+ALTER TABLE personal_info
+    DROP COLUMN version;
+
+ALTER TABLE events
+    DROP COLUMN version;
+
+ALTER TABLE courses
+    DROP COLUMN version;
@@ -0,0 +1,8 @@
+ALTER TABLE courses
+    ADD COLUMN version INT NOT NULL DEFAULT 1;
+
+ALTER TABLE events
+    ADD COLUMN version INT NOT NULL DEFAULT 1;
+
+ALTER TABLE personal_info
+    ADD COLUMN version INT NOT NULL DEFAULT 1;
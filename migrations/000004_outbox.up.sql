@@ -0,0 +1,14 @@
+CREATE TABLE outbox
+(
+    id              BIGSERIAL PRIMARY KEY,
+    event_type      VARCHAR(128) NOT NULL,
+    routing_key     VARCHAR(255) NOT NULL,
+    payload         JSONB        NOT NULL,
+    attempts        INTEGER      NOT NULL DEFAULT 0,
+    last_error      TEXT,
+    created_at      TIMESTAMP    NOT NULL DEFAULT LOCALTIMESTAMP,
+    next_attempt_at TIMESTAMP    NOT NULL DEFAULT LOCALTIMESTAMP,
+    sent_at         TIMESTAMP
+);
+
+CREATE INDEX outbox_pending_idx ON outbox (next_attempt_at) WHERE sent_at IS NULL;
@@ -0,0 +1,23 @@
+CREATE TABLE jobs
+(
+    id               BIGSERIAL PRIMARY KEY,
+    queue            VARCHAR(255) NOT NULL,
+    message_id       VARCHAR(255) NOT NULL,
+    routing_key      VARCHAR(255) NOT NULL,
+    type             VARCHAR(128) NOT NULL DEFAULT '',
+    correlation_id   VARCHAR(255) NOT NULL DEFAULT '',
+    producer         VARCHAR(128) NOT NULL DEFAULT '',
+    content_type     VARCHAR(128) NOT NULL DEFAULT '',
+    headers          JSONB        NOT NULL DEFAULT '{}',
+    body             BYTEA        NOT NULL,
+    published_at     TIMESTAMP    NOT NULL DEFAULT LOCALTIMESTAMP,
+    attempts         INTEGER      NOT NULL DEFAULT 0,
+    retry_count      INTEGER      NOT NULL DEFAULT 0,
+    last_error       TEXT,
+    created_at       TIMESTAMP    NOT NULL DEFAULT LOCALTIMESTAMP,
+    run_at           TIMESTAMP    NOT NULL DEFAULT LOCALTIMESTAMP,
+    dead_lettered_at TIMESTAMP
+);
+
+CREATE INDEX jobs_ready_idx ON jobs (queue, run_at) WHERE dead_lettered_at IS NULL;
+CREATE INDEX jobs_dead_lettered_idx ON jobs (message_id) WHERE dead_lettered_at IS NOT NULL;
@@ -0,0 +1,9 @@
+-- a parent may link the accounts of several children to one chat
+CREATE TABLE telegram_links
+(
+    personal_info_id INTEGER   PRIMARY KEY REFERENCES personal_info (personal_info_id) ON DELETE CASCADE,
+    chat_id          BIGINT    NOT NULL,
+    linked_at        TIMESTAMP NOT NULL DEFAULT LOCALTIMESTAMP
+);
+
+CREATE INDEX telegram_links_chat_id_idx ON telegram_links (chat_id);
@@ -0,0 +1,44 @@
+CREATE TABLE webhook_subscriptions
+(
+    id          SERIAL PRIMARY KEY,
+    url         TEXT      NOT NULL,
+    event_types TEXT[]    NOT NULL,
+    secret      TEXT      NOT NULL,
+    active      BOOLEAN   NOT NULL DEFAULT TRUE,
+    created_at  TIMESTAMP NOT NULL DEFAULT LOCALTIMESTAMP,
+    updated_at  TIMESTAMP NOT NULL DEFAULT LOCALTIMESTAMP
+);
+
+-- a delivery is created for every subscription in the transaction that
+-- raised the event, so it is delivered if and only if the transaction commits
+CREATE TABLE webhook_deliveries
+(
+    id                 BIGSERIAL PRIMARY KEY,
+    subscription_id    INTEGER      NOT NULL REFERENCES webhook_subscriptions (id) ON DELETE CASCADE,
+    event_id           VARCHAR(255) NOT NULL,
+    event_type         VARCHAR(128) NOT NULL,
+    payload            JSONB        NOT NULL,
+    status             VARCHAR(16)  NOT NULL DEFAULT 'pending',
+    attempts           INTEGER      NOT NULL DEFAULT 0,
+    last_response_code INTEGER,
+    last_error         TEXT,
+    created_at         TIMESTAMP    NOT NULL DEFAULT LOCALTIMESTAMP,
+    next_attempt_at    TIMESTAMP    NOT NULL DEFAULT LOCALTIMESTAMP,
+    delivered_at       TIMESTAMP
+);
+
+CREATE INDEX webhook_deliveries_pending_idx ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX webhook_deliveries_subscription_idx ON webhook_deliveries (subscription_id, id);
+
+CREATE TABLE webhook_delivery_attempts
+(
+    id            BIGSERIAL PRIMARY KEY,
+    delivery_id   BIGINT    NOT NULL REFERENCES webhook_deliveries (id) ON DELETE CASCADE,
+    response_code INTEGER,
+    response_body TEXT,
+    error         TEXT,
+    duration_ms   INTEGER   NOT NULL,
+    attempted_at  TIMESTAMP NOT NULL DEFAULT LOCALTIMESTAMP
+);
+
+CREATE INDEX webhook_delivery_attempts_delivery_idx ON webhook_delivery_attempts (delivery_id);
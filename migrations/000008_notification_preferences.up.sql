@@ -0,0 +1,7 @@
+CREATE TABLE notification_preferences
+(
+    personal_info_id INTEGER   PRIMARY KEY REFERENCES personal_info (personal_info_id) ON DELETE CASCADE,
+    channels         JSONB     NOT NULL DEFAULT '{}',
+    quiet_hours      JSONB,
+    updated_at       TIMESTAMP NOT NULL DEFAULT LOCALTIMESTAMP
+);
@@ -0,0 +1,3 @@
+ALTER TABLE notification_preferences DROP COLUMN locale;
+
+DROP TABLE notification_templates;
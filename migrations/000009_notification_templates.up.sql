@@ -0,0 +1,16 @@
+-- every save adds a version, the latest version of the event type and locale is used
+CREATE TABLE notification_templates
+(
+    id         SERIAL PRIMARY KEY,
+    event_type VARCHAR(128) NOT NULL,
+    locale     VARCHAR(8)   NOT NULL,
+    version    INTEGER      NOT NULL,
+    layout     VARCHAR(64)  NOT NULL DEFAULT 'email',
+    subject    TEXT         NOT NULL,
+    text_body  TEXT         NOT NULL,
+    html_body  TEXT         NOT NULL,
+    created_at TIMESTAMP    NOT NULL DEFAULT LOCALTIMESTAMP,
+    UNIQUE (event_type, locale, version)
+);
+
+ALTER TABLE notification_preferences ADD COLUMN locale VARCHAR(8) NOT NULL DEFAULT 'ru';
@@ -0,0 +1,14 @@
+-- a reminder is enqueued once per user, session and offset before it
+CREATE TABLE session_reminders
+(
+    event_id         INTEGER   NOT NULL,
+    course_id        INTEGER   NOT NULL,
+    personal_info_id INTEGER   NOT NULL REFERENCES personal_info (personal_info_id) ON DELETE CASCADE,
+    starts_at        TIMESTAMP NOT NULL,
+    offset_minutes   INTEGER   NOT NULL,
+    created_at       TIMESTAMP NOT NULL DEFAULT LOCALTIMESTAMP,
+    PRIMARY KEY (event_id, personal_info_id, starts_at, offset_minutes),
+    FOREIGN KEY (event_id, course_id) REFERENCES events (event_id, course_id) ON DELETE CASCADE
+);
+
+CREATE INDEX session_reminders_starts_at_idx ON session_reminders (starts_at);
@@ -0,0 +1,24 @@
+-- every notification sent by the notification service, with the outcome of its last attempt
+CREATE TABLE notification_deliveries
+(
+    id                BIGSERIAL PRIMARY KEY,
+    personal_info_id  INTEGER REFERENCES personal_info (personal_info_id) ON DELETE SET NULL,
+    event_type        VARCHAR(128) NOT NULL,
+    locale            VARCHAR(8),
+    template_version  INTEGER      NOT NULL DEFAULT 0,
+    channel           VARCHAR(32)  NOT NULL,
+    recipients        TEXT[]       NOT NULL,
+    content_type      VARCHAR(32)  NOT NULL,
+    subject           TEXT         NOT NULL DEFAULT '',
+    body              TEXT         NOT NULL,
+    status            VARCHAR(16)  NOT NULL DEFAULT 'queued',
+    attempts          INTEGER      NOT NULL DEFAULT 0,
+    provider_response TEXT,
+    created_at        TIMESTAMP    NOT NULL DEFAULT LOCALTIMESTAMP,
+    updated_at        TIMESTAMP    NOT NULL DEFAULT LOCALTIMESTAMP,
+    sent_at           TIMESTAMP
+);
+
+CREATE INDEX notification_deliveries_user_idx ON notification_deliveries (personal_info_id, id);
+CREATE INDEX notification_deliveries_event_type_idx ON notification_deliveries (event_type, id);
+CREATE INDEX notification_deliveries_status_idx ON notification_deliveries (status, id);
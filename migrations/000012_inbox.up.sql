@@ -0,0 +1,13 @@
+CREATE TABLE inbox_notifications
+(
+    id               BIGSERIAL PRIMARY KEY,
+    personal_info_id INTEGER     NOT NULL REFERENCES personal_info (personal_info_id) ON DELETE CASCADE,
+    subject          TEXT        NOT NULL DEFAULT '',
+    body             TEXT        NOT NULL,
+    content_type     VARCHAR(32) NOT NULL,
+    read_at          TIMESTAMP,
+    created_at       TIMESTAMP   NOT NULL DEFAULT LOCALTIMESTAMP
+);
+
+CREATE INDEX inbox_notifications_user_idx ON inbox_notifications (personal_info_id, id);
+CREATE INDEX inbox_notifications_unread_idx ON inbox_notifications (personal_info_id) WHERE read_at IS NULL;
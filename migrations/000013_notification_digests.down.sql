@@ -0,0 +1,3 @@
+DROP TABLE notification_digest_items;
+
+ALTER TABLE notification_preferences DROP COLUMN delivery;
@@ -0,0 +1,17 @@
+ALTER TABLE notification_preferences ADD COLUMN delivery JSONB NOT NULL DEFAULT '{}';
+
+CREATE TABLE notification_digest_items
+(
+    id               BIGSERIAL PRIMARY KEY,
+    personal_info_id INTEGER     NOT NULL REFERENCES personal_info (personal_info_id) ON DELETE CASCADE,
+    mode             VARCHAR(16) NOT NULL,
+    category         VARCHAR(32) NOT NULL,
+    event_type       VARCHAR(64) NOT NULL,
+    subject          TEXT        NOT NULL DEFAULT '',
+    body             TEXT        NOT NULL,
+    created_at       TIMESTAMP   NOT NULL DEFAULT LOCALTIMESTAMP,
+    queued_at        TIMESTAMP
+);
+
+CREATE INDEX notification_digest_items_pending_idx ON notification_digest_items (mode, created_at)
+    WHERE queued_at IS NULL;
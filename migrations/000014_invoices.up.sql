@@ -0,0 +1,13 @@
+-- invoices are issued per enrolled user, a course with unpaid invoices is
+-- archived only when forced
+CREATE TABLE invoices
+(
+    id               BIGSERIAL PRIMARY KEY,
+    course_id        INTEGER        NOT NULL REFERENCES courses (course_id),
+    personal_info_id INTEGER        NOT NULL REFERENCES personal_info (personal_info_id) ON DELETE CASCADE,
+    amount           NUMERIC(10, 2) NOT NULL CHECK (amount >= 0),
+    issued_at        TIMESTAMP      NOT NULL DEFAULT LOCALTIMESTAMP,
+    paid_at          TIMESTAMP
+);
+
+CREATE INDEX invoices_unpaid_idx ON invoices (course_id) WHERE paid_at IS NULL;
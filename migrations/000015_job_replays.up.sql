@@ -0,0 +1,5 @@
+-- replays counts how often a dead-lettered job was returned to its queue, a
+-- replay resets the attempts so settlements of the old delivery are told
+-- apart by the replay generation
+ALTER TABLE jobs
+    ADD COLUMN replays INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+ALTER TABLE personal_info
+    DROP COLUMN phone_verified_at;
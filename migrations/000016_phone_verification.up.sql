@@ -0,0 +1,4 @@
+-- phone_verified_at is set once the user confirms a one-time code sent to the
+-- phone and cleared when the phone changes
+ALTER TABLE personal_info
+    ADD COLUMN phone_verified_at TIMESTAMP;
@@ -0,0 +1,3 @@
+ALTER TABLE notification_deliveries
+    DROP COLUMN alt_body,
+    DROP COLUMN attachments;
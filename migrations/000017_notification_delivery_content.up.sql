@@ -0,0 +1,5 @@
+-- the plain text alternative and the attachments are kept so that failed
+-- deliveries are resent as they were
+ALTER TABLE notification_deliveries
+    ADD COLUMN alt_body    TEXT  NOT NULL DEFAULT '',
+    ADD COLUMN attachments JSONB NOT NULL DEFAULT '[]';
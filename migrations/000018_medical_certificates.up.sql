@@ -0,0 +1,12 @@
+-- medical certificates admit users to training until they expire, the user
+-- and the trainers of the user's courses are notified once before that
+CREATE TABLE medical_certificates
+(
+    id               BIGSERIAL PRIMARY KEY,
+    personal_info_id INTEGER   NOT NULL REFERENCES personal_info (personal_info_id) ON DELETE CASCADE,
+    expires_at       TIMESTAMP NOT NULL,
+    created_at       TIMESTAMP NOT NULL DEFAULT LOCALTIMESTAMP,
+    notified_at      TIMESTAMP
+);
+
+CREATE INDEX medical_certificates_pending_idx ON medical_certificates (expires_at) WHERE notified_at IS NULL;
@@ -1,6 +1,7 @@
 package jwt
 
 import (
+	"context"
 	"dussh/internal/utils/bytesconv"
 	"errors"
 	"fmt"
@@ -158,3 +159,16 @@ func ExtractBearerToken(header string) (string, error) {
 
 	return jwtToken[1], nil
 }
+
+const ctxUserClaims = "userClaimsKey"
+
+// ContextWithUserClaims adds user claims to context.
+func ContextWithUserClaims(c *gin.Context, claims *UserClaims) {
+	c.Set(ctxUserClaims, claims)
+}
+
+// UserClaimsFromContext returns user claims from context.
+func UserClaimsFromContext(ctx context.Context) (*UserClaims, bool) {
+	claims, ok := ctx.Value(ctxUserClaims).(*UserClaims)
+	return claims, ok
+}
@@ -4,11 +4,23 @@ import (
 	"dussh/pkg/notify/notification"
 	"dussh/pkg/notify/provider"
 	"dussh/pkg/notify/provider/email"
+	"dussh/pkg/notify/provider/inapp"
+	"dussh/pkg/notify/provider/mailsink"
+	"dussh/pkg/notify/provider/sms"
+	"dussh/pkg/notify/provider/telegram"
 )
 
 type Config struct {
 	// Email is the configuration for the email notify provider
 	Email *email.NotificationProvider
+	// MailSink captures emails instead of Email if set, for development only
+	MailSink *mailsink.NotificationProvider
+	// Telegram is the configuration for the telegram notify provider
+	Telegram *telegram.NotificationProvider
+	// SMS is the configuration for the sms notify provider
+	SMS *sms.NotificationProvider
+	// InApp is the configuration for the in-app inbox notify provider
+	InApp *inapp.NotificationProvider
 }
 
 func (c *Config) GetNotificationProviderByType(
@@ -16,7 +28,16 @@ func (c *Config) GetNotificationProviderByType(
 ) provider.NotificationProvider {
 	switch notificationType {
 	case notification.TypeEmail:
+		if c.MailSink != nil {
+			return c.MailSink
+		}
 		return c.Email
+	case notification.TypeTelegram:
+		return c.Telegram
+	case notification.TypeSMS:
+		return c.SMS
+	case notification.TypeInApp:
+		return c.InApp
 	default:
 		return nil
 	}
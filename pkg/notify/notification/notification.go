@@ -6,4 +6,19 @@ type Notification struct {
 	To          []string
 	Subject     string
 	Body        string
+	// AltBody is the plain text alternative of an HTML body, sent by email
+	// providers only.
+	AltBody string
+	// Attachments are sent by email providers only.
+	Attachments []Attachment
+}
+
+// Attachment is a file sent with the notification, such as an .ics invite or
+// a PDF invoice. Inline attachments are shown in the HTML body, which refers
+// to them as cid:<Name>.
+type Attachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+	Inline      bool
 }
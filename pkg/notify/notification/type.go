@@ -5,6 +5,12 @@ type Type string
 const (
 	// TypeEmail is Type for the email notify provider
 	TypeEmail Type = "email"
+	// TypeTelegram is Type for the telegram notify provider
+	TypeTelegram Type = "telegram"
+	// TypeSMS is Type for the sms notify provider
+	TypeSMS Type = "sms"
+	// TypeInApp is Type for the in-app inbox notify provider
+	TypeInApp Type = "in_app"
 )
 
 type ContentType string
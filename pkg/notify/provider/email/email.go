@@ -4,24 +4,80 @@ import (
 	"context"
 	"crypto/tls"
 	"dussh/pkg/notify/notification"
+	"errors"
+	"fmt"
 	"gopkg.in/gomail.v2"
+	"io"
 	"math"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrUnknownSecurity     = errors.New("unknown smtp security")
+	ErrStartTLSUnsupported = errors.New("smtp server does not support STARTTLS")
+	ErrProviderClosed      = errors.New("email provider closed")
+)
+
+// Security of the connection to the SMTP server.
+type Security string
+
+const (
+	// SecurityStartTLS upgrades the connection with STARTTLS, servers that
+	// don't support it are refused.
+	SecurityStartTLS Security = "starttls"
+	// SecurityTLS connects over TLS, usually to port 465.
+	SecurityTLS Security = "tls"
+	// SecurityNone sends in plain text, meant for local mail sinks only.
+	SecurityNone Security = "none"
+)
+
+// DriverSMTP is the driver of the email provider that sends over SMTP.
+const DriverSMTP = "smtp"
+
+const (
+	defaultPoolSize    = 2
+	defaultIdleTimeout = time.Minute
+	// defaultTimeout bounds a send without a context deadline.
+	defaultTimeout = 30 * time.Second
 )
 
 type NotificationProvider struct {
-	From      string
-	Username  string
-	Password  string
-	Host      string
-	Port      int
-	TLSEnable bool
+	From     string
+	Username string
+	Password string
+	Host     string
+	Port     int
+	// Security of the connection, SecurityTLS for port 465 and
+	// SecurityStartTLS for other ports if empty
+	Security Security
+	// InsecureSkipVerify accepts any certificate of the server, for testing only
+	InsecureSkipVerify bool
+	// PoolSize is the number of idle connections kept open, 2 if zero
+	PoolSize int
+	// IdleTimeout closes connections idle for longer, a minute if zero
+	IdleTimeout time.Duration
+
+	once sync.Once
+	pool *pool
 }
 
 // IsValid returns whether the provider's configuration is valid
 func (provider *NotificationProvider) IsValid() bool {
+	if provider == nil {
+		return false
+	}
+
+	_, err := provider.security()
 	isValid := len(provider.From) > 0 && len(provider.Host) > 0 &&
-		provider.Port > 0 && provider.Port < math.MaxUint16
+		provider.Port > 0 && provider.Port < math.MaxUint16 && err == nil
 
 	return isValid
 }
@@ -29,35 +85,229 @@ func (provider *NotificationProvider) IsValid() bool {
 // Send a notification using the provider
 func (provider *NotificationProvider) Send(
 	ctx context.Context,
-	notification *notification.Notification,
+	n *notification.Notification,
 ) error {
-	var username string
-	if len(provider.Username) > 0 {
-		username = provider.Username
+	return provider.SendBulk(ctx, []*notification.Notification{n})
+}
+
+// SendBulk sends the notifications over one pooled connection, a broken
+// connection is replaced and the notification sent again once.
+func (provider *NotificationProvider) SendBulk(
+	ctx context.Context,
+	notifications []*notification.Notification,
+) error {
+	p := provider.connections()
+
+	c, err := p.get(ctx)
+	if err != nil {
+		return err
+	}
+
+	from := provider.envelopeFrom()
+
+	var errs []error
+	for _, n := range notifications {
+		m := composeMessage(provider.From, n)
+
+		err := c.send(ctx, from, n.To, m)
+		if err != nil && !isProtocolError(err) {
+			// the server may have closed the pooled connection
+			c.close()
+			if c, err = p.dial(ctx); err != nil {
+				return errors.Join(append(errs, err)...)
+			}
+			err = c.send(ctx, from, n.To, m)
+		}
+		if err == nil {
+			continue
+		}
+
+		errs = append(errs, err)
+		if isProtocolError(err) && c.client.Reset() == nil {
+			continue
+		}
+		c.close()
+		if c, err = p.dial(ctx); err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+	}
+
+	p.put(c)
+	return errors.Join(errs...)
+}
+
+// Close closes the idle connections, notifications can't be sent afterwards.
+func (provider *NotificationProvider) Close() error {
+	provider.connections().close()
+	return nil
+}
+
+func (provider *NotificationProvider) connections() *pool {
+	provider.once.Do(func() {
+		size := provider.PoolSize
+		if size <= 0 {
+			size = defaultPoolSize
+		}
+		idleTimeout := provider.IdleTimeout
+		if idleTimeout <= 0 {
+			idleTimeout = defaultIdleTimeout
+		}
+
+		provider.pool = newPool(provider.dial, size, idleTimeout)
+	})
+
+	return provider.pool
+}
+
+func (provider *NotificationProvider) security() (Security, error) {
+	switch provider.Security {
+	case "":
+		if provider.Port == 465 {
+			return SecurityTLS, nil
+		}
+		return SecurityStartTLS, nil
+	case SecurityStartTLS, SecurityTLS, SecurityNone:
+		return provider.Security, nil
+	default:
+		return "", fmt.Errorf("%w %q", ErrUnknownSecurity, provider.Security)
+	}
+}
+
+// dial connects to the server, secures the connection and authenticates if a
+// password is set.
+func (provider *NotificationProvider) dial(ctx context.Context) (*conn, error) {
+	security, err := provider.security()
+	if err != nil {
+		return nil, err
+	}
+
+	addr := net.JoinHostPort(provider.Host, strconv.Itoa(provider.Port))
+	tlsConfig := &tls.Config{ServerName: provider.Host, InsecureSkipVerify: provider.InsecureSkipVerify}
+	dialer := &net.Dialer{Timeout: defaultTimeout}
+
+	var nc net.Conn
+	if security == SecurityTLS {
+		nc, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
 	} else {
+		nc, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	setDeadline(ctx, nc)
+
+	client, err := smtp.NewClient(nc, provider.Host)
+	if err != nil {
+		nc.Close()
+		return nil, err
+	}
+
+	c := &conn{net: nc, client: client}
+	if err := provider.handshake(client, security, tlsConfig); err != nil {
+		c.close()
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (provider *NotificationProvider) handshake(client *smtp.Client, security Security, tlsConfig *tls.Config) error {
+	if err := client.Hello(provider.localName()); err != nil {
+		return err
+	}
+
+	if security == SecurityStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return ErrStartTLSUnsupported
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+
+	if len(provider.Password) == 0 {
+		return nil
+	}
+
+	username := provider.Username
+	if len(username) == 0 {
 		username = provider.From
 	}
+
+	return client.Auth(smtp.PlainAuth("", username, provider.Password, provider.Host))
+}
+
+// localName is the domain in the From address, sent in the greeting.
+func (provider *NotificationProvider) localName() string {
+	fromParts := strings.Split(provider.envelopeFrom(), `@`)
+	if len(fromParts) == 2 {
+		return fromParts[1]
+	}
+	return "localhost"
+}
+
+func (provider *NotificationProvider) envelopeFrom() string {
+	if addr, err := mail.ParseAddress(provider.From); err == nil {
+		return addr.Address
+	}
+	return provider.From
+}
+
+// WriteMessage writes the notification sent from the address as an RFC 5322
+// message, the way it is sent to the SMTP server.
+func WriteMessage(w io.Writer, from string, n *notification.Notification) error {
+	_, err := composeMessage(from, n).WriteTo(w)
+	return err
+}
+
+// composeMessage builds the email, an HTML body with a plain text alternative
+// is sent as multipart/alternative.
+func composeMessage(from string, n *notification.Notification) *gomail.Message {
 	m := gomail.NewMessage()
-	m.SetHeader("From", provider.From)
-	m.SetHeader("To", strings.Join(notification.To, ","))
-	m.SetHeader("Subject", notification.Subject)
-	m.SetBody(string(notification.ContentType), notification.Body)
-	var d *gomail.Dialer
-	if len(provider.Password) == 0 {
-		// Get the domain in the From address
-		localName := "localhost"
-		fromParts := strings.Split(provider.From, `@`)
-		if len(fromParts) == 2 {
-			localName = fromParts[1]
-		}
-		// Create a dialer with no authentication
-		d = &gomail.Dialer{Host: provider.Host, Port: provider.Port, LocalName: localName}
+	m.SetHeader("From", from)
+	m.SetHeader("To", strings.Join(n.To, ","))
+	m.SetHeader("Subject", n.Subject)
+
+	if n.ContentType == notification.ContentTypeHTML && len(n.AltBody) > 0 {
+		m.SetBody(string(notification.ContentTypePlain), n.AltBody)
+		m.AddAlternative(string(n.ContentType), n.Body)
 	} else {
-		// Create an authenticated dialer
-		d = gomail.NewDialer(provider.Host, provider.Port, username, provider.Password)
+		m.SetBody(string(n.ContentType), n.Body)
 	}
-	if !provider.TLSEnable {
-		d.TLSConfig = &tls.Config{InsecureSkipVerify: true}
+
+	for _, a := range n.Attachments {
+		data := a.Data
+		settings := []gomail.FileSetting{gomail.SetCopyFunc(func(w io.Writer) error {
+			_, err := w.Write(data)
+			return err
+		})}
+		if len(a.ContentType) > 0 {
+			settings = append(settings, gomail.SetHeader(map[string][]string{
+				"Content-Type": {mime.FormatMediaType(a.ContentType, map[string]string{"name": a.Name})},
+			}))
+		}
+
+		if a.Inline {
+			m.Embed(a.Name, settings...)
+		} else {
+			m.Attach(a.Name, settings...)
+		}
+	}
+
+	return m
+}
+
+// isProtocolError reports whether the server rejected the command, the
+// connection can be used further after a reset.
+func isProtocolError(err error) bool {
+	var protoErr *textproto.Error
+	return errors.As(err, &protoErr)
+}
+
+func setDeadline(ctx context.Context, nc net.Conn) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(defaultTimeout)
 	}
-	return d.DialAndSend(m)
+	_ = nc.SetDeadline(deadline)
 }
@@ -3,18 +3,22 @@ package email
 import (
 	"context"
 	"dussh/pkg/notify/notification"
+	"errors"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
 	"testing"
 )
 
 func TestSend(t *testing.T) {
 	var (
 		provider = &NotificationProvider{
-			From:      "example@conmpany.com",
-			Username:  "f0d73492594ef7",
-			Password:  "ee6d5d7a04bbbd",
-			Host:      "sandbox.smtp.mailtrap.io",
-			Port:      2525,
-			TLSEnable: false,
+			From:     "example@conmpany.com",
+			Username: "f0d73492594ef7",
+			Password: "ee6d5d7a04bbbd",
+			Host:     "sandbox.smtp.mailtrap.io",
+			Port:     2525,
 		}
 		ntf = &notification.Notification{
 			Type:        notification.TypeEmail,
@@ -34,3 +38,176 @@ func TestSend(t *testing.T) {
 
 	t.Log("send notify successfully")
 }
+
+// fakeSMTP is a plain SMTP server that records the messages it accepts.
+type fakeSMTP struct {
+	t        *testing.T
+	listener net.Listener
+
+	mu       sync.Mutex
+	conns    []net.Conn
+	dials    int
+	messages []string
+}
+
+func newFakeSMTP(t *testing.T) *fakeSMTP {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	s := &fakeSMTP{t: t, listener: listener}
+	go s.serve()
+	t.Cleanup(func() { listener.Close() })
+	return s
+}
+
+func (s *fakeSMTP) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeSMTP) serve() {
+	for {
+		c, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns = append(s.conns, c)
+		s.dials++
+		s.mu.Unlock()
+		go s.handle(c)
+	}
+}
+
+func (s *fakeSMTP) handle(c net.Conn) {
+	defer c.Close()
+
+	r := textproto.NewConn(c)
+	r.PrintfLine("220 fake ESMTP")
+	for {
+		line, err := r.ReadLine()
+		if err != nil {
+			return
+		}
+
+		switch cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0]); cmd {
+		case "EHLO":
+			r.PrintfLine("250 fake")
+		case "MAIL", "RCPT", "RSET", "NOOP":
+			if strings.Contains(line, "rejected@") {
+				r.PrintfLine("550 mailbox unavailable")
+				continue
+			}
+			r.PrintfLine("250 OK")
+		case "DATA":
+			r.PrintfLine("354 go ahead")
+			data, err := r.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, string(data))
+			s.mu.Unlock()
+			r.PrintfLine("250 queued")
+		case "QUIT":
+			r.PrintfLine("221 bye")
+			return
+		default:
+			r.PrintfLine("502 %s not implemented", cmd)
+		}
+	}
+}
+
+// drop closes the open connections as a server does on idle timeout.
+func (s *fakeSMTP) drop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.conns {
+		c.Close()
+	}
+	s.conns = nil
+}
+
+func TestSendBulk(t *testing.T) {
+	server := newFakeSMTP(t)
+	provider := &NotificationProvider{
+		From:     "School <dussh@school.com>",
+		Host:     "127.0.0.1",
+		Port:     server.port(),
+		Security: SecurityNone,
+	}
+	defer provider.Close()
+
+	ctx := context.Background()
+	html := &notification.Notification{
+		Type:        notification.TypeEmail,
+		ContentType: notification.ContentTypeHTML,
+		To:          []string{"a@school.com"},
+		Subject:     "Invite",
+		Body:        `<p>See you</p><img src="cid:logo.png">`,
+		AltBody:     "See you",
+		Attachments: []notification.Attachment{
+			{Name: "logo.png", ContentType: "image/png", Data: []byte("png"), Inline: true},
+			{Name: "session.ics", ContentType: "text/calendar", Data: []byte("BEGIN:VCALENDAR")},
+		},
+	}
+	rejected := &notification.Notification{
+		Type:        notification.TypeEmail,
+		ContentType: notification.ContentTypePlain,
+		To:          []string{"rejected@school.com"},
+		Body:        "Test",
+	}
+
+	err := provider.SendBulk(ctx, []*notification.Notification{rejected, html})
+	var protoErr *textproto.Error
+	if !errors.As(err, &protoErr) || protoErr.Code != 550 {
+		t.Fatalf("expected the rejected recipient error, got %v", err)
+	}
+	if err := provider.Send(ctx, html); err != nil {
+		t.Fatalf("failed to send over the pooled connection: %v", err)
+	}
+
+	server.mu.Lock()
+	dials, messages := server.dials, server.messages
+	server.mu.Unlock()
+	if dials != 1 || len(messages) != 2 {
+		t.Fatalf("expected 2 messages over 1 connection, got %d over %d", len(messages), dials)
+	}
+	for _, want := range []string{
+		"multipart/alternative",
+		"Content-ID: <logo.png>",
+		`Content-Type: text/calendar; name=session.ics`,
+		"Content-Disposition: attachment; filename=\"session.ics\"",
+	} {
+		if !strings.Contains(messages[0], want) {
+			t.Errorf("message does not contain %q:\n%s", want, messages[0])
+		}
+	}
+
+	// a connection dropped by the server is replaced
+	server.drop()
+	if err := provider.Send(ctx, html); err != nil {
+		t.Fatalf("failed to send after the connection was dropped: %v", err)
+	}
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if server.dials != 2 {
+		t.Errorf("expected a reconnect, got %d dials", server.dials)
+	}
+}
+
+func TestSendRequiresStartTLS(t *testing.T) {
+	server := newFakeSMTP(t)
+	provider := &NotificationProvider{From: "dussh@school.com", Host: "127.0.0.1", Port: server.port()}
+
+	err := provider.Send(context.Background(), &notification.Notification{
+		Type:        notification.TypeEmail,
+		ContentType: notification.ContentTypePlain,
+		To:          []string{"a@school.com"},
+		Body:        "Test",
+	})
+	if !errors.Is(err, ErrStartTLSUnsupported) {
+		t.Fatalf("expected STARTTLS to be required, got %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+package email
+
+import (
+	"context"
+	"gopkg.in/gomail.v2"
+	"net"
+	"net/smtp"
+	"sync"
+	"time"
+)
+
+// conn is an authenticated connection to the SMTP server.
+type conn struct {
+	net    net.Conn
+	client *smtp.Client
+	usedAt time.Time
+}
+
+// send sends the message to the recipients in one mail transaction.
+func (c *conn) send(ctx context.Context, from string, to []string, m *gomail.Message) error {
+	setDeadline(ctx, c.net)
+
+	if err := c.client.Mail(from); err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		if err := c.client.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+
+	w, err := c.client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := m.WriteTo(w); err != nil {
+		w.Close()
+		return err
+	}
+
+	return w.Close()
+}
+
+// alive reports whether the server still answers on the connection.
+func (c *conn) alive(ctx context.Context) bool {
+	setDeadline(ctx, c.net)
+	return c.client.Noop() == nil
+}
+
+// close says goodbye to the server, the connection is closed either way.
+func (c *conn) close() {
+	_ = c.client.Quit()
+	_ = c.client.Close()
+}
+
+// pool keeps up to size idle connections open for reuse. Connections idle for
+// longer than idleTimeout or dropped by the server are replaced on get.
+type pool struct {
+	dial        func(context.Context) (*conn, error)
+	idleTimeout time.Duration
+
+	mu     sync.Mutex
+	idle   []*conn
+	size   int
+	closed bool
+}
+
+func newPool(dial func(context.Context) (*conn, error), size int, idleTimeout time.Duration) *pool {
+	return &pool{
+		dial:        dial,
+		idleTimeout: idleTimeout,
+		size:        size,
+	}
+}
+
+// get returns an idle connection or dials a new one.
+func (p *pool) get(ctx context.Context) (*conn, error) {
+	for {
+		p.mu.Lock()
+		if p.closed {
+			p.mu.Unlock()
+			return nil, ErrProviderClosed
+		}
+		if len(p.idle) == 0 {
+			p.mu.Unlock()
+			return p.dial(ctx)
+		}
+		// the most recently used connection is the most likely to be alive
+		c := p.idle[len(p.idle)-1]
+		p.idle = p.idle[:len(p.idle)-1]
+		p.mu.Unlock()
+
+		if time.Since(c.usedAt) < p.idleTimeout && c.alive(ctx) {
+			return c, nil
+		}
+		c.close()
+	}
+}
+
+// put returns the connection to the pool, it is closed if the pool is full.
+func (p *pool) put(c *conn) {
+	c.usedAt = time.Now()
+
+	p.mu.Lock()
+	if p.closed || len(p.idle) >= p.size {
+		p.mu.Unlock()
+		c.close()
+		return
+	}
+	p.idle = append(p.idle, c)
+	p.mu.Unlock()
+}
+
+func (p *pool) close() {
+	p.mu.Lock()
+	idle := p.idle
+	p.idle, p.closed = nil, true
+	p.mu.Unlock()
+
+	for _, c := range idle {
+		c.close()
+	}
+}
@@ -0,0 +1,59 @@
+package inapp
+
+import (
+	"context"
+	"dussh/pkg/notify/notification"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+var ErrInvalidUserID = errors.New("invalid in-app recipient")
+
+// Message is a notification put into the inbox of a user.
+type Message struct {
+	Subject     string
+	Body        string
+	ContentType notification.ContentType
+}
+
+// Inbox stores messages per user and pushes them to the open streams of the
+// user.
+type Inbox interface {
+	Deliver(ctx context.Context, userID int64, msg Message) error
+}
+
+type NotificationProvider struct {
+	Inbox Inbox
+}
+
+// IsValid returns whether the provider's configuration is valid
+func (provider *NotificationProvider) IsValid() bool {
+	return provider != nil && provider.Inbox != nil
+}
+
+// Send a notification using the provider, To holds user IDs
+func (provider *NotificationProvider) Send(
+	ctx context.Context,
+	notification *notification.Notification,
+) error {
+	msg := Message{
+		Subject:     notification.Subject,
+		Body:        notification.Body,
+		ContentType: notification.ContentType,
+	}
+
+	var errs []error
+	for _, to := range notification.To {
+		userID, err := strconv.ParseInt(to, 10, 64)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%w %q", ErrInvalidUserID, to))
+			continue
+		}
+		if err := provider.Inbox.Deliver(ctx, userID, msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
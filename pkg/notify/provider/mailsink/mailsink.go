@@ -0,0 +1,149 @@
+package mailsink
+
+import (
+	"bytes"
+	"context"
+	"dussh/pkg/notify/notification"
+	"dussh/pkg/notify/provider/email"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+var ErrMessageNotFound = errors.New("captured message not found")
+
+// Drivers of the email provider that capture the emails.
+const (
+	DriverFile   = "file"
+	DriverMemory = "memory"
+)
+
+const defaultLimit = 100
+
+// Message is an email captured by the sink. Text and HTML are the bodies of
+// the notification, Raw is the message as it would have been sent.
+type Message struct {
+	ID          string    `json:"id"`
+	From        string    `json:"from"`
+	To          []string  `json:"to"`
+	Subject     string    `json:"subject"`
+	Text        string    `json:"text,omitempty"`
+	HTML        string    `json:"html,omitempty"`
+	Attachments []string  `json:"attachments,omitempty"`
+	Size        int       `json:"size"`
+	CapturedAt  time.Time `json:"captured_at"`
+	// Path of the .eml file, empty if the sink keeps messages in memory only
+	Path string `json:"path,omitempty"`
+	Raw  []byte `json:"-"`
+}
+
+// NotificationProvider captures emails instead of sending them, it is meant
+// for development and tests where there is no SMTP server. Messages are kept
+// in memory and, if Dir is set, written to it as .eml files.
+type NotificationProvider struct {
+	From string
+	// Dir the .eml files are written to, no files are written if empty
+	Dir string
+	// Limit of the messages kept in memory, 100 if zero
+	Limit int
+
+	mu       sync.Mutex
+	messages []*Message
+	seq      int
+}
+
+// IsValid returns whether the provider's configuration is valid
+func (provider *NotificationProvider) IsValid() bool {
+	return provider != nil && len(provider.From) > 0 && provider.Limit >= 0
+}
+
+// Send captures the notification
+func (provider *NotificationProvider) Send(
+	ctx context.Context,
+	n *notification.Notification,
+) error {
+	var raw bytes.Buffer
+	if err := email.WriteMessage(&raw, provider.From, n); err != nil {
+		return err
+	}
+
+	msg := &Message{
+		From:       provider.From,
+		To:         n.To,
+		Subject:    n.Subject,
+		Text:       n.Body,
+		Size:       raw.Len(),
+		CapturedAt: time.Now(),
+		Raw:        raw.Bytes(),
+	}
+	if n.ContentType == notification.ContentTypeHTML {
+		msg.Text, msg.HTML = n.AltBody, n.Body
+	}
+	for _, a := range n.Attachments {
+		msg.Attachments = append(msg.Attachments, a.Name)
+	}
+
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+
+	provider.seq++
+	msg.ID = fmt.Sprintf("%s-%d", msg.CapturedAt.Format("20060102T150405"), provider.seq)
+
+	if len(provider.Dir) > 0 {
+		if err := os.MkdirAll(provider.Dir, 0o755); err != nil {
+			return err
+		}
+		msg.Path = filepath.Join(provider.Dir, msg.ID+".eml")
+		if err := os.WriteFile(msg.Path, msg.Raw, 0o644); err != nil {
+			return err
+		}
+	}
+
+	limit := provider.Limit
+	if limit == 0 {
+		limit = defaultLimit
+	}
+	provider.messages = append(provider.messages, msg)
+	if len(provider.messages) > limit {
+		provider.messages = provider.messages[len(provider.messages)-limit:]
+	}
+
+	return nil
+}
+
+// Messages returns the captured messages, newest first.
+func (provider *NotificationProvider) Messages() []*Message {
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+
+	messages := make([]*Message, 0, len(provider.messages))
+	for i := len(provider.messages) - 1; i >= 0; i-- {
+		messages = append(messages, provider.messages[i])
+	}
+
+	return messages
+}
+
+func (provider *NotificationProvider) Message(id string) (*Message, error) {
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+
+	for _, msg := range provider.messages {
+		if msg.ID == id {
+			return msg, nil
+		}
+	}
+
+	return nil, ErrMessageNotFound
+}
+
+// Clear forgets the captured messages, the .eml files are kept.
+func (provider *NotificationProvider) Clear() {
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+
+	provider.messages = nil
+}
@@ -0,0 +1,62 @@
+package mailsink
+
+import (
+	"context"
+	"dussh/pkg/notify/notification"
+	"errors"
+	"net/mail"
+	"os"
+	"testing"
+)
+
+func TestSend(t *testing.T) {
+	provider := &NotificationProvider{From: "dussh@school.com", Dir: t.TempDir(), Limit: 2}
+	if !provider.IsValid() {
+		t.Fatal("provider config is invalid")
+	}
+
+	ctx := context.Background()
+	for _, subject := range []string{"First", "Second", "Third"} {
+		err := provider.Send(ctx, &notification.Notification{
+			Type:        notification.TypeEmail,
+			ContentType: notification.ContentTypeHTML,
+			To:          []string{"a@school.com"},
+			Subject:     subject,
+			Body:        "<p>" + subject + "</p>",
+			AltBody:     subject,
+		})
+		if err != nil {
+			t.Fatalf("failed to capture message: %v", err)
+		}
+	}
+
+	messages := provider.Messages()
+	if len(messages) != 2 || messages[0].Subject != "Third" || messages[1].Subject != "Second" {
+		t.Fatalf("expected the last 2 messages newest first, got %+v", messages)
+	}
+	if messages[0].Text != "Third" || messages[0].HTML != "<p>Third</p>" {
+		t.Errorf("unexpected bodies: %+v", messages[0])
+	}
+
+	file, err := os.Open(messages[0].Path)
+	if err != nil {
+		t.Fatalf("failed to open .eml file: %v", err)
+	}
+	defer file.Close()
+
+	eml, err := mail.ReadMessage(file)
+	if err != nil {
+		t.Fatalf("failed to parse .eml file: %v", err)
+	}
+	if eml.Header.Get("Subject") != "Third" || eml.Header.Get("Date") == "" {
+		t.Errorf("unexpected headers: %v", eml.Header)
+	}
+
+	if _, err := provider.Message(messages[1].ID); err != nil {
+		t.Errorf("failed to get message by id: %v", err)
+	}
+	provider.Clear()
+	if _, err := provider.Message(messages[1].ID); !errors.Is(err, ErrMessageNotFound) {
+		t.Errorf("expected cleared message to be gone, got %v", err)
+	}
+}
@@ -4,6 +4,10 @@ import (
 	"context"
 	"dussh/pkg/notify/notification"
 	"dussh/pkg/notify/provider/email"
+	"dussh/pkg/notify/provider/inapp"
+	"dussh/pkg/notify/provider/mailsink"
+	"dussh/pkg/notify/provider/sms"
+	"dussh/pkg/notify/provider/telegram"
 )
 
 type NotificationProvider interface {
@@ -16,4 +20,8 @@ type NotificationProvider interface {
 
 var (
 	_ NotificationProvider = (*email.NotificationProvider)(nil)
+	_ NotificationProvider = (*telegram.NotificationProvider)(nil)
+	_ NotificationProvider = (*sms.NotificationProvider)(nil)
+	_ NotificationProvider = (*inapp.NotificationProvider)(nil)
+	_ NotificationProvider = (*mailsink.NotificationProvider)(nil)
 )
@@ -0,0 +1,120 @@
+package sms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// Gateways selectable by config.
+const (
+	GatewayHTTP = "http"
+	GatewayFake = "fake"
+)
+
+// Message is a text message handed to a gateway. The gateway concatenates
+// the parts on the phone, Parts is the number it is billed for.
+type Message struct {
+	From     string
+	To       string
+	Text     string
+	Encoding Encoding
+	Parts    int
+}
+
+// Gateway delivers text messages to an SMS operator.
+type Gateway interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// GatewayError is a message rejected by the gateway.
+type GatewayError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *GatewayError) Error() string {
+	return fmt.Sprintf("sms gateway error %d: %s", e.StatusCode, e.Body)
+}
+
+// HTTPGateway posts messages as JSON to the URL of the gateway, authorized
+// with a bearer API key.
+type HTTPGateway struct {
+	URL    string
+	APIKey string
+	// Client is used for gateway requests, http.DefaultClient if nil, which
+	// has no timeout
+	Client *http.Client
+}
+
+func (g *HTTPGateway) Send(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(struct {
+		From     string   `json:"from,omitempty"`
+		To       string   `json:"to"`
+		Text     string   `json:"text"`
+		Encoding Encoding `json:"encoding"`
+	}{msg.From, msg.To, msg.Text, msg.Encoding})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if g.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+g.APIKey)
+	}
+
+	client := g.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &GatewayError{StatusCode: resp.StatusCode, Body: string(bytes.TrimSpace(respBody))}
+	}
+
+	return nil
+}
+
+// FakeGateway keeps the messages in memory instead of sending them, for
+// local development and tests.
+type FakeGateway struct {
+	// OnSend is called with every message, e.g. to log it
+	OnSend func(Message)
+
+	mu       sync.Mutex
+	messages []Message
+}
+
+func (g *FakeGateway) Send(_ context.Context, msg Message) error {
+	g.mu.Lock()
+	g.messages = append(g.messages, msg)
+	g.mu.Unlock()
+
+	if g.OnSend != nil {
+		g.OnSend(msg)
+	}
+	return nil
+}
+
+// Messages returns the messages sent so far.
+func (g *FakeGateway) Messages() []Message {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return append([]Message(nil), g.messages...)
+}
@@ -0,0 +1,96 @@
+package sms
+
+import "strings"
+
+// Encoding is the character set a message is sent in.
+type Encoding string
+
+const (
+	// EncodingGSM7 packs characters of the GSM 03.38 alphabet in 7 bits.
+	EncodingGSM7 Encoding = "gsm7"
+	// EncodingUCS2 is used as soon as the text has a character outside the
+	// GSM alphabet, Cyrillic always is.
+	EncodingUCS2 Encoding = "ucs2"
+)
+
+// Septets or UTF-16 code units fitting a single message, and a part of a
+// concatenated message which loses some of them to the UDH header.
+const (
+	gsm7SingleLimit = 160
+	gsm7PartLimit   = 153
+	ucs2SingleLimit = 70
+	ucs2PartLimit   = 67
+)
+
+const (
+	gsm7Basic = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+		"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+	// gsm7Extension characters are sent with an escape, taking two septets
+	gsm7Extension = "\f^{}\\[~]|€"
+)
+
+// EncodingOf returns the encoding the text has to be sent in.
+func EncodingOf(text string) Encoding {
+	for _, r := range text {
+		if !strings.ContainsRune(gsm7Basic, r) && !strings.ContainsRune(gsm7Extension, r) {
+			return EncodingUCS2
+		}
+	}
+	return EncodingGSM7
+}
+
+// Split splits the text into the parts of a concatenated message. A text
+// fitting a single message is returned as is. A character is never split
+// between parts, neither an escaped GSM character nor a surrogate pair.
+func Split(text string) (Encoding, []string) {
+	encoding := EncodingOf(text)
+
+	singleLimit, partLimit := gsm7SingleLimit, gsm7PartLimit
+	if encoding == EncodingUCS2 {
+		singleLimit, partLimit = ucs2SingleLimit, ucs2PartLimit
+	}
+
+	if length(text, encoding) <= singleLimit {
+		return encoding, []string{text}
+	}
+
+	var (
+		parts []string
+		start int
+		size  int
+	)
+	for i, r := range text {
+		n := runeLength(r, encoding)
+		if size+n > partLimit {
+			parts = append(parts, text[start:i])
+			start, size = i, 0
+		}
+		size += n
+	}
+	parts = append(parts, text[start:])
+
+	return encoding, parts
+}
+
+// length returns the length of the text in septets or UTF-16 code units.
+func length(text string, encoding Encoding) int {
+	var n int
+	for _, r := range text {
+		n += runeLength(r, encoding)
+	}
+	return n
+}
+
+func runeLength(r rune, encoding Encoding) int {
+	if encoding == EncodingUCS2 {
+		if r > 0xFFFF {
+			return 2
+		}
+		return 1
+	}
+
+	if strings.ContainsRune(gsm7Extension, r) {
+		return 2
+	}
+	return 1
+}
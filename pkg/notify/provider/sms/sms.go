@@ -0,0 +1,67 @@
+package sms
+
+import (
+	"context"
+	"dussh/pkg/notify/notification"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+var (
+	ErrInvalidPhone       = errors.New("phone number is not in E.164 format")
+	ErrUnsupportedContent = errors.New("sms supports plain text only")
+	ErrMessageTooLong     = errors.New("sms message is too long")
+)
+
+var e164 = regexp.MustCompile(`^\+[1-9]\d{1,14}$`)
+
+type NotificationProvider struct {
+	// From is the sender name or number, the gateway default if empty
+	From    string
+	Gateway Gateway
+	// MaxParts limits the parts of a concatenated message, unlimited if zero
+	MaxParts int
+}
+
+// IsValid returns whether the provider's configuration is valid
+func (provider *NotificationProvider) IsValid() bool {
+	return provider != nil && provider.Gateway != nil && provider.MaxParts >= 0
+}
+
+// Send a notification using the provider, To holds E.164 phone numbers.
+// The subject is not sent, every part of the message is paid for.
+func (provider *NotificationProvider) Send(
+	ctx context.Context,
+	n *notification.Notification,
+) error {
+	if n.ContentType == notification.ContentTypeHTML {
+		return ErrUnsupportedContent
+	}
+
+	encoding, parts := Split(n.Body)
+	if provider.MaxParts > 0 && len(parts) > provider.MaxParts {
+		return fmt.Errorf("%w: %d parts, at most %d allowed", ErrMessageTooLong, len(parts), provider.MaxParts)
+	}
+
+	var errs []error
+	for _, to := range n.To {
+		if !e164.MatchString(to) {
+			errs = append(errs, fmt.Errorf("%w: %q", ErrInvalidPhone, to))
+			continue
+		}
+
+		err := provider.Gateway.Send(ctx, Message{
+			From:     provider.From,
+			To:       to,
+			Text:     n.Body,
+			Encoding: encoding,
+			Parts:    len(parts),
+		})
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
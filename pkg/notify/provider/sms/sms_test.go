@@ -0,0 +1,117 @@
+package sms
+
+import (
+	"context"
+	"dussh/pkg/notify/notification"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		encoding Encoding
+		parts    []int
+	}{
+		{"gsm7 single", strings.Repeat("a", 160), EncodingGSM7, []int{160}},
+		{"gsm7 concatenated", strings.Repeat("a", 161), EncodingGSM7, []int{153, 8}},
+		{"gsm7 extension takes two septets", strings.Repeat("€", 80), EncodingGSM7, []int{80}},
+		{"gsm7 extension is not split", strings.Repeat("a", 152) + "€" + strings.Repeat("b", 10), EncodingGSM7, []int{152, 11}},
+		{"cyrillic single", strings.Repeat("я", 70), EncodingUCS2, []int{70}},
+		{"cyrillic concatenated", strings.Repeat("я", 71), EncodingUCS2, []int{67, 4}},
+		{"surrogate pair is not split", strings.Repeat("я", 66) + "😀" + strings.Repeat("я", 5), EncodingUCS2, []int{66, 6}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoding, parts := Split(tt.text)
+			if encoding != tt.encoding {
+				t.Errorf("encoding = %s, want %s", encoding, tt.encoding)
+			}
+
+			got := make([]int, len(parts))
+			for i, part := range parts {
+				got[i] = len([]rune(part))
+			}
+			if strings.Join(parts, "") != tt.text || len(got) != len(tt.parts) {
+				t.Fatalf("parts = %v, want rune lengths %v", got, tt.parts)
+			}
+			for i := range got {
+				if got[i] != tt.parts[i] {
+					t.Fatalf("parts = %v, want rune lengths %v", got, tt.parts)
+				}
+			}
+		})
+	}
+}
+
+func TestSend(t *testing.T) {
+	gateway := &FakeGateway{}
+	provider := &NotificationProvider{From: "DUSSH", Gateway: gateway, MaxParts: 2}
+
+	err := provider.Send(context.Background(), &notification.Notification{
+		Type:        notification.TypeSMS,
+		ContentType: notification.ContentTypePlain,
+		To:          []string{"+79991234567", "89991234567"},
+		Body:        "Занятие отменено",
+	})
+	if !errors.Is(err, ErrInvalidPhone) {
+		t.Fatalf("expected invalid phone error, got %v", err)
+	}
+
+	messages := gateway.Messages()
+	if len(messages) != 1 {
+		t.Fatalf("expected 1 message to be sent, got %d", len(messages))
+	}
+	if msg := messages[0]; msg.To != "+79991234567" || msg.Encoding != EncodingUCS2 || msg.Parts != 1 {
+		t.Errorf("unexpected message: %+v", msg)
+	}
+
+	err = provider.Send(context.Background(), &notification.Notification{
+		Type: notification.TypeSMS,
+		To:   []string{"+79991234567"},
+		Body: strings.Repeat("я", 135),
+	})
+	if !errors.Is(err, ErrMessageTooLong) {
+		t.Fatalf("expected message too long error, got %v", err)
+	}
+}
+
+func TestHTTPGateway(t *testing.T) {
+	var got map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		if got["to"] == "+10000000000" {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte("unknown number"))
+		}
+	}))
+	defer server.Close()
+
+	gateway := &HTTPGateway{URL: server.URL, APIKey: "key"}
+
+	err := gateway.Send(context.Background(), Message{To: "+79991234567", Text: "Привет", Encoding: EncodingUCS2})
+	if err != nil {
+		t.Fatalf("failed to send: %v", err)
+	}
+	if got["to"] != "+79991234567" || got["text"] != "Привет" || got["encoding"] != "ucs2" {
+		t.Errorf("unexpected request: %v", got)
+	}
+
+	err = gateway.Send(context.Background(), Message{To: "+10000000000", Text: "Hi"})
+	var gatewayErr *GatewayError
+	if !errors.As(err, &gatewayErr) || gatewayErr.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("expected gateway error, got %v", err)
+	}
+}
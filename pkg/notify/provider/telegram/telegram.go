@@ -0,0 +1,55 @@
+package telegram
+
+import (
+	"context"
+	"dussh/pkg/notify/notification"
+	"errors"
+	"html"
+	"net/http"
+)
+
+type NotificationProvider struct {
+	Token string
+	// BaseURL of the Bot API, DefaultBaseURL if empty
+	BaseURL string
+	// Client is used for API requests, http.DefaultClient if nil
+	Client *http.Client
+}
+
+// IsValid returns whether the provider's configuration is valid
+func (provider *NotificationProvider) IsValid() bool {
+	return provider != nil && len(provider.Token) > 0
+}
+
+// Send a notification using the provider, To holds chat IDs
+func (provider *NotificationProvider) Send(
+	ctx context.Context,
+	notification *notification.Notification,
+) error {
+	client := NewClient(provider.BaseURL, provider.Token, provider.Client)
+	text, parseMode := messageText(notification)
+
+	var errs []error
+	for _, chatID := range notification.To {
+		if err := client.SendMessage(ctx, chatID, text, parseMode); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// messageText puts the subject in front of the body, in bold for HTML bodies.
+func messageText(n *notification.Notification) (string, string) {
+	if n.ContentType == notification.ContentTypeHTML {
+		if n.Subject == "" {
+			return n.Body, ParseModeHTML
+		}
+		return "<b>" + html.EscapeString(n.Subject) + "</b>\n\n" + n.Body, ParseModeHTML
+	}
+
+	if n.Subject == "" {
+		return n.Body, ""
+	}
+	return n.Subject + "\n\n" + n.Body, ""
+}
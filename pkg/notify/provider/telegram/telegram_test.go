@@ -0,0 +1,104 @@
+package telegram
+
+import (
+	"context"
+	"dussh/pkg/notify/notification"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeAPI records sendMessage requests and serves canned getUpdates results.
+type fakeAPI struct {
+	t       *testing.T
+	sent    []map[string]string
+	updates []Update
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/bottoken/sendMessage":
+		var req map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			f.t.Errorf("failed to decode sendMessage request: %v", err)
+		}
+		if req["chat_id"] == "404" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]any{"ok": false, "error_code": 400, "description": "Bad Request: chat not found"})
+			return
+		}
+		f.sent = append(f.sent, req)
+		json.NewEncoder(w).Encode(map[string]any{"ok": true, "result": map[string]any{"message_id": 1}})
+	case "/bottoken/getUpdates":
+		json.NewEncoder(w).Encode(map[string]any{"ok": true, "result": f.updates})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]any{"ok": false, "error_code": 404, "description": "Not Found"})
+	}
+}
+
+func TestSend(t *testing.T) {
+	api := &fakeAPI{t: t}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	provider := &NotificationProvider{Token: "token", BaseURL: server.URL}
+	if !provider.IsValid() {
+		t.Fatal("provider config is invalid")
+	}
+
+	err := provider.Send(context.Background(), &notification.Notification{
+		Type:        notification.TypeTelegram,
+		ContentType: notification.ContentTypeHTML,
+		To:          []string{"42", "404"},
+		Subject:     "A & B",
+		Body:        "Test",
+	})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != http.StatusBadRequest {
+		t.Fatalf("expected api error for unknown chat, got %v", err)
+	}
+	if len(api.sent) != 1 {
+		t.Fatalf("expected 1 message to be sent, got %d", len(api.sent))
+	}
+	if got, want := api.sent[0]["text"], "<b>A &amp; B</b>\n\nTest"; got != want {
+		t.Errorf("text = %q, want %q", got, want)
+	}
+	if got := api.sent[0]["parse_mode"]; got != ParseModeHTML {
+		t.Errorf("parse_mode = %q, want %q", got, ParseModeHTML)
+	}
+}
+
+func TestGetUpdates(t *testing.T) {
+	api := &fakeAPI{t: t, updates: []Update{
+		{UpdateID: 7, Message: &Message{MessageID: 1, Chat: Chat{ID: 42}, Text: "/start 123456"}},
+	}}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	updates, err := NewClient(server.URL, "token", nil).GetUpdates(context.Background(), 7, time.Second)
+	if err != nil {
+		t.Fatalf("failed to get updates: %v", err)
+	}
+	if len(updates) != 1 || updates[0].Message.Chat.ID != 42 || updates[0].Message.Text != "/start 123456" {
+		t.Fatalf("unexpected updates: %+v", updates)
+	}
+}
+
+func TestTransportErrorHidesToken(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	_, err := NewClient(server.URL, "123:secret", nil).GetUpdates(context.Background(), 0, time.Second)
+	if err == nil {
+		t.Fatal("expected an error from the closed server")
+	}
+	if strings.Contains(err.Error(), "secret") {
+		t.Fatalf("error contains the bot token: %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// Header is the HTTP header carrying the request ID.
+	Header = "X-Request-ID"
+
+	ctxRequestID = "requestIDKey"
+
+	// maxLength matches the request_id column of the audit log.
+	maxLength = 64
+)
+
+// New returns middleware that takes the request ID from the X-Request-ID
+// header or generates a new one, stores it in the context and echoes it back.
+// A header longer than 64 characters or with characters other than letters,
+// digits and "-._:" is replaced with a generated ID.
+func New() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(Header)
+		if !valid(id) {
+			id = generate()
+		}
+
+		c.Set(ctxRequestID, id)
+		c.Header(Header, id)
+
+		c.Next()
+	}
+}
+
+// NewContext returns a copy of ctx carrying the request ID.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxRequestID, id)
+}
+
+// FromContext returns request ID from context.
+func FromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(ctxRequestID).(string); ok {
+		return id
+	}
+	return ""
+}
+
+func valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+func generate() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,51 @@
+package requestid
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		header string
+		keep   bool
+	}{
+		{name: "uuid", header: "0b6c7a43-3f4e-4a1e-9a55-6c1f4f6f8b2a", keep: true},
+		{name: "trace", header: "trace:1.2_3", keep: true},
+		{name: "max length", header: strings.Repeat("a", maxLength), keep: true},
+		{name: "empty", header: ""},
+		{name: "too long", header: strings.Repeat("a", maxLength+1)},
+		{name: "spaces", header: "id with spaces"},
+		{name: "non ascii", header: "запрос"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			r := gin.New()
+			r.Use(New())
+			r.GET("/", func(c *gin.Context) { got = FromContext(c) })
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(Header, tt.header)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if tt.keep && got != tt.header {
+				t.Fatalf("request id = %q, want %q", got, tt.header)
+			}
+			if !tt.keep && (got == tt.header || len(got) != 32) {
+				t.Fatalf("request id = %q, want a generated one", got)
+			}
+			if w.Header().Get(Header) != got {
+				t.Fatalf("echoed request id = %q, want %q", w.Header().Get(Header), got)
+			}
+		})
+	}
+}